@@ -3,10 +3,15 @@ package main
 import (
 	"fmt"
 	"github.com/instinctG/tender/internal/db"
+	"github.com/instinctG/tender/internal/outbox"
 	"github.com/instinctG/tender/internal/server"
 	"github.com/instinctG/tender/internal/service"
+	"github.com/instinctG/tender/internal/storage"
 	"github.com/joho/godotenv"
 	"log"
+	"os"
+	"strconv"
+	"time"
 )
 
 // Run инициализирует и запускает приложение, устанавливает соединение с базой данных,
@@ -25,9 +30,28 @@ func Run() error {
 		return err
 	}
 
-	tenderService := service.NewService(database)
+	blobs, err := storage.NewFromEnv()
+	if err != nil {
+		fmt.Println("failed to set up attachment storage")
+		return err
+	}
+
+	tenderService := service.NewService(database, blobs)
+	if maxSize, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_BYTES"), 10, 64); err == nil && maxSize > 0 {
+		tenderService.MaxAttachmentSize = maxSize
+	}
+	tenderService.StartRevealWorker(time.Minute)
+	if os.Getenv("OUTBOX_LOG") == "true" {
+		tenderService.Sinks = append(tenderService.Sinks, outbox.LogSink{})
+	}
+	tenderService.StartOutboxRelay(2 * time.Second)
+	tenderService.StartEventListener()
+	tenderService.StartWebhookWorker(10 * time.Second)
 
 	httpHandler := server.NewHandler(tenderService)
+	httpHandler.ForRequest = func(requestId string) server.TenderService {
+		return tenderService.WithStore(database.WithRequestId(requestId))
+	}
 	if err = httpHandler.Serve(); err != nil {
 		return err
 	}
@@ -0,0 +1,243 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// attachmentColumns - список колонок вложения в порядке, который ожидает scanAttachment.
+const attachmentColumns = `id, parent_type, parent_id, file_name, content_type, size, sha256, storage_key, created_at`
+
+// scanAttachment сканирует строку, выбранную по attachmentColumns.
+func scanAttachment(row pgx.Row) (*model.Attachment, error) {
+	var attachment model.Attachment
+
+	if err := row.Scan(
+		&attachment.Id,
+		&attachment.ParentType,
+		&attachment.ParentId,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Checksum,
+		&attachment.StorageKey,
+		&attachment.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// checkAttachmentWrite проверяет, что пользователь может прикладывать файлы к родительскому объекту:
+// к тендеру - ответственный организации, пока тендер не закрыт, к предложению - его автор,
+// пока тендер принимает предложения.
+func checkAttachmentWrite(ctx context.Context, q querier, parentType, parentId, username string) error {
+	switch parentType {
+	case "Tender":
+		var status string
+		if err := q.QueryRow(ctx, `SELECT status FROM tender WHERE id = $1`, parentId).Scan(&status); err != nil {
+			if err.Error() == "no rows in result set" {
+				return errors.New("tender not found")
+			}
+			return err
+		}
+
+		isResponsible, err := isTenderResponsible(ctx, q, parentId, username)
+		if err != nil {
+			return err
+		}
+		if !isResponsible {
+			return errors.New("user is not responsible for this organization")
+		}
+		if status == "Closed" || status == "Canceled" {
+			return errors.New("tender is closed")
+		}
+		return nil
+	case "Bid":
+		var tenderId, status string
+		err := q.QueryRow(ctx, `
+            SELECT tender_id, status
+            FROM bid
+            WHERE id = $1 AND author_id = (SELECT id FROM employee WHERE username = $2)`,
+			parentId, username).Scan(&tenderId, &status)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				return errors.New("bid not found")
+			}
+			return err
+		}
+		if status == "Canceled" || status == "Void" {
+			return errors.New("bid is not active")
+		}
+
+		_, err = checkSubmissionOpen(ctx, q, tenderId)
+		return err
+	default:
+		return errors.New("unknown attachment parent")
+	}
+}
+
+// checkAttachmentRead проверяет, что пользователь видит родительский объект: тендер - по правилам
+// checkTenderVisible, предложение - его автор и ответственные за тендер, но до вскрытия
+// запечатанного тендера только автор.
+func checkAttachmentRead(ctx context.Context, q querier, parentType, parentId, username string) error {
+	switch parentType {
+	case "Tender":
+		return checkTenderVisible(ctx, q, parentId, username)
+	case "Bid":
+		var tenderId string
+		var isAuthor, sealed bool
+		err := q.QueryRow(ctx, `
+            SELECT b.tender_id,
+                   b.author_id = (SELECT id FROM employee WHERE username = $2),
+                   t.sealed AND t.revealed_at IS NULL
+            FROM bid b
+            JOIN tender t ON t.id = b.tender_id
+            WHERE b.id = $1`,
+			parentId, username).Scan(&tenderId, &isAuthor, &sealed)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				return errors.New("bid not found")
+			}
+			return err
+		}
+		if isAuthor {
+			return nil
+		}
+
+		isResponsible, err := isTenderResponsible(ctx, q, tenderId, username)
+		if err != nil {
+			return err
+		}
+		if !isResponsible || sealed {
+			return errors.New("bid not found")
+		}
+		return nil
+	default:
+		return errors.New("unknown attachment parent")
+	}
+}
+
+func (d *Database) CheckAttachmentUpload(parentType, parentId string, params model.UploadAttachmentParams) error {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return err
+	}
+	defer conn.Release()
+
+	return checkAttachmentWrite(ctx, conn, parentType, parentId, params.Username)
+}
+
+// CreateAttachment сохраняет метаданные уже загруженного файла. Права проверяются повторно,
+// так как между началом и концом загрузки тендер мог закрыться.
+func (d *Database) CreateAttachment(attachment model.Attachment, params model.UploadAttachmentParams) (*model.Attachment, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = checkAttachmentWrite(ctx, tx, attachment.ParentType, attachment.ParentId, params.Username); err != nil {
+		return nil, err
+	}
+
+	uploaderId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := scanAttachment(tx.QueryRow(ctx, `
+        INSERT INTO attachment (parent_type, parent_id, file_name, content_type, size, sha256, storage_key, uploaded_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING `+attachmentColumns,
+		attachment.ParentType, attachment.ParentId, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.Checksum, attachment.StorageKey, uploaderId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "attachment.upload",
+		EntityType: "attachment", EntityId: created.Id}, nil, created); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (d *Database) GetAttachments(parentType, parentId string, params model.GetAttachmentsParams) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err = checkAttachmentRead(ctx, conn, parentType, parentId, params.Username); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+attachmentColumns+`
+        FROM attachment
+        WHERE parent_type = $1 AND parent_id = $2
+        ORDER BY created_at, id`,
+		parentType, parentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (d *Database) GetAttachment(attachmentId string, params model.GetAttachmentsParams) (*model.Attachment, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	attachment, err := scanAttachment(conn.QueryRow(ctx, `SELECT `+attachmentColumns+` FROM attachment WHERE id = $1`,
+		attachmentId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("attachment not found")
+		}
+		return nil, err
+	}
+
+	if err = checkAttachmentRead(ctx, conn, attachment.ParentType, attachment.ParentId, params.Username); err != nil {
+		return nil, errors.New("attachment not found")
+	}
+
+	return attachment, nil
+}
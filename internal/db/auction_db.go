@@ -0,0 +1,330 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"time"
+)
+
+// auctionColumns - список колонок аукциона в порядке, который ожидает scanAuction.
+const auctionColumns = `a.tender_id,
+	CASE WHEN now() < a.starts_at THEN 'Scheduled' WHEN now() < a.ends_at THEN 'Live' ELSE 'Finished' END,
+	a.starts_at, a.ends_at, a.min_decrement, a.currency, a.extension_window_seconds, a.extension_seconds,
+	a.best_amount, (SELECT count(*) FROM auction_offer o WHERE o.tender_id = a.tender_id)`
+
+// scanAuction сканирует строку, выбранную по auctionColumns.
+func scanAuction(row pgx.Row) (*model.Auction, error) {
+	var auction model.Auction
+	var bestAmount *int64
+
+	if err := row.Scan(
+		&auction.TenderId,
+		&auction.Status,
+		&auction.StartsAt,
+		&auction.EndsAt,
+		&auction.MinDecrement.Amount,
+		&auction.MinDecrement.Currency,
+		&auction.ExtensionWindowSeconds,
+		&auction.ExtensionSeconds,
+		&bestAmount,
+		&auction.OfferCount,
+	); err != nil {
+		return nil, err
+	}
+
+	auction.BestPrice = newMoney(bestAmount, &auction.MinDecrement.Currency)
+
+	return &auction, nil
+}
+
+func (d *Database) ConfigureAuction(tenderId string, params model.ConfigureAuctionParams, body model.ConfigureAuctionJSONBody) (*model.Auction, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isTenderResponsible(ctx, tx, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	var status string
+	var sealed bool
+	err = tx.QueryRow(ctx, `SELECT status, sealed FROM tender WHERE id = $1`, tenderId).Scan(&status, &sealed)
+	if err != nil {
+		return nil, err
+	}
+	if sealed {
+		return nil, errors.New("sealed tenders cannot run as auctions")
+	}
+	if status == "Closed" || status == "Canceled" {
+		return nil, errors.New("tender is closed")
+	}
+
+	before, err := auditSnapshot(ctx, tx, "auction", tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Параметры можно менять только до старта торгов.
+	auction, err := scanAuction(tx.QueryRow(ctx, `
+        WITH a AS (
+            INSERT INTO tender_auction (tender_id, starts_at, ends_at, min_decrement, currency,
+                                        extension_window_seconds, extension_seconds)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)
+            ON CONFLICT (tender_id) DO UPDATE
+            SET starts_at = EXCLUDED.starts_at,
+                ends_at = EXCLUDED.ends_at,
+                min_decrement = EXCLUDED.min_decrement,
+                currency = EXCLUDED.currency,
+                extension_window_seconds = EXCLUDED.extension_window_seconds,
+                extension_seconds = EXCLUDED.extension_seconds
+            WHERE tender_auction.starts_at > now()
+            RETURNING *
+        )
+        SELECT `+auctionColumns+` FROM a`,
+		tenderId, body.StartsAt, body.EndsAt, body.MinDecrement.Amount, body.MinDecrement.Currency,
+		body.ExtensionWindowSeconds, body.ExtensionSeconds))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("auction has already started")
+		}
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "auction.configure",
+		EntityType: "auction", EntityId: tenderId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+func (d *Database) GetAuction(tenderId string) (*model.Auction, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	auction, err := scanAuction(conn.QueryRow(ctx, `SELECT `+auctionColumns+` FROM tender_auction a WHERE a.tender_id = $1`,
+		tenderId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("auction not found")
+		}
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// PlaceAuctionOffer атомарно принимает ставку: блокирует аукцион, передает его состояние
+// в apply, который проверяет ставку по правилам торгов и обновляет состояние, и сохраняет результат.
+// Цена предложения обновляется как при EditBid - с увеличением версии.
+func (d *Database) PlaceAuctionOffer(tenderId string, params model.PlaceAuctionOfferParams, body model.PlaceAuctionOfferJSONBody,
+	apply func(auction *model.Auction, amount int64, now time.Time) error) (*model.Auction, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Тендер блокируется на чтение вместе с предложением, чтобы его не закрыли и не отменили до конца ставки.
+	var bidStatus, tenderStatus string
+	err = tx.QueryRow(ctx, `
+        SELECT b.status, t.status
+        FROM bid b
+        JOIN tender t ON t.id = b.tender_id
+        WHERE b.id = $1 AND b.tender_id = $2
+          AND b.author_id = (SELECT id FROM employee WHERE username = $3)
+        FOR UPDATE OF b FOR SHARE OF t`,
+		body.BidId, tenderId, params.Username).Scan(&bidStatus, &tenderStatus)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("bid not found")
+		}
+		return nil, err
+	}
+	if tenderStatus != "Published" {
+		return nil, errors.New("tender is not open for offers")
+	}
+	// Черновик предложения в торгах не участвует, отозванное - больше не участвует.
+	if bidStatus != "Published" {
+		return nil, errors.New("bid is not published")
+	}
+
+	auction, err := scanAuction(tx.QueryRow(ctx, `
+        SELECT `+auctionColumns+`
+        FROM tender_auction a
+        WHERE a.tender_id = $1
+        FOR UPDATE`,
+		tenderId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("auction not found")
+		}
+		return nil, err
+	}
+	before, err := auditSnapshot(ctx, tx, "auction", tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	var now time.Time
+	if err = tx.QueryRow(ctx, `SELECT now()`).Scan(&now); err != nil {
+		return nil, err
+	}
+
+	if err = apply(auction, body.Amount, now); err != nil {
+		return nil, err
+	}
+
+	price := &model.Money{Amount: body.Amount, Currency: auction.MinDecrement.Currency}
+	if err = checkBidPrice(ctx, tx, tenderId, price); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tender_auction SET ends_at = $2, best_amount = $3 WHERE tender_id = $1`,
+		tenderId, auction.EndsAt, body.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO auction_offer (tender_id, bid_id, amount) VALUES ($1, $2, $3)`,
+		tenderId, body.BidId, body.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE bid
+        SET price_amount = $2, price_currency = $3, version = version + 1
+        WHERE id = $1`,
+		body.BidId, price.Amount, price.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordBidEvent(ctx, tx, "bid.edited", body.BidId, map[string]any{"auctionOffer": true}); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "auction.offer",
+		EntityType: "auction", EntityId: tenderId}, before); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	auction.OfferCount++
+	return auction, nil
+}
+
+func (d *Database) GetAuctionResults(tenderId string, params model.GetAuctionResultsParams) ([]*model.AuctionResult, error) {
+	var results []*model.AuctionResult
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	var finished bool
+	var currency string
+	err = conn.QueryRow(ctx, `SELECT ends_at <= now(), currency FROM tender_auction WHERE tender_id = $1`,
+		tenderId).Scan(&finished, &currency)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("auction not found")
+		}
+		return nil, err
+	}
+	if !finished {
+		return nil, errors.New("auction is still running")
+	}
+
+	// Итоги видят ответственные организации-заказчика и участники торгов.
+	var allowed bool
+	err = conn.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1
+            FROM organization_responsible r
+            JOIN tender t ON t.organization_id = r.organization_id
+            WHERE t.id = $1 AND r.user_id = (SELECT id FROM employee WHERE username = $2)
+        ) OR EXISTS (
+            SELECT 1
+            FROM bid
+            WHERE tender_id = $1 AND author_id = (SELECT id FROM employee WHERE username = $2)
+        )`,
+		tenderId, params.Username).Scan(&allowed)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("user is not allowed to see auction results")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT b.id, b.name, b.author_type, b.author_id, min(o.amount), count(*), max(o.created_at)
+        FROM auction_offer o
+        JOIN bid b ON b.id = o.bid_id
+        WHERE o.tender_id = $1 AND b.status NOT IN ('Canceled', 'Void')
+        GROUP BY b.id
+        ORDER BY min(o.amount) ASC, max(o.created_at) ASC`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result := model.AuctionResult{Rank: int32(len(results) + 1)}
+		if err = rows.Scan(
+			&result.BidId,
+			&result.BidName,
+			&result.AuthorType,
+			&result.AuthorId,
+			&result.BestPrice.Amount,
+			&result.OfferCount,
+			&result.LastOfferAt,
+		); err != nil {
+			return nil, err
+		}
+		result.BestPrice.Currency = currency
+		results = append(results, &result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+	"time"
+)
+
+func TestPlaceAuctionOfferRequiresPublishedTenderAndBid(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	testOrganization(t, d, "Supplier", supplierId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	bid := testBid(t, d, tender.Id, "User", supplierId)
+
+	_, err := d.ConfigureAuction(tender.Id, model.ConfigureAuctionParams{Username: "creator"}, model.ConfigureAuctionJSONBody{
+		StartsAt:     time.Now().Add(time.Hour),
+		EndsAt:       time.Now().Add(2 * time.Hour),
+		MinDecrement: model.Money{Amount: 100, Currency: "RUB"},
+	})
+	if err != nil {
+		t.Fatalf("configure auction: %v", err)
+	}
+
+	// Правила торгов проверяются отдельно, здесь важна только проверка тендера.
+	accept := func(*model.Auction, int64, time.Time) error { return nil }
+	offer := model.PlaceAuctionOfferJSONBody{BidId: bid.Id, Amount: 5000}
+	params := model.PlaceAuctionOfferParams{Username: "supplier"}
+
+	if _, err = d.PlaceAuctionOffer(tender.Id, params, offer, accept); err == nil {
+		t.Fatal("offer on a draft bid was accepted")
+	}
+
+	_, err = d.UpdateBidStatus(bid.Id, model.UpdateBidStatusParams{Status: "Published", Username: "supplier"})
+	if err != nil {
+		t.Fatalf("publish bid: %v", err)
+	}
+	if _, err = d.PlaceAuctionOffer(tender.Id, params, offer, accept); err != nil {
+		t.Fatalf("offer on a published tender: %v", err)
+	}
+
+	if _, err = d.UpdateTenderStatus(tender.Id, model.UpdateTenderStatusParams{Status: "Closed", Username: "creator"}); err != nil {
+		t.Fatalf("close tender: %v", err)
+	}
+	offer.Amount = 4000
+	if _, err = d.PlaceAuctionOffer(tender.Id, params, offer, accept); err == nil {
+		t.Fatal("offer on a closed tender was accepted")
+	}
+	if count := testCount(t, d, `SELECT count(*) FROM auction_offer WHERE tender_id = $1`, tender.Id); count != 1 {
+		t.Fatalf("auction offers = %d, want 1", count)
+	}
+}
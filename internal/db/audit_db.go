@@ -0,0 +1,209 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+	"strings"
+)
+
+// auditSnapshots - запросы состояния сущностей для журнала аудита. Зашифрованное содержимое предложений,
+// автор вопроса, секрет вебхука и служебный поисковый индекс в журнал не попадают. Строка сущности
+// блокируется до конца транзакции, чтобы состояния до и после описывали именно это изменение.
+var auditSnapshots = map[string]string{
+	"tender":   `SELECT to_jsonb(t) - 'search_vector' FROM tender t WHERE t.id = $1 FOR UPDATE`,
+	"bid":      `SELECT to_jsonb(b) - 'sealed_payload' FROM bid b WHERE b.id = $1 FOR UPDATE`,
+	"lot":      `SELECT to_jsonb(l) FROM tender_lot l WHERE l.id = $1 FOR UPDATE`,
+	"question": `SELECT to_jsonb(q) - 'asker_id' FROM tender_question q WHERE q.id = $1 FOR UPDATE`,
+	"award":    `SELECT to_jsonb(a) FROM tender_award a WHERE a.id = $1 FOR UPDATE`,
+	"auction":  `SELECT to_jsonb(a) FROM tender_auction a WHERE a.tender_id = $1 FOR UPDATE`,
+	"webhook":  `SELECT to_jsonb(w) - 'secret' FROM webhook_endpoint w WHERE w.id = $1 FOR UPDATE`,
+}
+
+// WithRequestId возвращает копию базы, записи аудита которой помечаются идентификатором запроса requestId.
+func (d *Database) WithRequestId(requestId string) *Database {
+	scoped := *d
+	scoped.requestId = requestId
+	return &scoped
+}
+
+// auditSnapshot возвращает состояние сущности в транзакции q или nil, если сущности нет
+// или ее состояние не снимается.
+func auditSnapshot(ctx context.Context, q querier, entityType, entityId string) (json.RawMessage, error) {
+	query, ok := auditSnapshots[entityType]
+	if !ok || entityId == "" {
+		return nil, nil
+	}
+
+	var snapshot json.RawMessage
+	if err := q.QueryRow(ctx, query, entityId).Scan(&snapshot); err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// auditChanges сравнивает состояния сущности до и после вызова по полям верхнего уровня.
+func auditChanges(before, after any) (map[string]model.AuditChange, error) {
+	fields := func(state any) (map[string]json.RawMessage, error) {
+		fields := map[string]json.RawMessage{}
+		if raw, ok := state.(json.RawMessage); ok && len(raw) == 0 {
+			return fields, nil
+		}
+		data, err := json.Marshal(state)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		return fields, nil
+	}
+
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]model.AuditChange{}
+	for name, value := range beforeFields {
+		if !bytes.Equal(value, afterFields[name]) {
+			changes[name] = model.AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = model.AuditChange{After: value}
+		}
+	}
+
+	return changes, nil
+}
+
+// writeAudit добавляет запись с разницей состояний before и after в журнал аудита в транзакции
+// изменения q: если запись не сохранилась, изменение откатывается. Вместе с автором сохраняются
+// организации, за которые он отвечает в момент вызова: по ним запись видна ответственным этих организаций.
+func (d *Database) writeAudit(ctx context.Context, q querier, record model.AuditRecord, before, after any) error {
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+        WITH actor AS (
+            SELECT e.id, e.username
+            FROM employee e
+            WHERE e.username = NULLIF($2, '') OR e.id = NULLIF($3, '')::uuid
+            LIMIT 1
+        )
+        INSERT INTO audit_log (request_id, actor_id, actor_username, actor_organization_ids, action,
+                               entity_type, entity_id, changes)
+        SELECT $1,
+               a.id,
+               COALESCE(a.username, $2),
+               ARRAY(SELECT r.organization_id FROM organization_responsible r WHERE r.user_id = a.id),
+               $4, $5, $6, $7
+        FROM (SELECT 1) one
+        LEFT JOIN actor a ON true`,
+		d.requestId, record.Actor, record.ActorId, record.Action, record.EntityType, record.EntityId, data)
+	return err
+}
+
+// auditEntity снимает состояние сущности записи после изменения и пишет запись с разницей от before.
+func (d *Database) auditEntity(ctx context.Context, q querier, record model.AuditRecord, before json.RawMessage) error {
+	after, err := auditSnapshot(ctx, q, record.EntityType, record.EntityId)
+	if err != nil {
+		return err
+	}
+	return d.writeAudit(ctx, q, record, before, after)
+}
+
+// GetAuditLog возвращает записи журнала о действиях ответственных организации, новые сверху.
+// Журнал доступен только ответственным этой организации.
+func (d *Database) GetAuditLog(params model.GetAuditLogParams) ([]*model.AuditEntry, error) {
+	entries := []*model.AuditEntry{}
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isOrganizationResponsible(ctx, conn, params.OrganizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	var args queryArgs
+	conditions := []string{args.add(params.OrganizationId) + "::uuid = ANY(actor_organization_ids)"}
+	if params.EntityType != "" {
+		conditions = append(conditions, "entity_type = "+args.add(params.EntityType))
+	}
+	if params.EntityId != "" {
+		conditions = append(conditions, "entity_id = "+args.add(params.EntityId))
+	}
+	if params.Actor != "" {
+		conditions = append(conditions, "actor_username = "+args.add(params.Actor))
+	}
+	if params.From != nil {
+		conditions = append(conditions, "created_at >= "+args.add(*params.From))
+	}
+	if params.To != nil {
+		conditions = append(conditions, "created_at < "+args.add(*params.To))
+	}
+
+	query := fmt.Sprintf(`
+        SELECT id, request_id, actor_username, action, entity_type, entity_id, changes, created_at
+        FROM audit_log
+        WHERE %s
+        ORDER BY created_at DESC, id DESC
+        LIMIT %s OFFSET %s`,
+		strings.Join(conditions, " AND "), args.add(params.Limit), args.add(params.Offset))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry model.AuditEntry
+		if err = rows.Scan(
+			&entry.Id,
+			&entry.RequestId,
+			&entry.Actor,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityId,
+			&entry.Changes,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
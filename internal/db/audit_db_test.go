@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+	"time"
+)
+
+// testAuditLog возвращает записи журнала о сущности entityId, видимые ответственному username организации.
+func testAuditLog(t *testing.T, d *Database, organizationId, username, entityId string) []*model.AuditEntry {
+	t.Helper()
+	entries, err := d.GetAuditLog(model.GetAuditLogParams{
+		Username:       username,
+		OrganizationId: organizationId,
+		EntityId:       entityId,
+		Limit:          50,
+	})
+	if err != nil {
+		t.Fatalf("get audit log: %v", err)
+	}
+	return entries
+}
+
+func TestAuditRecordedWithMutation(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, false)
+
+	_, err := d.WithRequestId("request-1").EditTender(tender.Id, model.EditTenderParams{Username: "creator"},
+		model.EditTenderJSONBody{Name: "Renamed"})
+	if err != nil {
+		t.Fatalf("edit tender: %v", err)
+	}
+
+	entries := testAuditLog(t, d, organizationId, "creator", tender.Id)
+	if len(entries) != 2 || entries[0].Action != "tender.edit" || entries[1].Action != "tender.create" {
+		t.Fatalf("audit entries = %+v, want tender.edit after tender.create", entries)
+	}
+	edit := entries[0]
+	if edit.RequestId != "request-1" || edit.Actor != "creator" {
+		t.Fatalf("edit entry = %+v", edit)
+	}
+	if name := edit.Changes["name"]; string(name.Before) != `"Tender"` || string(name.After) != `"Renamed"` {
+		t.Fatalf("name change = %s -> %s", name.Before, name.After)
+	}
+	if _, ok := edit.Changes["description"]; ok {
+		t.Fatal("unchanged field is recorded as a change")
+	}
+	if entries[1].RequestId != "" {
+		t.Fatalf("request id of a call outside a request = %q", entries[1].RequestId)
+	}
+}
+
+func TestAuditFailureRollsBackMutation(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	testExec(t, d, `ALTER TABLE audit_log ADD CONSTRAINT audit_log_rejected CHECK (action NOT IN ('tender.edit', 'tender.watch'))`)
+
+	_, err := d.EditTender(tender.Id, model.EditTenderParams{Username: "creator"}, model.EditTenderJSONBody{Name: "Renamed"})
+	if err == nil {
+		t.Fatal("edit succeeded without an audit record")
+	}
+	if count := testCount(t, d, `SELECT count(*) FROM tender WHERE id = $1 AND name = 'Tender' AND version = $2`,
+		tender.Id, tender.Version); count != 1 {
+		t.Fatal("tender edit was saved without an audit record")
+	}
+	if count := testCount(t, d, `SELECT count(*) FROM outbox_event WHERE event_type = 'tender.edited'`); count != 0 {
+		t.Fatal("outbox event was recorded without an audit record")
+	}
+
+	if _, err = d.WatchTender(tender.Id, model.WatchTenderParams{Username: "creator"}); err == nil {
+		t.Fatal("watch succeeded without an audit record")
+	}
+	if count := testCount(t, d, `SELECT count(*) FROM tender_watch WHERE tender_id = $1`, tender.Id); count != 0 {
+		t.Fatal("watch was saved without an audit record")
+	}
+}
+
+func TestAuditSnapshotWaitsForConcurrentChange(t *testing.T) {
+	d := testDatabase(t)
+	ctx := context.Background()
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, false)
+
+	// Другая транзакция меняет тендер: правка ждет ее, и состояние "до" включает ее изменение.
+	busy, err := d.Client.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer busy.Rollback(ctx)
+	if _, err = busy.Exec(ctx, `UPDATE tender SET description = 'Concurrent' WHERE id = $1`, tender.Id); err != nil {
+		t.Fatalf("concurrent update: %v", err)
+	}
+
+	edited := make(chan error, 1)
+	go func() {
+		_, err := d.EditTender(tender.Id, model.EditTenderParams{Username: "creator"}, model.EditTenderJSONBody{Name: "Renamed"})
+		edited <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for testCount(t, d, `SELECT count(*) FROM pg_stat_activity WHERE wait_event_type = 'Lock'`) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("edit did not wait for the concurrent change")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err = busy.Commit(ctx); err != nil {
+		t.Fatalf("commit concurrent update: %v", err)
+	}
+	if err = <-edited; err != nil {
+		t.Fatalf("edit tender: %v", err)
+	}
+
+	entries := testAuditLog(t, d, organizationId, "creator", tender.Id)
+	if len(entries) == 0 || entries[0].Action != "tender.edit" {
+		t.Fatalf("audit entries = %+v", entries)
+	}
+	if _, ok := entries[0].Changes["description"]; ok {
+		t.Fatalf("concurrent change is attributed to the edit: %+v", entries[0].Changes)
+	}
+	if name := entries[0].Changes["name"]; string(name.After) != `"Renamed"` {
+		t.Fatalf("name change = %s -> %s", name.Before, name.After)
+	}
+}
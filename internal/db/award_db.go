@@ -0,0 +1,341 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// awardSelect выбирает присуждения вместе с названиями тендера, лота, предложения и сторон в порядке,
+// который ожидает scanAward. Условия дописываются к запросу после WHERE.
+const awardSelect = `
+        SELECT a.id, a.tender_id, t.name, a.lot_id, l.name, a.bid_id, b.name, a.bid_version,
+               a.price_amount, a.price_currency, a.buyer_organization_id, o.name,
+               a.supplier_type, a.supplier_id,
+               COALESCE((SELECT username FROM employee WHERE id = a.supplier_id),
+                        (SELECT name FROM organization WHERE id = a.supplier_id), ''),
+               a.rationale, a.share_kind, a.share::float8, a.awarded_at,
+               a.canceled_at, a.cancel_reason, a.delivered_at, a.delivered_on_time
+        FROM tender_award a
+        JOIN tender t ON t.id = a.tender_id
+        JOIN bid b ON b.id = a.bid_id
+        JOIN organization o ON o.id = a.buyer_organization_id
+        LEFT JOIN tender_lot l ON l.id = a.lot_id
+        WHERE `
+
+func scanAward(row pgx.Row) (*model.TenderAward, error) {
+	var award model.TenderAward
+	var lotId, lotName, priceCurrency, rationale, shareKind, cancelReason *string
+	var priceAmount *int64
+	var share *float64
+
+	if err := row.Scan(
+		&award.Id,
+		&award.TenderId,
+		&award.TenderName,
+		&lotId,
+		&lotName,
+		&award.BidId,
+		&award.BidName,
+		&award.BidVersion,
+		&priceAmount,
+		&priceCurrency,
+		&award.BuyerOrganizationId,
+		&award.BuyerOrganizationName,
+		&award.SupplierType,
+		&award.SupplierId,
+		&award.SupplierName,
+		&rationale,
+		&shareKind,
+		&share,
+		&award.AwardedAt,
+		&award.CanceledAt,
+		&cancelReason,
+		&award.DeliveredAt,
+		&award.DeliveredOnTime,
+	); err != nil {
+		return nil, err
+	}
+
+	if lotId != nil {
+		award.LotId = *lotId
+	}
+	if lotName != nil {
+		award.LotName = *lotName
+	}
+	if rationale != nil {
+		award.Rationale = *rationale
+	}
+	if cancelReason != nil {
+		award.CancelReason = *cancelReason
+	}
+	if shareKind != nil && share != nil {
+		award.ShareKind, award.Share = *shareKind, *share
+	}
+	award.Price = newMoney(priceAmount, priceCurrency)
+
+	return &award, nil
+}
+
+// insertAward фиксирует присуждение тендера (или лота lotId) предложению bidId в его текущей версии и цене.
+// Для долевого присуждения share задает долю вида shareKind, для обычного shareKind пуст.
+func insertAward(ctx context.Context, q querier, bidId, lotId, rationale, username, shareKind string, share float64) error {
+	_, err := q.Exec(ctx, `
+        INSERT INTO tender_award (tender_id, lot_id, bid_id, bid_version, price_amount, price_currency,
+                                  buyer_organization_id, supplier_type, supplier_id, rationale, awarded_by,
+                                  share_kind, share)
+        SELECT t.id, NULLIF($2, '')::uuid, b.id, b.version, b.price_amount, b.price_currency,
+               t.organization_id, b.author_type, b.author_id, NULLIF($3, ''),
+               (SELECT id FROM employee WHERE username = $4),
+               NULLIF($5::text, '')::award_share_kind, CASE WHEN $5::text = '' THEN NULL ELSE $6::numeric END
+        FROM bid b
+        JOIN tender t ON t.id = b.tender_id
+        WHERE b.id = $1`,
+		bidId, lotId, rationale, username, shareKind, share)
+	if err != nil {
+		return err
+	}
+	return bumpReputation(ctx, q, bidId, reputationDelta{awards: 1})
+}
+
+// GetOrganizationAwards возвращает присуждения, где организация выступает заказчиком или поставщиком.
+// Поставщик - всегда сотрудник-автор предложения, поэтому организация-поставщик определяется
+// через его ответственность при любом supplier_type.
+func (d *Database) GetOrganizationAwards(organizationId string, params model.GetOrganizationAwardsParams) ([]*model.TenderAward, error) {
+	var awards []*model.TenderAward
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isOrganizationResponsible(ctx, conn, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	rows, err := conn.Query(ctx, awardSelect+`
+            a.buyer_organization_id = $1
+            OR EXISTS (
+                SELECT 1 FROM organization_responsible r WHERE r.user_id = a.supplier_id AND r.organization_id = $1
+            )
+        ORDER BY a.awarded_at DESC, a.id
+        LIMIT $2 OFFSET $3`,
+		organizationId, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		award, err := scanAward(rows)
+		if err != nil {
+			return nil, err
+		}
+		awards = append(awards, award)
+	}
+
+	return awards, rows.Err()
+}
+
+// GetTenderAward возвращает присуждение ответственным заказчика, сотруднику-поставщику и, если предложение
+// подано от имени организации, ответственным организаций, за которые он отвечает.
+func (d *Database) GetTenderAward(awardId string, params model.GetTenderAwardParams) (*model.TenderAward, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	award, err := scanAward(conn.QueryRow(ctx, awardSelect+`
+            a.id = $1
+            AND (a.supplier_id = (SELECT id FROM employee WHERE username = $2)
+                 OR EXISTS (
+                     SELECT 1
+                     FROM organization_responsible r
+                     WHERE r.user_id = (SELECT id FROM employee WHERE username = $2)
+                       AND (r.organization_id = a.buyer_organization_id
+                            OR (a.supplier_type = 'Organization' AND r.organization_id IN (
+                                SELECT s.organization_id FROM organization_responsible s WHERE s.user_id = a.supplier_id
+                            )))
+                 ))`,
+		awardId, params.Username))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("award not found")
+		}
+		return nil, err
+	}
+
+	return award, nil
+}
+
+// lockBuyerAward блокирует присуждение, проверяя, что пользователь отвечает за организацию-заказчика,
+// и сообщает предложение, тендер и состояние присуждения.
+func lockBuyerAward(ctx context.Context, tx pgx.Tx, awardId, username string) (string, string, bool, bool, error) {
+	var bidId, tenderId string
+	var canceled, delivered bool
+	err := tx.QueryRow(ctx, `
+        SELECT a.bid_id, a.tender_id, a.canceled_at IS NOT NULL, a.delivered_at IS NOT NULL
+        FROM tender_award a
+        WHERE a.id = $1
+          AND EXISTS (
+              SELECT 1
+              FROM organization_responsible r
+              WHERE r.organization_id = a.buyer_organization_id
+                AND r.user_id = (SELECT id FROM employee WHERE username = $2)
+          )
+        FOR UPDATE OF a`,
+		awardId, username).Scan(&bidId, &tenderId, &canceled, &delivered)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return "", "", false, false, errors.New("award not found")
+		}
+		return "", "", false, false, err
+	}
+	return bidId, tenderId, canceled, delivered, nil
+}
+
+// CancelTenderAward отменяет присуждение по инициативе заказчика. Отмена учитывается в репутации поставщика.
+func (d *Database) CancelTenderAward(awardId string, params model.CancelTenderAwardParams,
+	body model.CancelTenderAwardJSONBody) (*model.TenderAward, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	bidId, tenderId, canceled, delivered, err := lockBuyerAward(ctx, tx, awardId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if canceled {
+		return nil, errors.New("award is already canceled")
+	}
+	if delivered {
+		return nil, errors.New("award is already delivered")
+	}
+	before, err := auditSnapshot(ctx, tx, "award", awardId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tender_award SET canceled_at = CURRENT_TIMESTAMP, cancel_reason = $2 WHERE id = $1`,
+		awardId, body.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = bumpReputation(ctx, tx, bidId, reputationDelta{awardsCanceled: 1}); err != nil {
+		return nil, err
+	}
+
+	err = notifyBidAuthors(ctx, tx, tenderId, []string{bidId}, "AwardCanceled", map[string]string{"awardId": awardId})
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordBidEvent(ctx, tx, "bid.award_canceled", bidId, map[string]any{"awardId": awardId, "reason": body.Reason})
+	if err != nil {
+		return nil, err
+	}
+
+	award, err := scanAward(tx.QueryRow(ctx, awardSelect+`a.id = $1`, awardId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "award.cancel", EntityType: "award",
+		EntityId: awardId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return award, nil
+}
+
+// RecordAwardDelivery фиксирует исполнение присуждения и то, уложился ли поставщик в срок.
+func (d *Database) RecordAwardDelivery(awardId string, params model.RecordAwardDeliveryParams,
+	body model.RecordAwardDeliveryJSONBody) (*model.TenderAward, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	bidId, _, canceled, delivered, err := lockBuyerAward(ctx, tx, awardId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if canceled {
+		return nil, errors.New("award is canceled")
+	}
+	if delivered {
+		return nil, errors.New("delivery is already recorded")
+	}
+	before, err := auditSnapshot(ctx, tx, "award", awardId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tender_award SET delivered_at = CURRENT_TIMESTAMP, delivered_on_time = $2 WHERE id = $1`,
+		awardId, *body.OnTime)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := reputationDelta{deliveries: 1}
+	if *body.OnTime {
+		delta.deliveriesOnTime = 1
+	}
+	if err = bumpReputation(ctx, tx, bidId, delta); err != nil {
+		return nil, err
+	}
+
+	err = recordBidEvent(ctx, tx, "bid.award_delivered", bidId, map[string]any{"awardId": awardId, "onTime": *body.OnTime})
+	if err != nil {
+		return nil, err
+	}
+
+	award, err := scanAward(tx.QueryRow(ctx, awardSelect+`a.id = $1`, awardId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "award.delivery", EntityType: "award",
+		EntityId: awardId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return award, nil
+}
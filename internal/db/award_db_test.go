@@ -0,0 +1,42 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestOrganizationBidAwardVisibleToSupplierOrganization(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	colleagueId := testEmployee(t, d, "colleague")
+	outsiderId := testEmployee(t, d, "outsider")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	supplierOrganizationId := testOrganization(t, d, "Supplier", supplierId, colleagueId)
+	testOrganization(t, d, "Other", outsiderId)
+
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	bid := testBid(t, d, tender.Id, "Organization", supplierId)
+	_, err := d.SubmitBidDecision(bid.Id, model.SubmitBidDecisionParams{Decision: "Accepted", Username: "creator"})
+	if err != nil {
+		t.Fatalf("accept bid: %v", err)
+	}
+
+	awards, err := d.GetOrganizationAwards(supplierOrganizationId,
+		model.GetOrganizationAwardsParams{Username: "colleague", Limit: 10})
+	if err != nil {
+		t.Fatalf("get organization awards: %v", err)
+	}
+	if len(awards) != 1 || awards[0].BidId != bid.Id {
+		t.Fatalf("awards of the supplier organization = %+v, want the accepted bid", awards)
+	}
+
+	for _, username := range []string{"supplier", "colleague", "creator"} {
+		if _, err = d.GetTenderAward(awards[0].Id, model.GetTenderAwardParams{Username: username}); err != nil {
+			t.Fatalf("award is hidden from %s: %v", username, err)
+		}
+	}
+	if _, err = d.GetTenderAward(awards[0].Id, model.GetTenderAwardParams{Username: "outsider"}); err == nil {
+		t.Fatal("award is visible to an outsider")
+	}
+}
@@ -0,0 +1,388 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+)
+
+// getTenderAwardSet возвращает набор присуждений тендера вместе с долями.
+func getTenderAwardSet(ctx context.Context, q querier, tenderId string) (*model.TenderAwardSet, error) {
+	awardSet := model.TenderAwardSet{TenderId: tenderId, Shares: []*model.AwardShare{}}
+	var totalQuantity *int64
+	err := q.QueryRow(ctx, `
+        SELECT kind, total_quantity, created_at, finalized_at
+        FROM tender_award_set
+        WHERE tender_id = $1`,
+		tenderId).Scan(&awardSet.Kind, &totalQuantity, &awardSet.CreatedAt, &awardSet.FinalizedAt)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("award set not found")
+		}
+		return nil, err
+	}
+	if totalQuantity != nil {
+		awardSet.TotalQuantity = *totalQuantity
+	}
+	awardSet.Status = "Draft"
+	if awardSet.FinalizedAt != nil {
+		awardSet.Status = "Finalized"
+	}
+
+	rows, err := q.Query(ctx, `
+        SELECT COALESCE(lot_id::text, ''), bid_id, share::float8
+        FROM tender_award_share
+        WHERE tender_id = $1
+        ORDER BY lot_id NULLS FIRST, share DESC, bid_id`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var share model.AwardShare
+		if err = rows.Scan(&share.LotId, &share.BidId, &share.Share); err != nil {
+			return nil, err
+		}
+		awardSet.Shares = append(awardSet.Shares, &share)
+	}
+
+	return &awardSet, rows.Err()
+}
+
+// checkAwardShares проверяет, что доли относятся к действующим предложениям тендера без решения, а для
+// многолотового тендера - что доли покрывают все открытые лоты и каждое предложение нацелено на свой лот.
+// После раунда BAFO в набор могут входить только предложения из короткого списка.
+func checkAwardShares(ctx context.Context, q querier, tenderId string, shares []model.AwardShare) error {
+	var status string
+	var stillSealed, bafoOpened, bafoOpen bool
+	err := q.QueryRow(ctx, `
+        SELECT status, sealed AND revealed_at IS NULL, bafo_opened_at IS NOT NULL, COALESCE(bafo_deadline > now(), false)
+        FROM tender
+        WHERE id = $1`,
+		tenderId).Scan(&status, &stillSealed, &bafoOpened, &bafoOpen)
+	if err != nil {
+		return err
+	}
+	if status != "Published" {
+		return errors.New("tender is not accepting decisions")
+	}
+	if stillSealed {
+		return errors.New("tender bids are still sealed")
+	}
+	if bafoOpen {
+		return errors.New("best and final offer round is still open")
+	}
+
+	var openLots int
+	err = q.QueryRow(ctx, `SELECT count(*) FROM tender_lot WHERE tender_id = $1 AND status = 'Open'`, tenderId).Scan(&openLots)
+	if err != nil {
+		return err
+	}
+
+	lots := make(map[string]bool)
+	for _, share := range shares {
+		if (openLots == 0) != (share.LotId == "") {
+			return errors.New("lot id must be set for every share of a multi-lot tender and only for it")
+		}
+
+		var eligible bool
+		err = q.QueryRow(ctx, `
+            SELECT EXISTS (
+                SELECT 1
+                FROM bid b
+                WHERE b.id = $2
+                  AND b.tender_id = $1
+                  AND b.status IN ('Created', 'Published')
+                  AND ($3 <> '' OR b.decision IS NULL)
+                  AND (NOT $4 OR EXISTS (SELECT 1 FROM tender_shortlist s WHERE s.tender_id = $1 AND s.bid_id = b.id))
+                  AND ($3 = '' OR EXISTS (
+                      SELECT 1
+                      FROM bid_lot bl
+                      JOIN tender_lot l ON l.id = bl.lot_id
+                      WHERE bl.bid_id = b.id AND bl.lot_id::text = $3 AND bl.decision IS NULL AND l.status = 'Open'
+                  ))
+                  AND NOT EXISTS (SELECT 1 FROM bid_negotiation_round n WHERE n.bid_id = b.id AND n.status = 'Open')
+            )`,
+			tenderId, share.BidId, share.LotId, bafoOpened).Scan(&eligible)
+		if err != nil {
+			return err
+		}
+		if !eligible {
+			return errors.New("award set may contain only eligible bids of this tender")
+		}
+		lots[share.LotId] = true
+	}
+
+	if openLots > 0 && len(lots) != openLots {
+		return errors.New("award set must cover every open lot")
+	}
+
+	return nil
+}
+
+// SetTenderAwardSet создает или заменяет черновик набора долевых присуждений.
+func (d *Database) SetTenderAwardSet(tenderId string, params model.SetTenderAwardSetParams,
+	body model.SetTenderAwardSetJSONBody) (*model.TenderAwardSet, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	creatorId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `SELECT 1 FROM tender WHERE id = $1 FOR UPDATE`, tenderId); err != nil {
+		return nil, err
+	}
+
+	var finalized bool
+	err = tx.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM tender_award_set WHERE tender_id = $1 AND finalized_at IS NOT NULL)`,
+		tenderId).Scan(&finalized)
+	if err != nil {
+		return nil, err
+	}
+	if finalized {
+		return nil, errors.New("award set is already finalized")
+	}
+
+	if err = checkAwardShares(ctx, tx, tenderId, body.Shares); err != nil {
+		return nil, err
+	}
+
+	var totalQuantity *int64
+	if body.Kind == "Quantity" {
+		totalQuantity = &body.TotalQuantity
+	}
+	_, err = tx.Exec(ctx, `
+        INSERT INTO tender_award_set (tender_id, kind, total_quantity, created_by)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (tender_id) DO UPDATE
+        SET kind = EXCLUDED.kind, total_quantity = EXCLUDED.total_quantity,
+            created_by = EXCLUDED.created_by, created_at = CURRENT_TIMESTAMP`,
+		tenderId, body.Kind, totalQuantity, creatorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM tender_award_share WHERE tender_id = $1`, tenderId); err != nil {
+		return nil, err
+	}
+	for _, share := range body.Shares {
+		_, err = tx.Exec(ctx, `
+            INSERT INTO tender_award_share (tender_id, lot_id, bid_id, share)
+            VALUES ($1, NULLIF($2, '')::uuid, $3, $4)`,
+			tenderId, share.LotId, share.BidId, share.Share)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	awardSet, err := getTenderAwardSet(ctx, tx, tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.award_set",
+		EntityType: "tender", EntityId: tenderId}, nil, awardSet); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return awardSet, nil
+}
+
+func (d *Database) GetTenderAwardSet(tenderId string, params model.GetTenderAwardSetParams) (*model.TenderAwardSet, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	return getTenderAwardSet(ctx, conn, tenderId)
+}
+
+// FinalizeTenderAwardSet вводит набор присуждений в силу: предложения из набора принимаются, по каждой доле
+// создается запись о присуждении, лоты присуждаются предложению с наибольшей долей, и тендер закрывается.
+func (d *Database) FinalizeTenderAwardSet(tenderId string, params model.FinalizeTenderAwardSetParams) (*model.TenderAwardSet, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := auditSnapshot(ctx, tx, "tender", tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	awardSet, err := getTenderAwardSet(ctx, tx, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if awardSet.Status == "Finalized" {
+		return nil, errors.New("award set is already finalized")
+	}
+
+	// Условия могли измениться с момента сохранения черновика, поэтому доли проверяются заново.
+	shares := make([]model.AwardShare, len(awardSet.Shares))
+	var bidIds []string
+	for i, share := range awardSet.Shares {
+		shares[i] = *share
+		bidIds = append(bidIds, share.BidId)
+	}
+	if err = checkAwardShares(ctx, tx, tenderId, shares); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tender SET evaluation_locked_at = COALESCE(evaluation_locked_at, now()) WHERE id = $1`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, share := range shares {
+		if share.LotId != "" {
+			_, err = tx.Exec(ctx, `UPDATE bid_lot SET decision = 'Accepted' WHERE bid_id = $1 AND lot_id = $2`,
+				share.BidId, share.LotId)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err = insertAward(ctx, tx, share.BidId, share.LotId, params.Rationale, params.Username,
+			awardSet.Kind, share.Share); err != nil {
+			return nil, err
+		}
+	}
+
+	// Доли внутри лота упорядочены по убыванию, первая из них - основной поставщик лота.
+	_, err = tx.Exec(ctx, `
+        UPDATE tender_lot l
+        SET status = 'Awarded',
+            awarded_bid_id = (
+                SELECT s.bid_id
+                FROM tender_award_share s
+                WHERE s.tender_id = l.tender_id AND s.lot_id = l.id
+                ORDER BY s.share DESC, s.bid_id
+                LIMIT 1
+            )
+        WHERE l.tender_id = $1
+          AND EXISTS (SELECT 1 FROM tender_award_share s WHERE s.tender_id = l.tender_id AND s.lot_id = l.id)`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	previousDecisions := make(map[string]string)
+	for _, bidId := range bidIds {
+		if _, ok := previousDecisions[bidId]; ok {
+			continue
+		}
+		if previousDecisions[bidId], err = bidDecision(ctx, tx, bidId); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE bid SET decision = 'Accepted' WHERE id = ANY($1::uuid[])`, bidIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for bidId, previous := range previousDecisions {
+		if err = bumpReputation(ctx, tx, bidId, decisionDelta(previous, "Accepted")); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE tender SET status = 'Closed' WHERE id = $1`, tenderId); err != nil {
+		return nil, err
+	}
+
+	recorded := make(map[string]bool)
+	for _, bidId := range bidIds {
+		if recorded[bidId] {
+			continue
+		}
+		recorded[bidId] = true
+		if err = recordBidEvent(ctx, tx, "bid.decided", bidId, nil); err != nil {
+			return nil, err
+		}
+	}
+	if err = recordTenderEvent(ctx, tx, "tender.status_changed", tenderId, nil); err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE tender_award_set SET finalized_at = CURRENT_TIMESTAMP WHERE tender_id = $1`,
+		tenderId); err != nil {
+		return nil, err
+	}
+
+	if err = notifyBidAuthors(ctx, tx, tenderId, bidIds, "TenderAwarded", map[string]string{"kind": awardSet.Kind}); err != nil {
+		return nil, err
+	}
+
+	if awardSet, err = getTenderAwardSet(ctx, tx, tenderId); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.award_set.finalize",
+		EntityType: "tender", EntityId: tenderId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return awardSet, nil
+}
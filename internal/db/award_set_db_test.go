@@ -0,0 +1,66 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestTenderAwardSetMustCoverEveryOpenLot(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, false)
+
+	var lotIds []string
+	for _, name := range []string{"North", "South"} {
+		lot, err := d.CreateTenderLot(tender.Id, model.CreateTenderLotParams{Username: "creator"},
+			model.CreateTenderLotJSONBody{Name: name, ServiceType: "Delivery"})
+		if err != nil {
+			t.Fatalf("create lot %s: %v", name, err)
+		}
+		lotIds = append(lotIds, lot.Id)
+	}
+	if _, err := d.UpdateTenderStatus(tender.Id, model.UpdateTenderStatusParams{Status: "Published", Username: "creator"}); err != nil {
+		t.Fatalf("publish tender: %v", err)
+	}
+
+	bid := func(username string, lotIds ...string) *model.Bid {
+		t.Helper()
+		authorId := testEmployee(t, d, username)
+		testOrganization(t, d, username+" LLC", authorId)
+		created, err := d.CreateBid(model.CreateBidJSONBody{
+			Name: "Bid", TenderId: tender.Id, AuthorType: "User", AuthorId: authorId, LotIds: lotIds,
+		})
+		if err != nil {
+			t.Fatalf("create bid: %v", err)
+		}
+		return created
+	}
+	both := bid("alice", lotIds...)
+	north := bid("bob", lotIds[0])
+
+	params := model.SetTenderAwardSetParams{Username: "creator"}
+	partial := model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+		{LotId: lotIds[0], BidId: both.Id, Share: 60},
+		{LotId: lotIds[0], BidId: north.Id, Share: 40},
+	}}
+	if _, err := d.SetTenderAwardSet(tender.Id, params, partial); err == nil {
+		t.Fatal("award set without the second open lot was accepted")
+	}
+
+	wrongLot := partial
+	wrongLot.Shares = append(partial.Shares, model.AwardShare{LotId: lotIds[1], BidId: north.Id, Share: 100})
+	if _, err := d.SetTenderAwardSet(tender.Id, params, wrongLot); err == nil {
+		t.Fatal("share for a lot the bid does not target was accepted")
+	}
+
+	full := partial
+	full.Shares = append(partial.Shares, model.AwardShare{LotId: lotIds[1], BidId: both.Id, Share: 100})
+	awardSet, err := d.SetTenderAwardSet(tender.Id, params, full)
+	if err != nil {
+		t.Fatalf("set award set: %v", err)
+	}
+	if awardSet.Status != "Draft" || len(awardSet.Shares) != 3 {
+		t.Fatalf("award set = %+v", awardSet)
+	}
+}
@@ -5,10 +5,110 @@ import (
 	"errors"
 	"fmt"
 	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"log"
 )
 
+// bidColumns - список колонок предложения в порядке, который ожидает scanBid.
+const bidColumns = `id, name, description, status, tender_id, author_type, author_id, version, created_at,
+	price_amount, price_currency, decision,
+	ARRAY(SELECT bl.lot_id::text FROM bid_lot bl WHERE bl.bid_id = bid.id ORDER BY bl.lot_id),
+	sealed_payload, decision_rationale`
+
+// scanBid сканирует строку, выбранную по bidColumns.
+func scanBid(row pgx.Row) (*model.Bid, error) {
+	var bid model.Bid
+	var description *string
+	var priceAmount *int64
+	var priceCurrency *string
+	var decision *string
+	var rationale *string
+
+	if err := row.Scan(
+		&bid.Id,
+		&bid.Name,
+		&description,
+		&bid.Status,
+		&bid.TenderId,
+		&bid.AuthorType,
+		&bid.AuthorId,
+		&bid.Version,
+		&bid.CreatedAt,
+		&priceAmount,
+		&priceCurrency,
+		&decision,
+		&bid.LotIds,
+		&bid.SealedPayload,
+		&rationale,
+	); err != nil {
+		return nil, err
+	}
+
+	if rationale != nil {
+		bid.DecisionRationale = *rationale
+	}
+
+	bid.Sealed = len(bid.SealedPayload) > 0
+
+	if description != nil {
+		bid.Description = *description
+	}
+	if decision != nil {
+		bid.Decision = *decision
+	}
+	bid.Price = newMoney(priceAmount, priceCurrency)
+
+	return &bid, nil
+}
+
+// archiveBidVersion сохраняет текущую версию предложения в bid_version. Вызывается в транзакции
+// перед изменением, которое увеличивает version.
+func archiveBidVersion(ctx context.Context, q querier, bidId string) error {
+	_, err := q.Exec(ctx, `
+        INSERT INTO bid_version (bid_id, version, name, description, price_amount, price_currency)
+        SELECT id, version, name, description, price_amount, price_currency
+        FROM bid
+        WHERE id = $1
+        ON CONFLICT DO NOTHING`,
+		bidId)
+	return err
+}
+
+// checkBidPrice проверяет цену предложения против бюджета тендера, если владелец тендера
+// требует, чтобы предложения не превышали бюджет.
+func checkBidPrice(ctx context.Context, q querier, tenderId string, price *model.Money) error {
+	var budgetAmount *int64
+	var budgetCurrency *string
+	var strictBudget bool
+
+	err := q.QueryRow(ctx, `SELECT budget_amount, budget_currency, strict_budget FROM tender WHERE id = $1`,
+		tenderId).Scan(&budgetAmount, &budgetCurrency, &strictBudget)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return errors.New("tender not found")
+		}
+		return err
+	}
+
+	budget := newMoney(budgetAmount, budgetCurrency)
+	if !strictBudget || budget == nil {
+		return nil
+	}
+
+	if price == nil {
+		return errors.New("bid price is required for this tender")
+	}
+	if price.Currency != budget.Currency {
+		return fmt.Errorf("bid currency must be %s", budget.Currency)
+	}
+	if price.Amount > budget.Amount {
+		return errors.New("bid price exceeds tender budget")
+	}
+
+	return nil
+}
+
 func (d *Database) GetUserBids(params model.GetUserBidsParams) ([]*model.Bid, error) {
 	var bids []*model.Bid
 	ctx := context.Background()
@@ -19,39 +119,37 @@ func (d *Database) GetUserBids(params model.GetUserBidsParams) ([]*model.Bid, er
 	}
 	defer conn.Release()
 
-	query := `SELECT id, name, status, author_type,author_id,version, created_at
-		FROM bid
-		WHERE author_id = (SELECT id FROM employee WHERE username = $1)
-		ORDER BY created_at DESC LIMIT $2 OFFSET $3;
-	`
+	args := queryArgs{params.Username}
+	query := `SELECT ` + bidColumns + ` FROM bid WHERE author_id = (SELECT id FROM employee WHERE username = $1)` +
+		bidListSpec.conditions(&args, params.Filter)
+
+	order, err := bidListSpec.orderBy(params.Filter.Sort, "created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	query += order + " LIMIT " + args.add(params.Limit) + " OFFSET " + args.add(params.Offset)
 
-	rows, err := conn.Query(ctx, query, params.Username, params.Limit, params.Offset)
+	rows, err := conn.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var bid model.Bid
-		if err = rows.Scan(
-			&bid.Id,
-			&bid.Name,
-			&bid.Status,
-			&bid.AuthorType,
-			&bid.AuthorId,
-			&bid.Version,
-			&bid.CreatedAt,
-		); err != nil {
+		bid, err := scanBid(rows)
+		if err != nil {
 			return nil, err
 		}
-		bids = append(bids, &bid)
+		if err = d.unsealBid(bid); err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
 	}
 
 	return bids, nil
 }
 
 func (d *Database) CreateBid(params model.CreateBidJSONBody) (*model.Bid, error) {
-	var createdBid model.Bid
 	ctx := context.Background()
 	conn, err := d.Client.Acquire(ctx)
 	if err != nil {
@@ -77,39 +175,98 @@ func (d *Database) CreateBid(params model.CreateBidJSONBody) (*model.Bid, error)
 		return nil, errors.New("user is not authorized to create a bid for this organization")
 	}
 
+	if err = checkBidPrice(ctx, conn, params.TenderId, params.Price); err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = checkBidInvitation(ctx, tx, params.TenderId, params.AuthorId); err != nil {
+		return nil, err
+	}
+
+	if err = checkBidConflict(ctx, tx, params.TenderId, params.AuthorId); err != nil {
+		return nil, err
+	}
+
+	if err = checkBidLots(ctx, tx, params.TenderId, params.LotIds); err != nil {
+		return nil, err
+	}
+
+	sealed, err := checkSubmissionOpen(ctx, tx, params.TenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	description := &params.Description
+	priceAmount, priceCurrency := moneyArgs(params.Price)
+	var sealedPayload []byte
+	if sealed {
+		sealedPayload, err = d.sealBidContent(sealedBidContent{Description: params.Description, Price: params.Price})
+		if err != nil {
+			return nil, err
+		}
+		description, priceAmount, priceCurrency = nil, nil, nil
+	}
+
 	query := `
-        INSERT INTO bid (name, description, tender_id, author_type,author_id)
-        VALUES ($1, $2, $3, $4, $5)
-        RETURNING id,name,description,status,tender_id,author_type,author_id,version,created_at;
+        INSERT INTO bid (name, description, tender_id, author_type,author_id, price_amount, price_currency, sealed_payload)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING ` + bidColumns + `;
     `
 
-	row := conn.QueryRow(ctx, query, params.Name, params.Description, params.TenderId, params.AuthorType, params.AuthorId)
-
-	err = row.Scan(
-		&createdBid.Id,
-		&createdBid.Name,
-		&createdBid.Description,
-		&createdBid.Status,
-		&createdBid.TenderId,
-		&createdBid.AuthorType,
-		&createdBid.AuthorId,
-		&createdBid.Version,
-		&createdBid.CreatedAt,
-	)
+	row := tx.QueryRow(ctx, query, params.Name, description, params.TenderId, params.AuthorType, params.AuthorId,
+		priceAmount, priceCurrency, sealedPayload)
 
+	createdBid, err := scanBid(row)
 	if err != nil {
+		if isUniqueViolation(err, "bid_one_active_per_author_idx") {
+			return nil, errActiveBidExists
+		}
 		if pgErr, ok := err.(*pgconn.PgError); ok {
 			log.Println(pgErr.Message)
 		}
-		log.Fatalf("Unable to scan from DB: %w", err)
+		log.Printf("Unable to scan from DB: %v", err)
+		return nil, err
+	}
+	createdBid.Description, createdBid.Price = params.Description, params.Price
+
+	if len(params.LotIds) > 0 {
+		_, err = tx.Exec(ctx, `
+            INSERT INTO bid_lot (bid_id, lot_id)
+            SELECT $1, lot_id FROM unnest($2::uuid[]) AS lot_id
+            ON CONFLICT DO NOTHING`,
+			createdBid.Id, params.LotIds)
+		if err != nil {
+			return nil, err
+		}
+		createdBid.LotIds = params.LotIds
+	}
+
+	eventType := "bid.created"
+	if createdBid.Status == "Published" {
+		eventType = "bid.submitted"
+	}
+	if err = recordBidEvent(ctx, tx, eventType, createdBid.Id, nil); err != nil {
 		return nil, err
 	}
 
-	return &createdBid, nil
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{ActorId: params.AuthorId, Action: "bid.create", EntityType: "bid",
+		EntityId: createdBid.Id}, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return createdBid, nil
 }
 
 func (d *Database) EditBid(bidId string, params model.EditBidParams, body model.EditBidJSONBody) (*model.Bid, error) {
-	var updatedBid model.Bid
 	ctx := context.Background()
 	conn, err := d.Client.Acquire(ctx)
 	if err != nil {
@@ -118,13 +275,85 @@ func (d *Database) EditBid(bidId string, params model.EditBidParams, body model.
 	}
 	defer conn.Release()
 
-	err = conn.QueryRow(ctx, `
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Запечатанное содержимое читается под блокировкой: иначе параллельная правка, перешифровав свое
+	// содержимое, потеряла бы изменения этой.
+	var tenderId string
+	var payload []byte
+	err = tx.QueryRow(ctx, `SELECT tender_id, sealed_payload FROM bid WHERE id = $1 FOR UPDATE`, bidId).Scan(&tenderId, &payload)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, fmt.Errorf("bid not found")
+		}
+		return nil, err
+	}
+
+	if _, err = checkSubmissionOpen(ctx, tx, tenderId); err != nil {
+		return nil, err
+	}
+
+	if body.Price != nil {
+		var isAuction bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tender_auction WHERE tender_id = $1)`, tenderId).Scan(&isAuction)
+		if err != nil {
+			return nil, err
+		}
+		if isAuction {
+			return nil, errors.New("auction bid price can only be changed with an auction offer")
+		}
+		if err = checkBidPrice(ctx, tx, tenderId, body.Price); err != nil {
+			return nil, err
+		}
+	}
+
+	description := func() *string {
+		if body.Description != "" {
+			return &body.Description
+		} else {
+			return nil
+		}
+	}()
+	priceAmount, priceCurrency := moneyArgs(body.Price)
+
+	// Запечатанное предложение перешифровывается целиком вместе с изменениями.
+	var sealedPayload []byte
+	if len(payload) > 0 {
+		content, err := d.openBidContent(payload)
+		if err != nil {
+			return nil, err
+		}
+		if body.Description != "" {
+			content.Description = body.Description
+		}
+		if body.Price != nil {
+			content.Price = body.Price
+		}
+		if sealedPayload, err = d.sealBidContent(content); err != nil {
+			return nil, err
+		}
+		description, priceAmount, priceCurrency = nil, nil, nil
+	}
+
+	before, err := auditSnapshot(ctx, tx, "bid", bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedBid, err := scanBid(tx.QueryRow(ctx, `
         UPDATE bid
         SET name = COALESCE($1, name),
             description = COALESCE($2, description),
+            price_amount = COALESCE($5, price_amount),
+            price_currency = COALESCE($6, price_currency),
+            sealed_payload = COALESCE($7, sealed_payload),
             version = version + 1
         WHERE id = $3 AND author_id = (SELECT id FROM employee WHERE username = $4)
-        RETURNING id, name, status, author_type, author_id, version, created_at
+        RETURNING `+bidColumns+`
     `,
 		func() *string {
 			if body.Name != "" {
@@ -133,21 +362,8 @@ func (d *Database) EditBid(bidId string, params model.EditBidParams, body model.
 				return nil
 			}
 		}(),
-		func() *string {
-			if body.Description != "" {
-				return &body.Description
-			} else {
-				return nil
-			}
-		}(),
-		bidId, params.Username).Scan(
-		&updatedBid.Id,
-		&updatedBid.Name,
-		&updatedBid.Status,
-		&updatedBid.AuthorType,
-		&updatedBid.AuthorId,
-		&updatedBid.Version,
-		&updatedBid.CreatedAt)
+		description,
+		bidId, params.Username, priceAmount, priceCurrency, sealedPayload))
 
 	if err != nil {
 		if err.Error() == "no rows in result set" {
@@ -156,11 +372,110 @@ func (d *Database) EditBid(bidId string, params model.EditBidParams, body model.
 		return nil, err
 	}
 
-	return &updatedBid, nil
+	if err = recordBidEvent(ctx, tx, "bid.edited", bidId, nil); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.edit", EntityType: "bid",
+		EntityId: bidId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = d.unsealBid(updatedBid); err != nil {
+		return nil, err
+	}
+
+	return updatedBid, nil
 }
 
-func (d *Database) SubmitBidFeedback(bidId string, params model.SubmitBidFeedbackParams) *model.Bid {
-	return nil
+// SubmitBidFeedback сохраняет отзыв ответственного за тендер на предложение. Оценка, если она задана,
+// сразу учитывается в репутации автора.
+func (d *Database) SubmitBidFeedback(bidId string, params model.SubmitBidFeedbackParams) (*model.Bid, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	reviewerId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var tenderId string
+	var stillSealed bool
+	err = tx.QueryRow(ctx, `
+        SELECT t.id, t.sealed AND t.revealed_at IS NULL
+        FROM bid b
+        JOIN tender t ON t.id = b.tender_id
+        WHERE b.id = $1`,
+		bidId).Scan(&tenderId, &stillSealed)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("bid not found")
+		}
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, tx, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for the organization")
+	}
+	if stillSealed {
+		return nil, errors.New("tender bids are still sealed")
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO bid_review (bid_id, tender_id, reviewer_id, description, rating)
+        VALUES ($1, $2, $3, $4, $5)`,
+		bidId, tenderId, reviewerId, params.BidFeedback, params.Rating)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Rating != nil {
+		err = bumpReputation(ctx, tx, bidId, reputationDelta{reviews: 1, reviewScore: int(*params.Rating)})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = recordBidEvent(ctx, tx, "bid.reviewed", bidId, map[string]any{
+		"feedback": params.BidFeedback,
+		"rating":   params.Rating,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := scanBid(tx.QueryRow(ctx, `SELECT `+bidColumns+` FROM bid WHERE id = $1`, bidId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.feedback", EntityType: "bid",
+		EntityId: bidId}, nil, map[string]any{"feedback": params.BidFeedback, "rating": params.Rating}); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return bid, nil
 }
 
 func (d *Database) RollbackBid(bidId string, version int32, params model.RollbackBidParams) *model.Bid {
@@ -192,7 +507,6 @@ func (d *Database) GetBidStatus(bidId string, params model.GetBidStatusParams) (
 }
 
 func (d *Database) UpdateBidStatus(bidId string, params model.UpdateBidStatusParams) (*model.Bid, error) {
-	var updatedBid model.Bid
 	ctx := context.Background()
 	conn, err := d.Client.Acquire(ctx)
 	if err != nil {
@@ -203,148 +517,283 @@ func (d *Database) UpdateBidStatus(bidId string, params model.UpdateBidStatusPar
 
 	query := `UPDATE bid
 				SET status = $1
-				WHERE id = $2 AND author_id = (SELECT id FROM employee WHERE username = $3)
-				RETURNING id, name, status, author_type, author_id, version, created_at;`
+				WHERE id = $2 AND author_id = (SELECT id FROM employee WHERE username = $3) AND status IN ('Created', 'Published')
+				RETURNING ` + bidColumns + `;`
 
-	err = conn.QueryRow(ctx, query, params.Status, bidId, params.Username).Scan(
-		&updatedBid.Id,
-		&updatedBid.Name,
-		&updatedBid.Status,
-		&updatedBid.AuthorType,
-		&updatedBid.AuthorId,
-		&updatedBid.Version,
-		&updatedBid.CreatedAt,
-	)
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := auditSnapshot(ctx, tx, "bid", bidId)
+	if err != nil {
+		return nil, err
+	}
 
+	updatedBid, err := scanBid(tx.QueryRow(ctx, query, params.Status, bidId, params.Username))
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, errors.New("bid not found")
 		}
+		if isUniqueViolation(err, "bid_one_active_per_author_idx") {
+			return nil, errActiveBidExists
+		}
+		return nil, err
+	}
+
+	eventType := "bid.status_changed"
+	if updatedBid.Status == "Published" {
+		eventType = "bid.submitted"
+	}
+	if err = recordBidEvent(ctx, tx, eventType, bidId, nil); err != nil {
 		return nil, err
 	}
 
-	return &updatedBid, nil
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.status", EntityType: "bid",
+		EntityId: bidId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = d.unsealBid(updatedBid); err != nil {
+		return nil, err
+	}
+
+	return updatedBid, nil
 }
 
 func (d *Database) SubmitBidDecision(bidId string, params model.SubmitBidDecisionParams) (*model.Bid, error) {
-	/*
-		var bid model.Bid
-			ctx := context.Background()
-			conn, err := d.db.Acquire(ctx)
-			if err != nil {
-				log.Fatalf("Unable to acquire a database connection: %v", err)
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var tenderId, tenderStatus, bidStatus string
+	err = tx.QueryRow(ctx, `
+        SELECT t.id, t.status, b.status
+        FROM bid b
+        JOIN tender t ON t.id = b.tender_id
+        WHERE b.id = $1
+        FOR UPDATE OF b, t`,
+		bidId).Scan(&tenderId, &tenderStatus, &bidStatus)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("bid not found")
+		}
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, tx, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for the organization")
+	}
+
+	if tenderStatus != "Published" {
+		return nil, errors.New("tender is not accepting decisions")
+	}
+	if bidStatus != "Created" && bidStatus != "Published" {
+		return nil, errors.New("bid is not active")
+	}
+	before, err := auditSnapshot(ctx, tx, "bid", bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	var stillSealed bool
+	err = tx.QueryRow(ctx, `SELECT sealed AND revealed_at IS NULL FROM tender WHERE id = $1`, tenderId).Scan(&stillSealed)
+	if err != nil {
+		return nil, err
+	}
+	if stillSealed {
+		return nil, errors.New("tender bids are still sealed")
+	}
+
+	var negotiating bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM bid_negotiation_round WHERE bid_id = $1 AND status = 'Open')`,
+		bidId).Scan(&negotiating)
+	if err != nil {
+		return nil, err
+	}
+	if negotiating {
+		return nil, errors.New("bid has an open counter-offer")
+	}
+
+	// После открытия раунда BAFO решения принимаются только по его окончании и только по короткому списку.
+	var bafoOpened, bafoOpen, shortlisted bool
+	err = tx.QueryRow(ctx, `
+        SELECT t.bafo_opened_at IS NOT NULL,
+               COALESCE(t.bafo_deadline > now(), false),
+               EXISTS (SELECT 1 FROM tender_shortlist s WHERE s.tender_id = t.id AND s.bid_id = $2)
+        FROM tender t
+        WHERE t.id = $1`,
+		tenderId, bidId).Scan(&bafoOpened, &bafoOpen, &shortlisted)
+	if err != nil {
+		return nil, err
+	}
+	if bafoOpen {
+		return nil, errors.New("best and final offer round is still open")
+	}
+
+	var splitAward bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tender_award_set WHERE tender_id = $1)`, tenderId).Scan(&splitAward)
+	if err != nil {
+		return nil, err
+	}
+	if splitAward && params.Decision == "Accepted" {
+		return nil, errors.New("tender is awarded with an award set")
+	}
+	if bafoOpened && params.Decision == "Accepted" && !shortlisted {
+		return nil, errors.New("only shortlisted bids can be accepted after a best and final offer round")
+	}
+
+	previousDecision, err := bidDecision(ctx, tx, bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Первое решение фиксирует оценки тендера, обоснование сохраняется для аудита.
+	_, err = tx.Exec(ctx, `UPDATE tender SET evaluation_locked_at = COALESCE(evaluation_locked_at, now()) WHERE id = $1`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Rationale != "" {
+		_, err = tx.Exec(ctx, `UPDATE bid SET decision_rationale = $2 WHERE id = $1`, bidId, params.Rationale)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lotCount int
+	if err = tx.QueryRow(ctx, `SELECT count(*) FROM tender_lot WHERE tender_id = $1`, tenderId).Scan(&lotCount); err != nil {
+		return nil, err
+	}
+
+	if lotCount == 0 {
+		if params.LotId != "" {
+			return nil, errors.New("tender has no lots")
+		}
+
+		if _, err = tx.Exec(ctx, `UPDATE bid SET decision = $1 WHERE id = $2`, params.Decision, bidId); err != nil {
+			return nil, err
+		}
+
+		if params.Decision == "Accepted" {
+			if _, err = tx.Exec(ctx, `UPDATE tender SET status = 'Closed' WHERE id = $1`, tenderId); err != nil {
 				return nil, err
 			}
-			defer conn.Release()
-
-			var isResponsible bool
-			query := `
-				SELECT EXISTS (
-					SELECT 1
-					FROM organization_responsible r
-					JOIN bid b ON b.organization_id = r.organization_id
-					WHERE r.user_id = (SELECT id FROM employee WHERE username = $1)
-					  AND b.id = $2
-				)
-			`
-			err = conn.QueryRow(ctx, query, params.Username, bidId).Scan(&isResponsible)
-			if err != nil {
-				return &bid, err
+			if err = recordTenderEvent(ctx, tx, "tender.status_changed", tenderId, nil); err != nil {
+				return nil, err
 			}
-			if !isResponsible {
-				return &bid, errors.New("user is not responsible for the organization")
+			if err = insertAward(ctx, tx, bidId, "", params.Rationale, params.Username, "", 0); err != nil {
+				return nil, err
 			}
+		}
+	} else {
+		if params.LotId == "" {
+			return nil, errors.New("lot id is required for a multi-lot tender")
+		}
 
+		var lotStatus string
+		err = tx.QueryRow(ctx, `
+            SELECT l.status
+            FROM bid_lot bl
+            JOIN tender_lot l ON l.id = bl.lot_id
+            WHERE bl.bid_id = $1 AND bl.lot_id = $2
+            FOR UPDATE OF l`,
+			bidId, params.LotId).Scan(&lotStatus)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				return nil, errors.New("bid does not target this lot")
+			}
+			return nil, err
+		}
+		if lotStatus != "Open" {
+			return nil, errors.New("lot is already resolved")
+		}
 
-			query = `
-				INSERT INTO bid (id, user_id, decision)
-				VALUES ($1, (SELECT id FROM employee WHERE username = $2), $3, $4)
-				ON CONFLICT (bid_id, user_id) DO UPDATE
-				SET decision = EXCLUDED.decision
-			`
-
+		_, err = tx.Exec(ctx, `UPDATE bid_lot SET decision = $3 WHERE bid_id = $1 AND lot_id = $2`,
+			bidId, params.LotId, params.Decision)
+		if err != nil {
+			return nil, err
+		}
 
-			_, err = conn.Exec(ctx, query, bidId, params.Username, params.Decision)
+		if params.Decision == "Accepted" {
+			_, err = tx.Exec(ctx, `UPDATE tender_lot SET status = 'Awarded', awarded_bid_id = $1 WHERE id = $2`,
+				bidId, params.LotId)
 			if err != nil {
-				return &bid, err
+				return nil, err
+			}
+			if err = insertAward(ctx, tx, bidId, params.LotId, params.Rationale, params.Username, "", 0); err != nil {
+				return nil, err
 			}
+		}
 
+		// Итоговое решение по предложению: принято, если выиграло хотя бы один лот,
+		// и отклонено, только когда отклонено по всем своим лотам.
+		_, err = tx.Exec(ctx, `
+            UPDATE bid SET decision = (
+                SELECT CASE
+                    WHEN count(*) FILTER (WHERE decision = 'Accepted') > 0 THEN 'Accepted'::decision
+                    WHEN count(*) = count(*) FILTER (WHERE decision = 'Rejected') THEN 'Rejected'::decision
+                END
+                FROM bid_lot
+                WHERE bid_id = $1
+            )
+            WHERE id = $1`,
+			bidId)
+		if err != nil {
+			return nil, err
+		}
 
-			var totalResponsible int
-			query = `
-				SELECT COUNT(*)
-				FROM organization_responsible r
-				JOIN bids b ON b.organization_id = r.organization_id
-				WHERE b.id = $1
-			`
-			err = db.QueryRow(ctx, query, bidId).Scan(&totalResponsible)
-			if err != nil {
-				return bid, err
-			}
+		if err = closeTenderIfLotsResolved(ctx, tx, tenderId); err != nil {
+			return nil, err
+		}
+	}
 
-			// Получаем решения всех ответственных
-			var totalAccepted, totalRejected int
-			query = `
-				SELECT
-					SUM(CASE WHEN decision = 'accept' THEN 1 ELSE 0 END),
-					SUM(CASE WHEN decision = 'reject' THEN 1 ELSE 0 END)
-				FROM decisions
-				WHERE bid_id = $1
-			`
-			err = db.QueryRow(ctx, query, bidId).Scan(&totalAccepted, &totalRejected)
-			if err != nil {
-				return bid, err
-			}
+	bid, err := scanBid(tx.QueryRow(ctx, `SELECT `+bidColumns+` FROM bid WHERE id = $1`, bidId))
+	if err != nil {
+		return nil, err
+	}
 
-			// Логика отклонения предложения, если есть хотя бы один "reject"
-			if totalRejected > 0 {
-				query = `
-					UPDATE bids
-					SET status = 'rejected'
-					WHERE id = $1
-					RETURNING id, name, description, status, version, created_at
-				`
-				err = db.QueryRow(ctx, query, bidId).Scan(
-					&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.Version, &bid.CreatedAt,
-				)
-				if err != nil {
-					return bid, err
-				}
-				return bid, nil
-			}
+	if err = bumpReputation(ctx, tx, bidId, decisionDelta(previousDecision, bid.Decision)); err != nil {
+		return nil, err
+	}
 
-			// Проверка кворума (min(3, количество ответственных))
-			quorum := min(3, totalResponsible)
-			if totalAccepted >= quorum {
-				// Согласование предложения, обновление статуса
-				query = `
-					UPDATE bids
-					SET status = 'accepted'
-					WHERE id = $1
-					RETURNING id, name, description, status, version, created_at
-				`
-				err = db.QueryRow(ctx, query, bidId).Scan(
-					&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.Version, &bid.CreatedAt,
-				)
-				if err != nil {
-					return bid, err
-				}
-
-				// Закрываем тендер, если предложение было согласовано
-				query = `
-					UPDATE tenders
-					SET status = 'closed'
-					WHERE id = (SELECT tender_id FROM bids WHERE id = $1)
-				`
-				_, err = db.Exec(ctx, query, bidId)
-				if err != nil {
-					return bid, err
-				}
-			}
+	// По лоту решение предложения в целом может быть еще не определено, поэтому решение по лоту передается отдельно.
+	var lotDecision map[string]any
+	if params.LotId != "" {
+		lotDecision = map[string]any{"lotId": params.LotId, "lotDecision": params.Decision}
+	}
+	if err = recordBidEvent(ctx, tx, "bid.decided", bidId, lotDecision); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.decision", EntityType: "bid",
+		EntityId: bidId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 
-			return bid, nil
-	*/
-	return nil, nil
+	return bid, nil
 }
 
 func (d *Database) GetBidsForTender(tenderId string, params model.GetBidsForTenderParams) ([]*model.Bid, error) {
@@ -357,38 +806,134 @@ func (d *Database) GetBidsForTender(tenderId string, params model.GetBidsForTend
 	}
 	defer conn.Release()
 
+	if err = d.revealIfDue(ctx, conn, tenderId); err != nil {
+		return nil, err
+	}
+
+	args := queryArgs{tenderId, params.Username}
 	query := `
-        SELECT id, name, status, author_type, author_id, version, created_at
-        FROM bid 
-        WHERE tender_id = $1 and author_id = (SELECT id FROM employee WHERE username = $2)
-        LIMIT $3 OFFSET $4
-    `
+        SELECT ` + bidColumns + `
+        FROM bid
+        WHERE tender_id = $1
+          AND (author_id = (SELECT id FROM employee WHERE username = $2)
+               OR EXISTS (
+                   SELECT 1
+                   FROM organization_responsible r
+                   JOIN tender t ON t.organization_id = r.organization_id
+                   WHERE t.id = bid.tender_id
+                     AND r.user_id = (SELECT id FROM employee WHERE username = $2)
+               ))` + bidListSpec.conditions(&args, params.Filter)
+
+	order, err := bidListSpec.orderBy(params.Filter.Sort, "created_at ASC")
+	if err != nil {
+		return nil, err
+	}
+	query += order + " LIMIT " + args.add(params.Limit) + " OFFSET " + args.add(params.Offset)
 
-	rows, err := conn.Query(ctx, query, tenderId, params.Username, params.Limit, params.Offset)
+	var requesterId string
+	err = conn.QueryRow(ctx, `SELECT id FROM employee WHERE username = $1`, params.Username).Scan(&requesterId)
+	if err != nil && err.Error() != "no rows in result set" {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var bid model.Bid
-		if err = rows.Scan(
-			&bid.Id,
-			&bid.Name,
-			&bid.Status,
-			&bid.AuthorType,
-			&bid.AuthorId,
-			&bid.Version,
-			&bid.CreatedAt,
-		); err != nil {
+		bid, err := scanBid(rows)
+		if err != nil {
+			return nil, err
+		}
+		// Содержимое запечатанных предложений видит только их автор.
+		if bid.AuthorId == requesterId {
+			if err = d.unsealBid(bid); err != nil {
+				return nil, err
+			}
+		}
+		bids = append(bids, bid)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if params.WithReputation {
+		if err = attachAuthorReputation(ctx, conn, bids); err != nil {
 			return nil, err
 		}
-		bids = append(bids, &bid)
 	}
 
 	return bids, nil
 }
 
-func (d *Database) GetBidReviews(tenderId string, params model.GetBidReviewsParams) []*model.BidReview {
-	return []*model.BidReview{}
+// GetBidReviews возвращает отзывы на предложения автора по всем тендерам. Смотреть их может
+// ответственный за тендер, в котором автор подал предложение.
+func (d *Database) GetBidReviews(tenderId string, params model.GetBidReviewsParams) ([]*model.BidReview, error) {
+	var reviews []*model.BidReview
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.RequesterUsername)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for the organization")
+	}
+
+	authorId, err := employeeId(ctx, conn, params.AuthorUsername)
+	if err != nil {
+		return nil, err
+	}
+
+	var hasBid bool
+	err = conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM bid WHERE tender_id = $1 AND author_id = $2)`,
+		tenderId, authorId).Scan(&hasBid)
+	if err != nil {
+		return nil, err
+	}
+	if !hasBid {
+		return nil, errors.New("author has no bids for this tender")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT r.id, r.bid_id, r.tender_id, r.description, r.rating, r.created_at
+        FROM bid_review r
+        JOIN bid b ON b.id = r.bid_id
+        WHERE b.author_id = $1
+        ORDER BY r.created_at DESC, r.id
+        LIMIT $2 OFFSET $3`,
+		authorId, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review model.BidReview
+		var rating *int32
+		if err = rows.Scan(
+			&review.Id,
+			&review.BidId,
+			&review.TenderId,
+			&review.Description,
+			&rating,
+			&review.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if rating != nil {
+			review.Rating = *rating
+		}
+		reviews = append(reviews, &review)
+	}
+
+	return reviews, rows.Err()
 }
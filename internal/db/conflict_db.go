@@ -0,0 +1,215 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+)
+
+// checkBidConflict отклоняет конфликт интересов: ответственные организации-заказчика не могут подавать
+// предложения на ее тендер, а ответственные организаций из ее черного списка - на любой ее тендер.
+// Автор предложения - всегда сотрудник, поэтому его организации определяются через organization_responsible
+// при любом типе автора.
+func checkBidConflict(ctx context.Context, q querier, tenderId, authorId string) error {
+	var ownTender, blocked bool
+	err := q.QueryRow(ctx, `
+        WITH author_organization AS (
+            SELECT r.organization_id
+            FROM organization_responsible r
+            WHERE r.user_id::text = $2
+        )
+        SELECT EXISTS (
+                   SELECT 1 FROM author_organization a WHERE a.organization_id = t.organization_id
+               ),
+               EXISTS (
+                   SELECT 1
+                   FROM author_organization a
+                   JOIN organization_blocklist b ON b.blocked_organization_id = a.organization_id
+                   WHERE b.organization_id = t.organization_id
+               )
+        FROM tender t
+        WHERE t.id = $1`,
+		tenderId, authorId).Scan(&ownTender, &blocked)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return errors.New("tender not found")
+		}
+		return err
+	}
+
+	if ownTender {
+		return errors.New("organization cannot bid on its own tender")
+	}
+	if blocked {
+		return errors.New("bidder is blocked by the tender organization")
+	}
+	return nil
+}
+
+func (d *Database) BlockOrganization(organizationId string, params model.BlockOrganizationParams,
+	body model.BlockOrganizationJSONBody) (*model.BlockedOrganization, error) {
+	var blockedOrganization model.BlockedOrganization
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isOrganizationResponsible(ctx, tx, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	creatorId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	var reason *string
+	err = tx.QueryRow(ctx, `
+        INSERT INTO organization_blocklist (organization_id, blocked_organization_id, reason, created_by)
+        VALUES ($1, $2, NULLIF($3, ''), $4)
+        ON CONFLICT (organization_id, blocked_organization_id) DO UPDATE SET reason = EXCLUDED.reason
+        RETURNING organization_id, blocked_organization_id, reason, created_at`,
+		organizationId, body.OrganizationId, body.Reason, creatorId).Scan(
+		&blockedOrganization.OrganizationId,
+		&blockedOrganization.BlockedOrganizationId,
+		&reason,
+		&blockedOrganization.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if reason != nil {
+		blockedOrganization.Reason = *reason
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "organization.block",
+		EntityType: "organization", EntityId: organizationId}, nil, &blockedOrganization); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &blockedOrganization, nil
+}
+
+func (d *Database) GetOrganizationBlocklist(organizationId string, params model.GetOrganizationBlocklistParams) ([]*model.BlockedOrganization, error) {
+	var blocklist []*model.BlockedOrganization
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isOrganizationResponsible(ctx, conn, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT organization_id, blocked_organization_id, reason, created_at
+        FROM organization_blocklist
+        WHERE organization_id = $1
+        ORDER BY created_at, blocked_organization_id`,
+		organizationId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var blockedOrganization model.BlockedOrganization
+		var reason *string
+		if err = rows.Scan(
+			&blockedOrganization.OrganizationId,
+			&blockedOrganization.BlockedOrganizationId,
+			&reason,
+			&blockedOrganization.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if reason != nil {
+			blockedOrganization.Reason = *reason
+		}
+		blocklist = append(blocklist, &blockedOrganization)
+	}
+
+	return blocklist, rows.Err()
+}
+
+// UnblockOrganization удаляет организацию из черного списка. Ранее отклоненные предложения не восстанавливаются.
+func (d *Database) UnblockOrganization(organizationId, blockedOrganizationId string,
+	params model.UnblockOrganizationParams) (*model.BlockedOrganization, error) {
+	var blockedOrganization model.BlockedOrganization
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isOrganizationResponsible(ctx, tx, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	var reason *string
+	err = tx.QueryRow(ctx, `
+        DELETE FROM organization_blocklist
+        WHERE organization_id = $1 AND blocked_organization_id = $2
+        RETURNING organization_id, blocked_organization_id, reason, created_at`,
+		organizationId, blockedOrganizationId).Scan(
+		&blockedOrganization.OrganizationId,
+		&blockedOrganization.BlockedOrganizationId,
+		&reason,
+		&blockedOrganization.CreatedAt,
+	)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("organization is not blocked")
+		}
+		return nil, err
+	}
+	if reason != nil {
+		blockedOrganization.Reason = *reason
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "organization.unblock",
+		EntityType: "organization", EntityId: organizationId}, &blockedOrganization, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &blockedOrganization, nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestBidConflictRejectsOwnAndBlockedOrganizations(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	colleagueId := testEmployee(t, d, "colleague")
+	blockedId := testEmployee(t, d, "blocked")
+	supplierId := testEmployee(t, d, "supplier")
+	organizationId := testOrganization(t, d, "Buyer", creatorId, colleagueId)
+	blockedOrganizationId := testOrganization(t, d, "Blocked", blockedId)
+	testOrganization(t, d, "Supplier", supplierId)
+
+	_, err := d.BlockOrganization(organizationId, model.BlockOrganizationParams{Username: "creator"},
+		model.BlockOrganizationJSONBody{OrganizationId: blockedOrganizationId})
+	if err != nil {
+		t.Fatalf("block organization: %v", err)
+	}
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+
+	for _, authorType := range []string{"Organization", "User"} {
+		for name, authorId := range map[string]string{"buyer": colleagueId, "blocked": blockedId} {
+			_, err = d.CreateBid(model.CreateBidJSONBody{Name: "Bid", TenderId: tender.Id, AuthorType: authorType, AuthorId: authorId})
+			if err == nil {
+				t.Errorf("%s bid from the %s organization was accepted", authorType, name)
+			}
+		}
+	}
+
+	testBid(t, d, tender.Id, "Organization", supplierId)
+}
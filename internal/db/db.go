@@ -2,13 +2,37 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/instinctG/tender/internal/seal"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"os"
 )
 
 type Database struct {
 	Client *pgxpool.Pool
+	Sealer *seal.Sealer
+	// requestId помечает записи аудита копии базы, созданной WithRequestId.
+	requestId string
+}
+
+// querier - общий интерфейс соединения из пула и транзакции pgx.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// errActiveBidExists - нарушение правила "не более одного действующего предложения автора на тендер".
+var errActiveBidExists = errors.New("author already has an active bid for this tender")
+
+// isUniqueViolation сообщает, что запрос нарушил уникальный индекс index.
+func isUniqueViolation(err error, index string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == index
 }
 
 // NewDatabase создает новое подключение к базе данных на основе конфигурации из переменных окружения.
@@ -25,7 +49,12 @@ func NewDatabase() (*Database, error) {
 		return &Database{}, fmt.Errorf("could not connect to database: %w", err)
 	}
 
-	return &Database{pool}, nil
+	sealer, err := seal.NewSealerFromEnv()
+	if err != nil {
+		return &Database{}, err
+	}
+
+	return &Database{Client: pool, Sealer: sealer}, nil
 
 }
 
@@ -33,3 +62,19 @@ func NewDatabase() (*Database, error) {
 func (d *Database) Ping(ctx context.Context) error {
 	return d.Client.Ping(ctx)
 }
+
+// newMoney собирает сумму из nullable-колонок amount/currency.
+func newMoney(amount *int64, currency *string) *model.Money {
+	if amount == nil || currency == nil {
+		return nil
+	}
+	return &model.Money{Amount: *amount, Currency: *currency}
+}
+
+// moneyArgs раскладывает сумму на аргументы запроса amount/currency.
+func moneyArgs(m *model.Money) (*int64, *string) {
+	if m == nil {
+		return nil, nil
+	}
+	return &m.Amount, &m.Currency
+}
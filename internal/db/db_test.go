@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"os"
+	"strings"
+	"testing"
+)
+
+// baseSchema - таблицы сотрудников и организаций, которые миграции сервиса считают уже созданными.
+const baseSchema = `
+CREATE TYPE organization_type AS ENUM ('IE', 'LLC', 'JSC');
+
+CREATE TABLE employee (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    username VARCHAR(50) UNIQUE NOT NULL,
+    first_name VARCHAR(50),
+    last_name VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE organization (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    type organization_type,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE organization_responsible (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID REFERENCES organization(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES employee(id) ON DELETE CASCADE
+);`
+
+// testDatabase возвращает базу в отдельной схеме с примененными миграциями. Схема удаляется после теста.
+// Тесты с базой выполняются, только если задана строка подключения TEST_POSTGRES_CONN.
+func testDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	connString := os.Getenv("TEST_POSTGRES_CONN")
+	if connString == "" {
+		t.Skip("TEST_POSTGRES_CONN is not set")
+	}
+
+	ctx := context.Background()
+	admin, err := pgx.Connect(ctx, connString)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer admin.Close(ctx)
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err = admin.Exec(ctx, `CREATE EXTENSION IF NOT EXISTS "uuid-ossp" SCHEMA public`); err != nil {
+		t.Fatalf("create extension: %v", err)
+	}
+	if _, err = admin.Exec(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(context.Background(), connString)
+		if err != nil {
+			t.Logf("drop schema %s: %v", schema, err)
+			return
+		}
+		defer conn.Close(context.Background())
+		if _, err = conn.Exec(context.Background(), `DROP SCHEMA `+schema+` CASCADE`); err != nil {
+			t.Logf("drop schema %s: %v", schema, err)
+		}
+	})
+
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema + ",public"
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if _, err = pool.Exec(ctx, baseSchema); err != nil {
+		t.Fatalf("create base schema: %v", err)
+	}
+	if err = goose.SetDialect("postgres"); err != nil {
+		t.Fatalf("goose dialect: %v", err)
+	}
+	if err = goose.Up(stdlib.OpenDBFromPool(pool), "../../migrations"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return &Database{Client: pool}
+}
+
+// testExec выполняет служебный запрос теста.
+func testExec(t *testing.T, d *Database, query string, args ...any) {
+	t.Helper()
+	if _, err := d.Client.Exec(context.Background(), query, args...); err != nil {
+		t.Fatalf("%s: %v", strings.TrimSpace(query), err)
+	}
+}
+
+// testCount возвращает число, выбранное запросом query.
+func testCount(t *testing.T, d *Database, query string, args ...any) int {
+	t.Helper()
+	var count int
+	if err := d.Client.QueryRow(context.Background(), query, args...).Scan(&count); err != nil {
+		t.Fatalf("%s: %v", strings.TrimSpace(query), err)
+	}
+	return count
+}
+
+// testEmployee создает сотрудника username и возвращает его id.
+func testEmployee(t *testing.T, d *Database, username string) string {
+	t.Helper()
+	var id string
+	err := d.Client.QueryRow(context.Background(),
+		`INSERT INTO employee (username) VALUES ($1) RETURNING id`, username).Scan(&id)
+	if err != nil {
+		t.Fatalf("create employee %s: %v", username, err)
+	}
+	return id
+}
+
+// testOrganization создает организацию с ответственными responsibleIds и возвращает ее id.
+func testOrganization(t *testing.T, d *Database, name string, responsibleIds ...string) string {
+	t.Helper()
+	var id string
+	err := d.Client.QueryRow(context.Background(),
+		`INSERT INTO organization (name, type) VALUES ($1, 'LLC') RETURNING id`, name).Scan(&id)
+	if err != nil {
+		t.Fatalf("create organization %s: %v", name, err)
+	}
+	for _, userId := range responsibleIds {
+		testExec(t, d, `INSERT INTO organization_responsible (organization_id, user_id) VALUES ($1, $2)`, id, userId)
+	}
+	return id
+}
+
+// testTender создает тендер организации от имени creator и, если publish, публикует его.
+func testTender(t *testing.T, d *Database, organizationId, creator string, body model.CreateTenderJSONBody,
+	publish bool) *model.Tender {
+	t.Helper()
+	body.OrganizationId = organizationId
+	body.CreatorUsername = creator
+	if body.Name == "" {
+		body.Name = "Tender"
+	}
+	if body.ServiceType == "" {
+		body.ServiceType = "Construction"
+	}
+
+	tender, err := d.CreateTender(body)
+	if err != nil {
+		t.Fatalf("create tender: %v", err)
+	}
+	if publish {
+		tender, err = d.UpdateTenderStatus(tender.Id, model.UpdateTenderStatusParams{Status: "Published", Username: creator})
+		if err != nil {
+			t.Fatalf("publish tender: %v", err)
+		}
+	}
+	return tender
+}
+
+// testBid создает предложение сотрудника authorId на тендер tenderId.
+func testBid(t *testing.T, d *Database, tenderId, authorType, authorId string) *model.Bid {
+	t.Helper()
+	bid, err := d.CreateBid(model.CreateBidJSONBody{
+		Name:       "Bid",
+		TenderId:   tenderId,
+		AuthorType: authorType,
+		AuthorId:   authorId,
+	})
+	if err != nil {
+		t.Fatalf("create bid: %v", err)
+	}
+	return bid
+}
@@ -0,0 +1,324 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+)
+
+func (d *Database) CreateEvaluationCriterion(tenderId string, params model.CreateEvaluationCriterionParams,
+	body model.CreateEvaluationCriterionJSONBody) (*model.EvaluationCriterion, error) {
+	var criterion model.EvaluationCriterion
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isTenderResponsible(ctx, tx, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	var locked bool
+	err = tx.QueryRow(ctx, `SELECT evaluation_locked_at IS NOT NULL FROM tender WHERE id = $1`, tenderId).Scan(&locked)
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		return nil, errors.New("evaluation is locked")
+	}
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO evaluation_criterion (tender_id, name, kind, weight)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, tender_id, name, kind, weight, created_at`,
+		tenderId, body.Name, body.Kind, body.Weight).Scan(
+		&criterion.Id,
+		&criterion.TenderId,
+		&criterion.Name,
+		&criterion.Kind,
+		&criterion.Weight,
+		&criterion.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "criterion.create",
+		EntityType: "criterion", EntityId: criterion.Id}, nil, &criterion); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &criterion, nil
+}
+
+func (d *Database) GetEvaluationCriteria(tenderId string, params model.GetEvaluationCriteriaParams) ([]*model.EvaluationCriterion, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err = checkTenderVisible(ctx, conn, tenderId, params.Username); err != nil {
+		return nil, err
+	}
+
+	return getEvaluationCriteria(ctx, conn, tenderId)
+}
+
+func getEvaluationCriteria(ctx context.Context, q querier, tenderId string) ([]*model.EvaluationCriterion, error) {
+	var criteria []*model.EvaluationCriterion
+
+	rows, err := q.Query(ctx, `
+        SELECT id, tender_id, name, kind, weight, created_at
+        FROM evaluation_criterion
+        WHERE tender_id = $1
+        ORDER BY created_at, id`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var criterion model.EvaluationCriterion
+		if err = rows.Scan(
+			&criterion.Id,
+			&criterion.TenderId,
+			&criterion.Name,
+			&criterion.Kind,
+			&criterion.Weight,
+			&criterion.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		criteria = append(criteria, &criterion)
+	}
+
+	return criteria, rows.Err()
+}
+
+func (d *Database) SubmitBidScores(bidId string, params model.SubmitBidScoresParams, body []model.BidScoreJSONBody) ([]*model.BidScore, error) {
+	var scores []*model.BidScore
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Тендер блокируется, чтобы оценка не разошлась с одновременным решением по предложению.
+	var tenderId, bidStatus string
+	var locked, sealed bool
+	err = tx.QueryRow(ctx, `
+        SELECT t.id, b.status, t.evaluation_locked_at IS NOT NULL, t.sealed AND t.revealed_at IS NULL
+        FROM bid b
+        JOIN tender t ON t.id = b.tender_id
+        WHERE b.id = $1
+        FOR UPDATE OF t`,
+		bidId).Scan(&tenderId, &bidStatus, &locked, &sealed)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("bid not found")
+		}
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, tx, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	if locked {
+		return nil, errors.New("evaluation is locked")
+	}
+	if sealed {
+		return nil, errors.New("tender bids are still sealed")
+	}
+	if bidStatus == "Canceled" || bidStatus == "Void" {
+		return nil, errors.New("bid is not active")
+	}
+
+	for _, item := range body {
+		var score model.BidScore
+		var comment *string
+		err = tx.QueryRow(ctx, `
+            INSERT INTO evaluation_score (bid_id, criterion_id, evaluator_id, score, comment)
+            SELECT $1, c.id, (SELECT id FROM employee WHERE username = $3), $4, NULLIF($5, '')
+            FROM evaluation_criterion c
+            WHERE c.id = $2 AND c.tender_id = $6
+            ON CONFLICT (bid_id, criterion_id, evaluator_id) DO UPDATE
+            SET score = EXCLUDED.score,
+                comment = EXCLUDED.comment,
+                updated_at = CURRENT_TIMESTAMP
+            RETURNING bid_id, criterion_id, score::float8, comment, updated_at`,
+			bidId, item.CriterionId, params.Username, item.Score, item.Comment, tenderId).Scan(
+			&score.BidId,
+			&score.CriterionId,
+			&score.Score,
+			&comment,
+			&score.UpdatedAt,
+		)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				return nil, errors.New("criterion not found")
+			}
+			return nil, err
+		}
+		if comment != nil {
+			score.Comment = *comment
+		}
+		scores = append(scores, &score)
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.score", EntityType: "bid",
+		EntityId: bidId}, nil, map[string]any{"scores": scores}); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// GetTenderEvaluation возвращает критерии и средние оценки каждого действующего предложения
+// по каждому критерию. Итоговый балл и места считает сервис.
+func (d *Database) GetTenderEvaluation(tenderId string, params model.GetTenderEvaluationParams) (*model.TenderEvaluation, error) {
+	evaluation := model.TenderEvaluation{TenderId: tenderId}
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	err = conn.QueryRow(ctx, `SELECT evaluation_locked_at FROM tender WHERE id = $1`, tenderId).Scan(&evaluation.LockedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if evaluation.Criteria, err = getEvaluationCriteria(ctx, conn, tenderId); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT id, name, author_id, price_amount, price_currency, decision, decision_rationale
+        FROM bid
+        WHERE tender_id = $1 AND status NOT IN ('Canceled', 'Void')
+        ORDER BY created_at, id`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]*model.BidEvaluation)
+	for rows.Next() {
+		var bid model.BidEvaluation
+		var priceAmount *int64
+		var priceCurrency, decision, rationale *string
+		if err = rows.Scan(
+			&bid.BidId,
+			&bid.BidName,
+			&bid.AuthorId,
+			&priceAmount,
+			&priceCurrency,
+			&decision,
+			&rationale,
+		); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		bid.Price = newMoney(priceAmount, priceCurrency)
+		if decision != nil {
+			bid.Decision = *decision
+		}
+		if rationale != nil {
+			bid.DecisionRationale = *rationale
+		}
+		for _, criterion := range evaluation.Criteria {
+			bid.Criteria = append(bid.Criteria, model.CriterionScore{
+				CriterionId: criterion.Id,
+				Name:        criterion.Name,
+				Weight:      criterion.Weight,
+			})
+		}
+		byId[bid.BidId] = &bid
+		evaluation.Bids = append(evaluation.Bids, &bid)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(ctx, `
+        SELECT s.bid_id, s.criterion_id, avg(s.score)::float8, count(*)
+        FROM evaluation_score s
+        JOIN evaluation_criterion c ON c.id = s.criterion_id
+        WHERE c.tender_id = $1
+        GROUP BY s.bid_id, s.criterion_id`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bidId, criterionId string
+		var average float64
+		var count int32
+		if err = rows.Scan(&bidId, &criterionId, &average, &count); err != nil {
+			return nil, err
+		}
+
+		bid, ok := byId[bidId]
+		if !ok {
+			continue
+		}
+		for i := range bid.Criteria {
+			if bid.Criteria[i].CriterionId == criterionId {
+				bid.Criteria[i].AverageScore = average
+				bid.Criteria[i].Evaluations = count
+			}
+		}
+	}
+
+	return &evaluation, rows.Err()
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestTenderEvaluationAveragesScoresPerCriterion(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	reviewerId := testEmployee(t, d, "reviewer")
+	supplierId := testEmployee(t, d, "supplier")
+	organizationId := testOrganization(t, d, "Buyer", creatorId, reviewerId)
+	testOrganization(t, d, "Supplier", supplierId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	bid := testBid(t, d, tender.Id, "User", supplierId)
+
+	criterion := func(name, kind string, weight int32) *model.EvaluationCriterion {
+		t.Helper()
+		created, err := d.CreateEvaluationCriterion(tender.Id, model.CreateEvaluationCriterionParams{Username: "creator"},
+			model.CreateEvaluationCriterionJSONBody{Name: name, Kind: kind, Weight: weight})
+		if err != nil {
+			t.Fatalf("create criterion %s: %v", name, err)
+		}
+		return created
+	}
+	price := criterion("Price", "Price", 3)
+	experience := criterion("Experience", "Experience", 1)
+
+	_, err := d.SubmitBidScores(bid.Id, model.SubmitBidScoresParams{Username: "creator"}, []model.BidScoreJSONBody{
+		{CriterionId: price.Id, Score: 80},
+		{CriterionId: experience.Id, Score: 40},
+	})
+	if err != nil {
+		t.Fatalf("creator scores: %v", err)
+	}
+	_, err = d.SubmitBidScores(bid.Id, model.SubmitBidScoresParams{Username: "reviewer"},
+		[]model.BidScoreJSONBody{{CriterionId: price.Id, Score: 60}})
+	if err != nil {
+		t.Fatalf("reviewer scores: %v", err)
+	}
+	// Повторная оценка того же эксперта заменяет прежнюю.
+	_, err = d.SubmitBidScores(bid.Id, model.SubmitBidScoresParams{Username: "reviewer"},
+		[]model.BidScoreJSONBody{{CriterionId: price.Id, Score: 70}})
+	if err != nil {
+		t.Fatalf("reviewer rescores: %v", err)
+	}
+
+	evaluation, err := d.GetTenderEvaluation(tender.Id, model.GetTenderEvaluationParams{Username: "creator"})
+	if err != nil {
+		t.Fatalf("get evaluation: %v", err)
+	}
+	if len(evaluation.Bids) != 1 || len(evaluation.Bids[0].Criteria) != 2 {
+		t.Fatalf("evaluation = %+v", evaluation)
+	}
+	want := map[string]model.CriterionScore{
+		price.Id:      {Weight: 3, AverageScore: 75, Evaluations: 2},
+		experience.Id: {Weight: 1, AverageScore: 40, Evaluations: 1},
+	}
+	for _, score := range evaluation.Bids[0].Criteria {
+		expected := want[score.CriterionId]
+		if score.Weight != expected.Weight || score.AverageScore != expected.AverageScore ||
+			score.Evaluations != expected.Evaluations {
+			t.Fatalf("criterion %s score = %+v, want %+v", score.Name, score, expected)
+		}
+	}
+
+	if _, err = d.GetTenderEvaluation(tender.Id, model.GetTenderEvaluationParams{Username: "supplier"}); err == nil {
+		t.Fatal("bidder read the tender evaluation")
+	}
+}
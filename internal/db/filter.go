@@ -0,0 +1,111 @@
+package db
+
+import (
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"strings"
+)
+
+// queryArgs накапливает параметры запроса и выдает для них плейсхолдеры,
+// чтобы пользовательские значения никогда не попадали в текст SQL.
+type queryArgs []any
+
+func (a *queryArgs) add(value any) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// listSpec описывает, по каким колонкам можно фильтровать и сортировать список.
+// Текст SQL собирается только из этих колонок, значения передаются параметрами.
+type listSpec struct {
+	amountColumn   string
+	currencyColumn string
+	// organization строит условие по организации для плейсхолдера arg.
+	organization func(arg string) string
+	sortColumns  map[string]string
+}
+
+var tenderListSpec = listSpec{
+	amountColumn:   "budget_amount",
+	currencyColumn: "budget_currency",
+	organization: func(arg string) string {
+		return "organization_id = " + arg + "::uuid"
+	},
+	sortColumns: map[string]string{
+		"name":       "name",
+		"created_at": "created_at",
+		"budget":     "budget_amount",
+		"status":     "status",
+		"version":    "version",
+	},
+}
+
+// bidListSpec фильтрует предложения по организации автора. Автор - всегда сотрудник, поэтому при любом
+// типе автора это организации, за которые он отвечает.
+var bidListSpec = listSpec{
+	amountColumn:   "price_amount",
+	currencyColumn: "price_currency",
+	organization: func(arg string) string {
+		return `author_id IN (SELECT user_id FROM organization_responsible WHERE organization_id = ` + arg + `::uuid)`
+	},
+	sortColumns: map[string]string{
+		"name":       "name",
+		"created_at": "created_at",
+		"price":      "price_amount",
+		"status":     "status",
+		"version":    "version",
+	},
+}
+
+// conditions возвращает условия фильтра в виде " AND ...", добавляя значения в args.
+func (s listSpec) conditions(args *queryArgs, f model.ListFilter) string {
+	var b strings.Builder
+
+	if len(f.Status) > 0 {
+		b.WriteString(" AND status::text = ANY(" + args.add(f.Status) + "::text[])")
+	}
+	if f.OrganizationId != "" {
+		b.WriteString(" AND " + s.organization(args.add(f.OrganizationId)))
+	}
+	if f.CreatedFrom != nil {
+		b.WriteString(" AND created_at >= " + args.add(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		b.WriteString(" AND created_at < " + args.add(*f.CreatedTo))
+	}
+	if f.AmountMin != nil {
+		b.WriteString(" AND " + s.amountColumn + " >= " + args.add(*f.AmountMin))
+	}
+	if f.AmountMax != nil {
+		b.WriteString(" AND " + s.amountColumn + " <= " + args.add(*f.AmountMax))
+	}
+	if f.Currency != "" {
+		b.WriteString(" AND " + s.currencyColumn + " = " + args.add(f.Currency))
+	}
+
+	return b.String()
+}
+
+// orderBy возвращает " ORDER BY ..." по полям сортировки или по fallback, если они не заданы.
+// id в конце делает порядок стабильным для пагинации.
+func (s listSpec) orderBy(sort []model.SortField, fallback string) (string, error) {
+	if len(sort) == 0 {
+		return " ORDER BY " + fallback + ", id", nil
+	}
+
+	terms := make([]string, 0, len(sort)+1)
+	for _, field := range sort {
+		column, ok := s.sortColumns[field.Field]
+		if !ok {
+			return "", fmt.Errorf("unsupported sort field %q", field.Field)
+		}
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		terms = append(terms, column+" "+direction+" NULLS LAST")
+	}
+	terms = append(terms, "id")
+
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
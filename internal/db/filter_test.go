@@ -0,0 +1,38 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestBidsFilteredByAuthorOrganization(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierIds := []string{testEmployee(t, d, "supplier-organization"), testEmployee(t, d, "supplier-user")}
+	otherId := testEmployee(t, d, "other")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	supplierOrganizationId := testOrganization(t, d, "Supplier", supplierIds...)
+	testOrganization(t, d, "Other", otherId)
+
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	organizationBid := testBid(t, d, tender.Id, "Organization", supplierIds[0])
+	userBid := testBid(t, d, tender.Id, "User", supplierIds[1])
+	testBid(t, d, tender.Id, "Organization", otherId)
+
+	bids, err := d.GetBidsForTender(tender.Id, model.GetBidsForTenderParams{
+		Username: "creator",
+		Limit:    10,
+		Filter:   model.ListFilter{OrganizationId: supplierOrganizationId},
+	})
+	if err != nil {
+		t.Fatalf("get bids: %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, bid := range bids {
+		found[bid.Id] = true
+	}
+	if len(bids) != 2 || !found[organizationBid.Id] || !found[userBid.Id] {
+		t.Fatalf("bids of the supplier organization = %+v, want both author types", bids)
+	}
+}
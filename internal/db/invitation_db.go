@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+)
+
+// checkBidInvitation проверяет, что автор предложения может участвовать в тендере: закрытый тендер
+// принимает предложения только от ответственных приглашенных организаций. Автор предложения - всегда
+// сотрудник, поэтому его организации определяются через organization_responsible при любом типе автора.
+func checkBidInvitation(ctx context.Context, q querier, tenderId, authorId string) error {
+	var allowed bool
+	err := q.QueryRow(ctx, `
+        SELECT t.visibility = 'Public' OR EXISTS (
+            SELECT 1
+            FROM tender_invitation i
+            JOIN organization_responsible r ON r.organization_id = i.organization_id
+            WHERE i.tender_id = t.id AND r.user_id::text = $2
+        )
+        FROM tender t
+        WHERE t.id = $1`,
+		tenderId, authorId).Scan(&allowed)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return errors.New("tender not found")
+		}
+		return err
+	}
+	if !allowed {
+		return errors.New("tender not found")
+	}
+	return nil
+}
+
+func (d *Database) CreateTenderInvitation(tenderId string, params model.CreateTenderInvitationParams,
+	body model.CreateTenderInvitationJSONBody) (*model.TenderInvitation, error) {
+	var invitation model.TenderInvitation
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isTenderResponsible(ctx, tx, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	inviterId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO tender_invitation (tender_id, organization_id, invited_by)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (tender_id, organization_id) DO UPDATE SET tender_id = EXCLUDED.tender_id
+        RETURNING tender_id, organization_id, created_at`,
+		tenderId, body.OrganizationId, inviterId).Scan(
+		&invitation.TenderId,
+		&invitation.OrganizationId,
+		&invitation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.invite",
+		EntityType: "tender", EntityId: tenderId}, nil, &invitation); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+func (d *Database) GetTenderInvitations(tenderId string, params model.GetTenderInvitationsParams) ([]*model.TenderInvitation, error) {
+	var invitations []*model.TenderInvitation
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT tender_id, organization_id, created_at
+        FROM tender_invitation
+        WHERE tender_id = $1
+        ORDER BY created_at, organization_id`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var invitation model.TenderInvitation
+		if err = rows.Scan(&invitation.TenderId, &invitation.OrganizationId, &invitation.CreatedAt); err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, &invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+// DeleteTenderInvitation отзывает приглашение. Уже поданные предложения приглашенной организации сохраняются.
+func (d *Database) DeleteTenderInvitation(tenderId, organizationId string, params model.DeleteTenderInvitationParams) (*model.TenderInvitation, error) {
+	var invitation model.TenderInvitation
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isTenderResponsible(ctx, tx, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	err = tx.QueryRow(ctx, `
+        DELETE FROM tender_invitation
+        WHERE tender_id = $1 AND organization_id = $2
+        RETURNING tender_id, organization_id, created_at`,
+		tenderId, organizationId).Scan(
+		&invitation.TenderId,
+		&invitation.OrganizationId,
+		&invitation.CreatedAt,
+	)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.uninvite",
+		EntityType: "tender", EntityId: tenderId}, &invitation, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// GetInvitedTenders возвращает опубликованные закрытые тендеры, в которые приглашены
+// организации пользователя.
+func (d *Database) GetInvitedTenders(params model.GetInvitedTendersParams) ([]*model.Tender, error) {
+	var tenders []*model.Tender
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+tenderColumns+`
+        FROM tender
+        WHERE status = 'Published'
+          AND visibility = 'InviteOnly'
+          AND EXISTS (
+              SELECT 1
+              FROM tender_invitation i
+              JOIN organization_responsible r ON r.organization_id = i.organization_id
+              WHERE i.tender_id = tender.id
+                AND r.user_id = (SELECT id FROM employee WHERE username = $1)
+          )
+        ORDER BY name ASC
+        LIMIT $2 OFFSET $3`,
+		params.Username, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tender, err := scanTender(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenders = append(tenders, tender)
+	}
+
+	return tenders, rows.Err()
+}
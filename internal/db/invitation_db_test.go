@@ -0,0 +1,35 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestInviteOnlyTenderAcceptsInvitedOrganizationEmployees(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	invitedIds := map[string]string{
+		"Organization": testEmployee(t, d, "invited-organization"),
+		"User":         testEmployee(t, d, "invited-user"),
+	}
+	outsiderId := testEmployee(t, d, "outsider")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	invitedOrganizationId := testOrganization(t, d, "Invited", invitedIds["Organization"], invitedIds["User"])
+	testOrganization(t, d, "Outsider", outsiderId)
+
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{Visibility: "InviteOnly"}, true)
+	_, err := d.CreateTenderInvitation(tender.Id, model.CreateTenderInvitationParams{Username: "creator"},
+		model.CreateTenderInvitationJSONBody{OrganizationId: invitedOrganizationId})
+	if err != nil {
+		t.Fatalf("invite organization: %v", err)
+	}
+
+	for authorType, invitedId := range invitedIds {
+		testBid(t, d, tender.Id, authorType, invitedId)
+
+		_, err = d.CreateBid(model.CreateBidJSONBody{Name: "Bid", TenderId: tender.Id, AuthorType: authorType, AuthorId: outsiderId})
+		if err == nil {
+			t.Fatalf("%s bid from an organization that was not invited was accepted", authorType)
+		}
+	}
+}
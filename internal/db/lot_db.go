@@ -0,0 +1,249 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// lotColumns - список колонок лота в порядке, который ожидает scanLot.
+const lotColumns = `id, tender_id, name, description, service_type, budget_amount, budget_currency, status,
+	awarded_bid_id, created_at`
+
+// scanLot сканирует строку, выбранную по lotColumns.
+func scanLot(row pgx.Row) (*model.TenderLot, error) {
+	var lot model.TenderLot
+	var description *string
+	var budgetAmount *int64
+	var budgetCurrency *string
+	var awardedBidId *string
+
+	if err := row.Scan(
+		&lot.Id,
+		&lot.TenderId,
+		&lot.Name,
+		&description,
+		&lot.ServiceType,
+		&budgetAmount,
+		&budgetCurrency,
+		&lot.Status,
+		&awardedBidId,
+		&lot.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if description != nil {
+		lot.Description = *description
+	}
+	if awardedBidId != nil {
+		lot.AwardedBidId = *awardedBidId
+	}
+	lot.Budget = newMoney(budgetAmount, budgetCurrency)
+
+	return &lot, nil
+}
+
+// checkBidLots проверяет, что предложение по многолотовому тендеру нацелено хотя бы на один
+// открытый лот этого тендера, а предложение по обычному тендеру не содержит лотов.
+func checkBidLots(ctx context.Context, q querier, tenderId string, lotIds []string) error {
+	var lotCount, matched int
+	err := q.QueryRow(ctx, `
+        SELECT count(*),
+               count(*) FILTER (WHERE id = ANY($2::uuid[]) AND status = 'Open')
+        FROM tender_lot
+        WHERE tender_id = $1`,
+		tenderId, lotIds).Scan(&lotCount, &matched)
+	if err != nil {
+		return err
+	}
+
+	if lotCount == 0 {
+		if len(lotIds) > 0 {
+			return errors.New("tender has no lots")
+		}
+		return nil
+	}
+
+	if len(lotIds) == 0 {
+		return errors.New("bid must target at least one lot")
+	}
+
+	unique := make(map[string]struct{}, len(lotIds))
+	for _, id := range lotIds {
+		unique[id] = struct{}{}
+	}
+	if matched != len(unique) {
+		return errors.New("bid targets unknown or resolved lots")
+	}
+
+	return nil
+}
+
+// closeTenderIfLotsResolved закрывает опубликованный тендер, когда по всем его лотам принято решение.
+func closeTenderIfLotsResolved(ctx context.Context, q querier, tenderId string) error {
+	tag, err := q.Exec(ctx, `
+        UPDATE tender SET status = 'Closed'
+        WHERE id = $1
+          AND status = 'Published'
+          AND NOT EXISTS (SELECT 1 FROM tender_lot WHERE tender_id = $1 AND status = 'Open')`,
+		tenderId)
+	if err != nil || tag.RowsAffected() == 0 {
+		return err
+	}
+	return recordTenderEvent(ctx, q, "tender.status_changed", tenderId, nil)
+}
+
+func (d *Database) CreateTenderLot(tenderId string, params model.CreateTenderLotParams, body model.CreateTenderLotJSONBody) (*model.TenderLot, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	if err = tx.QueryRow(ctx, `SELECT status FROM tender WHERE id = $1 FOR UPDATE`, tenderId).Scan(&status); err != nil {
+		return nil, err
+	}
+	if status != "Created" {
+		return nil, errors.New("lots can only be added to a tender in Created status")
+	}
+
+	budgetAmount, budgetCurrency := moneyArgs(body.Budget)
+
+	lot, err := scanLot(tx.QueryRow(ctx, `
+        INSERT INTO tender_lot (tender_id, name, description, service_type, budget_amount, budget_currency)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING `+lotColumns,
+		tenderId, body.Name, body.Description, body.ServiceType, budgetAmount, budgetCurrency))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordTenderEvent(ctx, tx, "tender.lot_created", tenderId, map[string]any{"lotId": lot.Id}); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "lot.create", EntityType: "lot",
+		EntityId: lot.Id}, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return lot, nil
+}
+
+func (d *Database) GetTenderLots(tenderId string, params model.GetTenderLotsParams) ([]*model.TenderLot, error) {
+	var lots []*model.TenderLot
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err = checkTenderVisible(ctx, conn, tenderId, params.Username); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+lotColumns+`
+        FROM tender_lot
+        WHERE tender_id = $1
+        ORDER BY created_at, id`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		lot, err := scanLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+
+	return lots, nil
+}
+
+func (d *Database) CancelTenderLot(tenderId, lotId string, params model.CancelTenderLotParams) (*model.TenderLot, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := auditSnapshot(ctx, tx, "lot", lotId)
+	if err != nil {
+		return nil, err
+	}
+
+	lot, err := scanLot(tx.QueryRow(ctx, `
+        UPDATE tender_lot SET status = 'Canceled'
+        WHERE id = $1 AND tender_id = $2 AND status = 'Open'
+        RETURNING `+lotColumns,
+		lotId, tenderId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("open lot not found")
+		}
+		return nil, err
+	}
+
+	if err = recordTenderEvent(ctx, tx, "tender.lot_canceled", tenderId, map[string]any{"lotId": lot.Id}); err != nil {
+		return nil, err
+	}
+
+	if err = closeTenderIfLotsResolved(ctx, tx, tenderId); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "lot.cancel", EntityType: "lot",
+		EntityId: lotId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return lot, nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestLotDecisionsResolveLotsAndCloseTender(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, false)
+
+	var lotIds []string
+	for _, name := range []string{"North", "South"} {
+		lot, err := d.CreateTenderLot(tender.Id, model.CreateTenderLotParams{Username: "creator"},
+			model.CreateTenderLotJSONBody{Name: name, ServiceType: "Delivery"})
+		if err != nil {
+			t.Fatalf("create lot %s: %v", name, err)
+		}
+		lotIds = append(lotIds, lot.Id)
+	}
+	publish := model.UpdateTenderStatusParams{Status: "Published", Username: "creator"}
+	if _, err := d.UpdateTenderStatus(tender.Id, publish); err != nil {
+		t.Fatalf("publish tender: %v", err)
+	}
+
+	bid := func(username string, lotIds ...string) *model.Bid {
+		t.Helper()
+		authorId := testEmployee(t, d, username)
+		testOrganization(t, d, username+" LLC", authorId)
+		created, err := d.CreateBid(model.CreateBidJSONBody{
+			Name: "Bid", TenderId: tender.Id, AuthorType: "User", AuthorId: authorId, LotIds: lotIds,
+		})
+		if err != nil {
+			t.Fatalf("create bid: %v", err)
+		}
+		return created
+	}
+	alice := bid("alice", lotIds...)
+	bob := bid("bob", lotIds[0])
+	carol := bid("carol", lotIds[1])
+
+	decide := func(bidId, lotId, decision string) (*model.Bid, error) {
+		return d.SubmitBidDecision(bidId, model.SubmitBidDecisionParams{Decision: decision, Username: "creator", LotId: lotId})
+	}
+
+	if _, err := decide(alice.Id, "", "Accepted"); err == nil {
+		t.Fatal("decision without a lot accepted on a multi-lot tender")
+	}
+	if _, err := decide(bob.Id, lotIds[1], "Accepted"); err == nil {
+		t.Fatal("decision accepted for a lot the bid does not target")
+	}
+
+	// Отмененное автором предложение больше не участвует в решениях.
+	if _, err := d.UpdateBidStatus(carol.Id, model.UpdateBidStatusParams{Status: "Canceled", Username: "carol"}); err != nil {
+		t.Fatalf("cancel bid: %v", err)
+	}
+	if _, err := decide(carol.Id, lotIds[1], "Accepted"); err == nil {
+		t.Fatal("decision accepted for a canceled bid")
+	}
+
+	decided, err := decide(alice.Id, lotIds[0], "Accepted")
+	if err != nil {
+		t.Fatalf("accept alice on the first lot: %v", err)
+	}
+	if decided.Decision != "Accepted" {
+		t.Fatalf("bid decision = %q, want Accepted after winning a lot", decided.Decision)
+	}
+	if _, err = decide(bob.Id, lotIds[0], "Accepted"); err == nil {
+		t.Fatal("second award of a resolved lot accepted")
+	}
+
+	closed := model.UpdateTenderStatusParams{Status: "Closed", Username: "creator"}
+	if _, err = d.UpdateTenderStatus(tender.Id, closed); err == nil {
+		t.Fatal("tender with an open lot was closed")
+	}
+
+	if _, err = decide(alice.Id, lotIds[1], "Rejected"); err != nil {
+		t.Fatalf("reject alice on the second lot: %v", err)
+	}
+	if n := testCount(t, d, `SELECT count(*) FROM tender WHERE id = $1 AND status = 'Published'`, tender.Id); n != 1 {
+		t.Fatal("tender closed while the second lot is still open")
+	}
+	if _, err = d.CancelTenderLot(tender.Id, lotIds[1], model.CancelTenderLotParams{Username: "creator"}); err != nil {
+		t.Fatalf("cancel the second lot: %v", err)
+	}
+	if n := testCount(t, d, `SELECT count(*) FROM tender WHERE id = $1 AND status = 'Closed'`, tender.Id); n != 1 {
+		t.Fatal("tender is not closed after every lot was resolved")
+	}
+
+	lots, err := d.GetTenderLots(tender.Id, model.GetTenderLotsParams{Username: "creator"})
+	if err != nil {
+		t.Fatalf("get lots: %v", err)
+	}
+	for _, lot := range lots {
+		if lot.Id == lotIds[0] && (lot.Status != "Awarded" || lot.AwardedBidId != alice.Id) {
+			t.Fatalf("first lot = %+v, want awarded to alice", lot)
+		}
+	}
+}
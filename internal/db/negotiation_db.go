@@ -0,0 +1,379 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// negotiationColumns - список колонок раунда переговоров в порядке, который ожидает scanNegotiationRound.
+const negotiationColumns = `id, bid_id, round, party, price_amount, price_currency, terms, status, bid_version,
+	created_at, responded_at`
+
+func scanNegotiationRound(row pgx.Row) (*model.BidNegotiationRound, error) {
+	var round model.BidNegotiationRound
+	var priceAmount *int64
+	var priceCurrency, terms *string
+	var bidVersion *int32
+
+	if err := row.Scan(
+		&round.Id,
+		&round.BidId,
+		&round.Round,
+		&round.Party,
+		&priceAmount,
+		&priceCurrency,
+		&terms,
+		&round.Status,
+		&bidVersion,
+		&round.CreatedAt,
+		&round.RespondedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	round.Price = newMoney(priceAmount, priceCurrency)
+	if terms != nil {
+		round.Terms = *terms
+	}
+	if bidVersion != nil {
+		round.BidVersion = *bidVersion
+	}
+
+	return &round, nil
+}
+
+// lockNegotiation блокирует предложение и определяет сторону переговоров пользователя: Buyer - ответственный
+// организации-заказчика, Bidder - автор предложения. Переговоры возможны по действующему вскрытому предложению
+// без решения в опубликованном тендере без аукциона.
+func lockNegotiation(ctx context.Context, tx pgx.Tx, bidId, username string) (string, string, error) {
+	var tenderId, bidStatus, tenderStatus string
+	var decided, sealed, isAuction, isAuthor, bafo bool
+	err := tx.QueryRow(ctx, `
+        SELECT b.tender_id, b.status,
+               b.decision IS NOT NULL OR EXISTS (SELECT 1 FROM bid_lot WHERE bid_id = b.id AND decision IS NOT NULL),
+               b.sealed_payload IS NOT NULL,
+               t.status,
+               EXISTS (SELECT 1 FROM tender_auction WHERE tender_id = t.id),
+               b.author_id = (SELECT id FROM employee WHERE username = $2),
+               t.bafo_opened_at IS NOT NULL
+        FROM bid b
+        JOIN tender t ON t.id = b.tender_id
+        WHERE b.id = $1
+        FOR UPDATE OF b`,
+		bidId, username).Scan(&tenderId, &bidStatus, &decided, &sealed, &tenderStatus, &isAuction, &isAuthor, &bafo)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return "", "", errors.New("bid not found")
+		}
+		return "", "", err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, tx, tenderId, username)
+	if err != nil {
+		return "", "", err
+	}
+
+	party := ""
+	switch {
+	case isResponsible:
+		party = "Buyer"
+	case isAuthor:
+		party = "Bidder"
+	default:
+		return "", "", errors.New("bid not found")
+	}
+
+	if tenderStatus != "Published" {
+		return "", "", errors.New("tender is not published")
+	}
+	if bidStatus != "Created" && bidStatus != "Published" {
+		return "", "", errors.New("bid is not active")
+	}
+	if decided {
+		return "", "", errors.New("bid already has a decision")
+	}
+	if sealed {
+		return "", "", errors.New("tender bids are still sealed")
+	}
+	if isAuction {
+		return "", "", errors.New("auction bid price can only be changed with an auction offer")
+	}
+	if bafo {
+		return "", "", errors.New("tender is in a best and final offer round")
+	}
+
+	return tenderId, party, nil
+}
+
+// openNegotiationRound возвращает открытый раунд переговоров по предложению или nil.
+func openNegotiationRound(ctx context.Context, q querier, bidId string) (*model.BidNegotiationRound, error) {
+	round, err := scanNegotiationRound(q.QueryRow(ctx, `
+        SELECT `+negotiationColumns+`
+        FROM bid_negotiation_round
+        WHERE bid_id = $1 AND status = 'Open'`,
+		bidId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return round, nil
+}
+
+// notifyNegotiationParty уведомляет противоположную party сторону переговоров.
+func notifyNegotiationParty(ctx context.Context, q querier, tenderId, bidId, party, kind string,
+	round *model.BidNegotiationRound) error {
+	if party == "Buyer" {
+		return notifyBidAuthors(ctx, q, tenderId, []string{bidId}, kind, round)
+	}
+	return notifyTenderResponsibles(ctx, q, tenderId, bidId, kind, round)
+}
+
+// CreateCounterOffer добавляет раунд переговоров. Первый раунд открывает только заказчик, дальше стороны
+// ходят по очереди: новое встречное предложение закрывает открытый раунд другой стороны со статусом Countered.
+func (d *Database) CreateCounterOffer(bidId string, params model.CreateCounterOfferParams,
+	body model.CreateCounterOfferJSONBody) (*model.BidNegotiationRound, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	creatorId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tenderId, party, err := lockNegotiation(ctx, tx, bidId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	before, err := auditSnapshot(ctx, tx, "bid", bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := openNegotiationRound(ctx, tx, bidId)
+	if err != nil {
+		return nil, err
+	}
+	if open == nil && party != "Buyer" {
+		return nil, errors.New("only the tender owner can open a negotiation")
+	}
+	if open != nil && open.Party == party {
+		return nil, errors.New("counter-offer is waiting for the other party")
+	}
+
+	if body.Price != nil {
+		if err = checkBidPrice(ctx, tx, tenderId, body.Price); err != nil {
+			return nil, err
+		}
+	}
+
+	if open != nil {
+		_, err = tx.Exec(ctx, `UPDATE bid_negotiation_round SET status = 'Countered', responded_at = now() WHERE id = $1`,
+			open.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	priceAmount, priceCurrency := moneyArgs(body.Price)
+	round, err := scanNegotiationRound(tx.QueryRow(ctx, `
+        INSERT INTO bid_negotiation_round (bid_id, round, party, price_amount, price_currency, terms, created_by)
+        SELECT $1, COALESCE(max(round), 0) + 1, $2, $3, $4, NULLIF($5, ''), $6
+        FROM bid_negotiation_round
+        WHERE bid_id = $1
+        RETURNING `+negotiationColumns,
+		bidId, party, priceAmount, priceCurrency, body.Terms, creatorId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = notifyNegotiationParty(ctx, tx, tenderId, bidId, party, "BidCounterOffer", round); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.counter_offer", EntityType: "bid",
+		EntityId: bidId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// AcceptCounterOffer принимает открытое встречное предложение другой стороны: его цена и условия
+// становятся новой версией предложения, а прежняя версия сохраняется в bid_version.
+func (d *Database) AcceptCounterOffer(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error) {
+	return d.respondCounterOffer(bidId, params, "Accepted", "bid.counter_offer.accept")
+}
+
+// DeclineCounterOffer отклоняет открытое встречное предложение другой стороны и завершает переговоры.
+func (d *Database) DeclineCounterOffer(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error) {
+	return d.respondCounterOffer(bidId, params, "Declined", "bid.counter_offer.decline")
+}
+
+// respondCounterOffer закрывает открытый раунд статусом status и пишет в журнал аудита действие action.
+func (d *Database) respondCounterOffer(bidId string, params model.RespondCounterOfferParams,
+	status, action string) (*model.BidNegotiationRound, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tenderId, party, err := lockNegotiation(ctx, tx, bidId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	before, err := auditSnapshot(ctx, tx, "bid", bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := openNegotiationRound(ctx, tx, bidId)
+	if err != nil {
+		return nil, err
+	}
+	if open == nil {
+		return nil, errors.New("bid has no open counter-offer")
+	}
+	if open.Party == party {
+		return nil, errors.New("counter-offer is waiting for the other party")
+	}
+
+	var bidVersion *int32
+	if status == "Accepted" {
+		if open.Price != nil {
+			if err = checkBidPrice(ctx, tx, tenderId, open.Price); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = archiveBidVersion(ctx, tx, bidId); err != nil {
+			return nil, err
+		}
+
+		priceAmount, priceCurrency := moneyArgs(open.Price)
+		var version int32
+		err = tx.QueryRow(ctx, `
+            UPDATE bid
+            SET price_amount = COALESCE($2, price_amount),
+                price_currency = COALESCE($3, price_currency),
+                description = COALESCE(NULLIF($4, ''), description),
+                version = version + 1
+            WHERE id = $1
+            RETURNING version`,
+			bidId, priceAmount, priceCurrency, open.Terms).Scan(&version)
+		if err != nil {
+			return nil, err
+		}
+		bidVersion = &version
+
+		if err = recordBidEvent(ctx, tx, "bid.edited", bidId, map[string]any{"counterOfferId": open.Id}); err != nil {
+			return nil, err
+		}
+	}
+
+	round, err := scanNegotiationRound(tx.QueryRow(ctx, `
+        UPDATE bid_negotiation_round
+        SET status = $2, responded_at = now(), bid_version = $3
+        WHERE id = $1
+        RETURNING `+negotiationColumns,
+		open.Id, status, bidVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = notifyNegotiationParty(ctx, tx, tenderId, bidId, party, "CounterOffer"+status, round); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: action, EntityType: "bid",
+		EntityId: bidId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// GetBidNegotiation возвращает все раунды переговоров по предложению автору и ответственным организации-заказчика.
+func (d *Database) GetBidNegotiation(bidId string, params model.GetBidNegotiationParams) ([]*model.BidNegotiationRound, error) {
+	var rounds []*model.BidNegotiationRound
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	var tenderId string
+	var isAuthor bool
+	err = conn.QueryRow(ctx, `
+        SELECT tender_id, author_id = (SELECT id FROM employee WHERE username = $2)
+        FROM bid
+        WHERE id = $1`,
+		bidId, params.Username).Scan(&tenderId, &isAuthor)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("bid not found")
+		}
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthor && !isResponsible {
+		return nil, errors.New("bid not found")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+negotiationColumns+`
+        FROM bid_negotiation_round
+        WHERE bid_id = $1
+        ORDER BY round`,
+		bidId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		round, err := scanNegotiationRound(rows)
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, round)
+	}
+
+	return rounds, rows.Err()
+}
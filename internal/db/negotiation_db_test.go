@@ -0,0 +1,53 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestAcceptCounterOfferArchivesPreviousBidVersion(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	testOrganization(t, d, "Supplier", supplierId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+
+	bid, err := d.CreateBid(model.CreateBidJSONBody{
+		Name:        "Bid",
+		Description: "Initial terms",
+		TenderId:    tender.Id,
+		AuthorType:  "User",
+		AuthorId:    supplierId,
+		Price:       &model.Money{Amount: 50000, Currency: "RUB"},
+	})
+	if err != nil {
+		t.Fatalf("create bid: %v", err)
+	}
+
+	_, err = d.CreateCounterOffer(bid.Id, model.CreateCounterOfferParams{Username: "creator"}, model.CreateCounterOfferJSONBody{
+		Price: &model.Money{Amount: 45000, Currency: "RUB"},
+		Terms: "Delivery within a week",
+	})
+	if err != nil {
+		t.Fatalf("counter-offer: %v", err)
+	}
+	round, err := d.AcceptCounterOffer(bid.Id, model.RespondCounterOfferParams{Username: "supplier"})
+	if err != nil {
+		t.Fatalf("accept counter-offer: %v", err)
+	}
+	if round.BidVersion != bid.Version+1 {
+		t.Fatalf("accepted round bid version = %v, want %d", round.BidVersion, bid.Version+1)
+	}
+
+	n := testCount(t, d, `
+        SELECT count(*) FROM bid_version
+        WHERE bid_id = $1 AND version = $2 AND description = 'Initial terms' AND price_amount = 50000`,
+		bid.Id, bid.Version)
+	if n != 1 {
+		t.Fatalf("archived versions of the bid before the counter-offer = %d, want 1", n)
+	}
+	if n = testCount(t, d, `SELECT count(*) FROM bid_version WHERE bid_id = $1`, bid.Id); n != 1 {
+		t.Fatalf("archived versions = %d, want 1", n)
+	}
+}
@@ -0,0 +1,314 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// notificationEnabled возвращает условие, при котором уведомление вида kind доставляется получателю recipient:
+// пользователь не отключил такие уведомления в настройках.
+func notificationEnabled(recipient, kind string) string {
+	return `NOT EXISTS (
+            SELECT 1 FROM notification_preference p
+            WHERE p.user_id = ` + recipient + ` AND p.kind = ` + kind + ` AND NOT p.enabled
+        )`
+}
+
+// notifyBidAuthors ставит в очередь уведомление kind авторам предложений bidIds. Автор - всегда сотрудник
+// и получает уведомление сам, а для предложений от имени организации его получают и остальные ответственные
+// организаций, за которые он отвечает.
+func notifyBidAuthors(ctx context.Context, q querier, tenderId string, bidIds []string, kind string, payload any) error {
+	if len(bidIds) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+        INSERT INTO notification (recipient_id, kind, tender_id, bid_id, payload)
+        SELECT recipient.id, $3, $1, b.id, $4
+        FROM bid b
+        CROSS JOIN LATERAL (
+            SELECT b.author_id AS id
+            UNION
+            SELECT colleague.user_id
+            FROM organization_responsible r
+            JOIN organization_responsible colleague ON colleague.organization_id = r.organization_id
+            WHERE b.author_type = 'Organization' AND r.user_id = b.author_id
+        ) recipient
+        WHERE b.id = ANY($2::uuid[]) AND `+notificationEnabled("recipient.id", "$3"),
+		tenderId, bidIds, kind, data)
+	return err
+}
+
+// notifyTenderResponsibles ставит в очередь уведомление kind всем ответственным организации-заказчика.
+func notifyTenderResponsibles(ctx context.Context, q querier, tenderId, bidId, kind string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+        INSERT INTO notification (recipient_id, kind, tender_id, bid_id, payload)
+        SELECT r.user_id, $3, t.id, NULLIF($2, '')::uuid, $4
+        FROM tender t
+        JOIN organization_responsible r ON r.organization_id = t.organization_id
+        WHERE t.id = $1 AND `+notificationEnabled("r.user_id", "$3"),
+		tenderId, bidId, kind, data)
+	return err
+}
+
+// Notify ставит в очередь уведомление о событии сервиса получателям event.Audience.
+func (d *Database) Notify(event model.NotificationEvent) error {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return err
+	}
+	defer conn.Release()
+
+	switch event.Audience {
+	case "TenderResponsibles":
+		return notifyTenderResponsibles(ctx, conn, event.TenderId, event.BidId, event.Kind, event.Payload)
+	case "BidAuthors":
+		return notifyBidAuthors(ctx, conn, event.TenderId, []string{event.BidId}, event.Kind, event.Payload)
+	case "TenderBidders":
+		rows, err := conn.Query(ctx, `SELECT id FROM bid WHERE tender_id = $1 AND status IN ('Created', 'Published')`,
+			event.TenderId)
+		if err != nil {
+			return err
+		}
+		bidIds, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		return notifyBidAuthors(ctx, conn, event.TenderId, bidIds, event.Kind, event.Payload)
+	}
+
+	return fmt.Errorf("unknown notification audience %q", event.Audience)
+}
+
+const notificationColumns = `id, kind, tender_id, bid_id, payload, created_at, read_at`
+
+func scanNotification(row pgx.Row) (*model.Notification, error) {
+	var notification model.Notification
+	var tenderId, bidId *string
+
+	if err := row.Scan(
+		&notification.Id,
+		&notification.Kind,
+		&tenderId,
+		&bidId,
+		&notification.Payload,
+		&notification.CreatedAt,
+		&notification.ReadAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if tenderId != nil {
+		notification.TenderId = *tenderId
+	}
+	if bidId != nil {
+		notification.BidId = *bidId
+	}
+
+	return &notification, nil
+}
+
+// GetNotifications возвращает входящие пользователя, новые сверху, и число непрочитанных.
+func (d *Database) GetNotifications(params model.GetNotificationsParams) (*model.NotificationInbox, error) {
+	inbox := model.NotificationInbox{Notifications: []*model.Notification{}}
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	userId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.QueryRow(ctx, `SELECT count(*) FROM notification WHERE recipient_id = $1 AND read_at IS NULL`,
+		userId).Scan(&inbox.UnreadCount)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+notificationColumns+`
+        FROM notification
+        WHERE recipient_id = $1 AND (NOT $2 OR read_at IS NULL)
+        ORDER BY created_at DESC, id
+        LIMIT $3 OFFSET $4`,
+		userId, params.UnreadOnly, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		inbox.Notifications = append(inbox.Notifications, notification)
+	}
+
+	return &inbox, rows.Err()
+}
+
+// MarkNotificationsRead отмечает прочитанными уведомления пользователя и возвращает отмеченные.
+func (d *Database) MarkNotificationsRead(params model.MarkNotificationsReadParams,
+	body model.MarkNotificationsReadJSONBody) ([]*model.Notification, error) {
+	var notifications []*model.Notification
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	userId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+        UPDATE notification SET read_at = CURRENT_TIMESTAMP
+        WHERE recipient_id = $1 AND read_at IS NULL AND ($2 OR id = ANY($3::uuid[]))
+        RETURNING `+notificationColumns,
+		userId, body.All, body.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	read := []string{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+		read = append(read, notification.Id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "notification.read",
+		EntityType: "employee", EntityId: params.Username}, nil, map[string]any{"readNotifications": read}); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// GetNotificationPreferences возвращает сохраненные настройки пользователя. Виды без настройки доставляются.
+func (d *Database) GetNotificationPreferences(params model.GetNotificationPreferencesParams) ([]*model.NotificationPreference, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	userId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationPreferences(ctx, conn, userId)
+}
+
+func notificationPreferences(ctx context.Context, q querier, userId string) ([]*model.NotificationPreference, error) {
+	var preferences []*model.NotificationPreference
+
+	rows, err := q.Query(ctx, `SELECT kind, enabled FROM notification_preference WHERE user_id = $1 ORDER BY kind`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var preference model.NotificationPreference
+		if err = rows.Scan(&preference.Kind, &preference.Enabled); err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, &preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+// SetNotificationPreferences сохраняет настройки по перечисленным видам уведомлений, остальные не меняются.
+func (d *Database) SetNotificationPreferences(params model.SetNotificationPreferencesParams,
+	body model.SetNotificationPreferencesJSONBody) ([]*model.NotificationPreference, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	userId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, preference := range body.Preferences {
+		_, err = tx.Exec(ctx, `
+            INSERT INTO notification_preference (user_id, kind, enabled)
+            VALUES ($1, $2, $3)
+            ON CONFLICT (user_id, kind) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = CURRENT_TIMESTAMP`,
+			userId, preference.Kind, preference.Enabled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	preferences, err := notificationPreferences(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "notification.preferences",
+		EntityType: "employee", EntityId: params.Username}, nil, map[string]any{"preferences": body.Preferences}); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
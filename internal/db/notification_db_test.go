@@ -0,0 +1,42 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestBidAuthorsNotifiedForEveryAuthorType(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	colleagueId := testEmployee(t, d, "colleague")
+	soloId := testEmployee(t, d, "solo")
+	soloColleagueId := testEmployee(t, d, "solo-colleague")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	testOrganization(t, d, "Supplier", supplierId, colleagueId)
+	testOrganization(t, d, "Solo", soloId, soloColleagueId)
+
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	organizationBid := testBid(t, d, tender.Id, "Organization", supplierId)
+	userBid := testBid(t, d, tender.Id, "User", soloId)
+
+	for _, bid := range []*model.Bid{organizationBid, userBid} {
+		err := d.Notify(model.NotificationEvent{
+			Kind:     "bid.decided",
+			Audience: "BidAuthors",
+			TenderId: tender.Id,
+			BidId:    bid.Id,
+			Payload:  map[string]any{"bidName": bid.Name},
+		})
+		if err != nil {
+			t.Fatalf("notify: %v", err)
+		}
+	}
+
+	want := map[string]int{supplierId: 1, colleagueId: 1, soloId: 1, soloColleagueId: 0, creatorId: 0}
+	for recipientId, count := range want {
+		if got := testCount(t, d, `SELECT count(*) FROM notification WHERE recipient_id = $1`, recipientId); got != count {
+			t.Fatalf("recipient %s got %d notifications, want %d", recipientId, got, count)
+		}
+	}
+}
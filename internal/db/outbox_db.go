@@ -0,0 +1,317 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"strconv"
+)
+
+// outboxChannel - канал LISTEN/NOTIFY, в который реле отправляет id каждого опубликованного события.
+const outboxChannel = "outbox_event"
+
+// outboxPublishLock - ключ транзакционной advisory-блокировки, под которой реле выдает номера публикации.
+// Блокировка держится до фиксации, поэтому номера видны читателям строго по возрастанию.
+const outboxPublishLock = 7_241_001
+
+const outboxEventColumns = `id, COALESCE(publish_seq, 0), event_id, event_type, tender_id, COALESCE(bid_id::text, ''),
+        payload, created_at, attempts, published_sinks`
+
+func scanOutboxEvent(row pgx.Row) (*model.OutboxEvent, error) {
+	var event model.OutboxEvent
+	if err := row.Scan(
+		&event.Id,
+		&event.Sequence,
+		&event.EventId,
+		&event.Type,
+		&event.TenderId,
+		&event.BidId,
+		&event.Payload,
+		&event.CreatedAt,
+		&event.Attempts,
+		&event.PublishedSinks,
+	); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// recordTenderEvent пишет в outbox событие eventType по тендеру tenderId. Данные события - состояние тендера
+// на момент записи, поэтому вызывать его нужно в той же транзакции после изменения; extra дополняет данные события.
+func recordTenderEvent(ctx context.Context, q querier, eventType, tenderId string, extra map[string]any) error {
+	if extra == nil {
+		extra = map[string]any{}
+	}
+	extraJSON, err := json.Marshal(extra)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+        INSERT INTO outbox_event (event_type, tender_id, payload)
+        SELECT $1, t.id, jsonb_build_object(
+                   'id', t.id,
+                   'name', t.name,
+                   'status', t.status,
+                   'serviceType', t.service_type,
+                   'organizationId', t.organization_id,
+                   'visibility', t.visibility,
+                   'version', t.version,
+                   'cancelReason', t.cancel_reason) || $3::jsonb
+        FROM tender t
+        WHERE t.id = $2`,
+		eventType, tenderId, extraJSON)
+	return err
+}
+
+// recordBidEvent пишет в outbox событие eventType по предложению bidId. Описание и цена в событие не попадают,
+// чтобы не раскрыть содержимое запечатанных предложений; extra дополняет данные события.
+func recordBidEvent(ctx context.Context, q querier, eventType, bidId string, extra map[string]any) error {
+	if extra == nil {
+		extra = map[string]any{}
+	}
+	extraJSON, err := json.Marshal(extra)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+        INSERT INTO outbox_event (event_type, tender_id, bid_id, payload)
+        SELECT $1, b.tender_id, b.id, jsonb_build_object(
+                   'id', b.id,
+                   'name', b.name,
+                   'status', b.status,
+                   'tenderId', b.tender_id,
+                   'authorType', b.author_type,
+                   'authorId', b.author_id,
+                   'version', b.version,
+                   'decision', b.decision) || $3::jsonb
+        FROM bid b
+        WHERE b.id = $2`,
+		eventType, bidId, extraJSON)
+	return err
+}
+
+// recordQuestionEvent пишет в outbox событие eventType по вопросу questionId. Автор вопроса не раскрывается
+// и в событие не попадает.
+func recordQuestionEvent(ctx context.Context, q querier, eventType, questionId string) error {
+	_, err := q.Exec(ctx, `
+        INSERT INTO outbox_event (event_type, tender_id, payload)
+        SELECT $1, q.tender_id, jsonb_build_object(
+                   'id', q.id,
+                   'tenderId', q.tender_id,
+                   'question', q.question,
+                   'answer', q.answer,
+                   'public', q.is_public,
+                   'answeredAt', q.answered_at)
+        FROM tender_question q
+        WHERE q.id = $2`,
+		eventType, questionId)
+	return err
+}
+
+// RelayOutboxEvents берет до limit неопубликованных событий по порядку записи и передает каждое в publish
+// в той же транзакции. Строки, заблокированные другим реле, пропускаются. Если publish вернул ошибку, событие
+// остается неопубликованным, а обработка пакета прекращается, чтобы не нарушить порядок; после maxAttempts
+// неудачных попыток событие помечается failed_at и больше не передается. Опубликованные события получают
+// номера публикации перед самой фиксацией, и о каждом сообщается в канал outboxChannel.
+func (d *Database) RelayOutboxEvents(limit, maxAttempts int, publish func(event *model.OutboxEvent) error) (int, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return 0, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+        SELECT `+outboxEventColumns+`
+        FROM outbox_event
+        WHERE published_at IS NULL AND failed_at IS NULL
+        ORDER BY id
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED`,
+		limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []*model.OutboxEvent
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var published []*model.OutboxEvent
+	for _, event := range events {
+		if publishErr := publish(event); publishErr != nil {
+			log.Printf("failed to publish outbox event %d (%s): %v", event.Id, event.Type, publishErr)
+			_, err = tx.Exec(ctx, `
+                UPDATE outbox_event
+                SET attempts = attempts + 1,
+                    last_error = $2,
+                    published_sinks = COALESCE($3::text[], '{}'),
+                    failed_at = CASE WHEN attempts + 1 >= $4 THEN now() END
+                WHERE id = $1`,
+				event.Id, publishErr.Error(), event.PublishedSinks, maxAttempts)
+			if err != nil {
+				return len(published), err
+			}
+			break
+		}
+
+		_, err = tx.Exec(ctx, `
+            UPDATE outbox_event
+            SET published_at = now(), published_sinks = COALESCE($2::text[], '{}')
+            WHERE id = $1`,
+			event.Id, event.PublishedSinks)
+		if err != nil {
+			return len(published), err
+		}
+		published = append(published, event)
+	}
+
+	if len(published) > 0 {
+		if _, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, outboxPublishLock); err != nil {
+			return 0, err
+		}
+	}
+	for _, event := range published {
+		err = tx.QueryRow(ctx, `
+            UPDATE outbox_event SET publish_seq = nextval('outbox_publish_seq')
+            WHERE id = $1
+            RETURNING publish_seq`,
+			event.Id).Scan(&event.Sequence)
+		if err != nil {
+			return 0, err
+		}
+
+		// Уведомление уходит слушателям только после фиксации транзакции.
+		if _, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, outboxChannel, strconv.FormatInt(event.Id, 10)); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(published), nil
+}
+
+// ListenOutboxEvents передает handle события, опубликованные реле любой реплики, пока не отменен ctx
+// или не оборвалось соединение. Для ожидания уведомлений соединение забирается из пула.
+func (d *Database) ListenOutboxEvents(ctx context.Context, handle func(event *model.OutboxEvent)) error {
+	pooled, err := d.Client.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err = conn.Exec(ctx, `LISTEN `+outboxChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		id, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			log.Printf("unexpected outbox notification %q", notification.Payload)
+			continue
+		}
+
+		event, err := scanOutboxEvent(conn.QueryRow(ctx, `SELECT `+outboxEventColumns+` FROM outbox_event WHERE id = $1`, id))
+		if err != nil {
+			return err
+		}
+		handle(event)
+	}
+}
+
+// GetTenderEvents возвращает по порядку публикации события тендера с номером публикации больше
+// params.LastEventId, которые пользователь может видеть: смену статуса тендера - все, кому виден тендер,
+// поданные предложения - ответственные, решения, смену статуса и отзыв предложения - его автор, ответы
+// на вопросы - ответственные, автор вопроса и, для публичных ответов, все остальные.
+func (d *Database) GetTenderEvents(tenderId string, params model.GetTenderEventsParams) ([]*model.OutboxEvent, error) {
+	events := []*model.OutboxEvent{}
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	userId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkTenderVisible(ctx, conn, tenderId, params.Username); err != nil {
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+outboxEventColumns+`
+        FROM outbox_event e
+        WHERE e.tender_id = $1
+          AND e.publish_seq > $2
+          AND (e.event_type IN ('tender.published', 'tender.status_changed', 'tender.canceled')
+               OR (e.event_type = 'bid.submitted' AND $3)
+               OR (e.event_type IN ('bid.decided', 'bid.status_changed', 'bid.withdrawn') AND EXISTS (
+                   SELECT 1 FROM bid b WHERE b.id = e.bid_id AND b.author_id = $4
+               ))
+               OR (e.event_type = 'question.answered' AND ($3 OR EXISTS (
+                   SELECT 1
+                   FROM tender_question q
+                   WHERE q.id = (e.payload->>'id')::uuid
+                     AND (q.is_public OR q.asker_id = $4)
+               ))))
+        ORDER BY e.publish_seq
+        LIMIT $5`,
+		tenderId, params.LastEventId, isResponsible, userId, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
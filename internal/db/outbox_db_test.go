@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+	"time"
+)
+
+func TestTenderEventsResumeByPublishSequence(t *testing.T) {
+	d := testDatabase(t)
+	ctx := context.Background()
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	if _, err := d.UpdateTenderStatus(tender.Id, model.UpdateTenderStatusParams{Status: "Closed", Username: "creator"}); err != nil {
+		t.Fatalf("close tender: %v", err)
+	}
+	publish := func(*model.OutboxEvent) error { return nil }
+
+	// Событие публикации тендера занято другим реле, поэтому событие закрытия публикуется раньше него.
+	busy, err := d.Client.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer busy.Rollback(ctx)
+	if _, err = busy.Exec(ctx, `SELECT 1 FROM outbox_event WHERE event_type = 'tender.published' FOR UPDATE`); err != nil {
+		t.Fatalf("lock event: %v", err)
+	}
+	if _, err = d.RelayOutboxEvents(10, 20, publish); err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+
+	params := model.GetTenderEventsParams{Username: "creator", Limit: 10}
+	events, err := d.GetTenderEvents(tender.Id, params)
+	if err != nil || len(events) != 1 || events[0].Type != "tender.status_changed" {
+		t.Fatalf("events before the delayed publication = %v, %v", events, err)
+	}
+	params.LastEventId = events[0].Sequence
+
+	if err = busy.Rollback(ctx); err != nil {
+		t.Fatalf("release event: %v", err)
+	}
+	if _, err = d.RelayOutboxEvents(10, 20, publish); err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+
+	events, err = d.GetTenderEvents(tender.Id, params)
+	if err != nil || len(events) != 1 || events[0].Type != "tender.published" {
+		t.Fatalf("events after the cursor = %v, %v, want the delayed publication", events, err)
+	}
+	if events[0].Sequence <= params.LastEventId {
+		t.Fatalf("delayed publication got sequence %d, not after %d", events[0].Sequence, params.LastEventId)
+	}
+}
+
+func TestTenderEventsShowBidEventsToTheirAuthor(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	bids := make(map[string]*model.Bid)
+	for _, username := range []string{"winner", "leaver", "canceler"} {
+		authorId := testEmployee(t, d, username)
+		testOrganization(t, d, username+" LLC", authorId)
+		bids[username] = testBid(t, d, tender.Id, "User", authorId)
+		_, err := d.UpdateBidStatus(bids[username].Id, model.UpdateBidStatusParams{Status: "Published", Username: username})
+		if err != nil {
+			t.Fatalf("publish bid of %s: %v", username, err)
+		}
+	}
+
+	if _, err := d.WithdrawBid(bids["leaver"].Id, model.WithdrawBidParams{Username: "leaver"}, model.WithdrawBidJSONBody{}); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+	if _, err := d.UpdateBidStatus(bids["canceler"].Id, model.UpdateBidStatusParams{Status: "Canceled", Username: "canceler"}); err != nil {
+		t.Fatalf("cancel bid: %v", err)
+	}
+	_, err := d.SubmitBidDecision(bids["winner"].Id, model.SubmitBidDecisionParams{Decision: "Accepted", Username: "creator"})
+	if err != nil {
+		t.Fatalf("accept bid: %v", err)
+	}
+	if _, err = d.RelayOutboxEvents(100, 20, func(*model.OutboxEvent) error { return nil }); err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+
+	bidEvents := func(username string) map[string]string {
+		t.Helper()
+		events, err := d.GetTenderEvents(tender.Id, model.GetTenderEventsParams{Username: username, Limit: 100})
+		if err != nil {
+			t.Fatalf("events for %s: %v", username, err)
+		}
+		result := make(map[string]string)
+		for _, event := range events {
+			if event.BidId != "" {
+				result[event.Type] = event.BidId
+			}
+		}
+		return result
+	}
+
+	want := map[string]map[string]string{
+		"winner":   {"bid.decided": bids["winner"].Id},
+		"leaver":   {"bid.withdrawn": bids["leaver"].Id},
+		"canceler": {"bid.status_changed": bids["canceler"].Id},
+	}
+	for username, expected := range want {
+		got := bidEvents(username)
+		if len(got) != len(expected) {
+			t.Fatalf("%s sees bid events %v, want %v", username, got, expected)
+		}
+		for eventType, bidId := range expected {
+			if got[eventType] != bidId {
+				t.Fatalf("%s sees bid events %v, want %v", username, got, expected)
+			}
+		}
+	}
+
+	// Ответственные видят поданные предложения, но не события чужих предложений после подачи.
+	if got := bidEvents("creator"); len(got) != 1 || got["bid.submitted"] == "" {
+		t.Fatalf("creator sees bid events %v, want only bid.submitted", got)
+	}
+}
+
+// expectOutboxEvent проверяет, что mutate записывает в outbox ровно одно событие eventType.
+func expectOutboxEvent(t *testing.T, d *Database, eventType string, mutate func() error) {
+	t.Helper()
+	before := testCount(t, d, `SELECT count(*) FROM outbox_event`)
+	if err := mutate(); err != nil {
+		t.Fatalf("%s: %v", eventType, err)
+	}
+	if added := testCount(t, d, `SELECT count(*) FROM outbox_event`) - before; added != 1 {
+		t.Fatalf("%s wrote %d outbox events, want 1", eventType, added)
+	}
+	var recorded string
+	err := d.Client.QueryRow(context.Background(), `SELECT event_type FROM outbox_event ORDER BY id DESC LIMIT 1`).Scan(&recorded)
+	if err != nil || recorded != eventType {
+		t.Fatalf("last outbox event = %q, %v, want %s", recorded, err, eventType)
+	}
+}
+
+func TestMutationsRecordOutboxEvents(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	testOrganization(t, d, "Supplier", supplierId)
+	body := model.CreateTenderJSONBody{}
+	price := &model.Money{Amount: 50000, Currency: "RUB"}
+
+	t.Run("auction offer", func(t *testing.T) {
+		tender := testTender(t, d, organizationId, "creator", body, true)
+		bid := testBid(t, d, tender.Id, "User", supplierId)
+		_, err := d.ConfigureAuction(tender.Id, model.ConfigureAuctionParams{Username: "creator"}, model.ConfigureAuctionJSONBody{
+			StartsAt:     time.Now().Add(time.Hour),
+			EndsAt:       time.Now().Add(2 * time.Hour),
+			MinDecrement: model.Money{Amount: 100, Currency: "RUB"},
+		})
+		if err != nil {
+			t.Fatalf("configure auction: %v", err)
+		}
+		accept := func(*model.Auction, int64, time.Time) error { return nil }
+		expectOutboxEvent(t, d, "bid.edited", func() error {
+			_, err := d.PlaceAuctionOffer(tender.Id, model.PlaceAuctionOfferParams{Username: "supplier"},
+				model.PlaceAuctionOfferJSONBody{BidId: bid.Id, Amount: 5000}, accept)
+			return err
+		})
+	})
+
+	t.Run("accepted counter-offer", func(t *testing.T) {
+		tender := testTender(t, d, organizationId, "creator", body, true)
+		bid := testBid(t, d, tender.Id, "User", supplierId)
+		_, err := d.CreateCounterOffer(bid.Id, model.CreateCounterOfferParams{Username: "creator"},
+			model.CreateCounterOfferJSONBody{Price: price})
+		if err != nil {
+			t.Fatalf("counter-offer: %v", err)
+		}
+		expectOutboxEvent(t, d, "bid.edited", func() error {
+			_, err := d.AcceptCounterOffer(bid.Id, model.RespondCounterOfferParams{Username: "supplier"})
+			return err
+		})
+	})
+
+	t.Run("best and final offer round", func(t *testing.T) {
+		tender := testTender(t, d, organizationId, "creator", body, true)
+		bid := testBid(t, d, tender.Id, "User", supplierId)
+		_, err := d.SetTenderShortlist(tender.Id, model.SetTenderShortlistParams{Username: "creator"},
+			model.SetTenderShortlistJSONBody{BidIds: []string{bid.Id}})
+		if err != nil {
+			t.Fatalf("shortlist: %v", err)
+		}
+		expectOutboxEvent(t, d, "tender.bafo_opened", func() error {
+			_, err := d.OpenBafoRound(tender.Id, model.OpenBafoRoundParams{Username: "creator"},
+				model.OpenBafoRoundJSONBody{Deadline: time.Now().Add(time.Hour)})
+			return err
+		})
+		expectOutboxEvent(t, d, "bid.edited", func() error {
+			_, err := d.SubmitBafo(bid.Id, model.SubmitBafoParams{Username: "supplier"}, model.SubmitBafoJSONBody{Price: price})
+			return err
+		})
+	})
+
+	t.Run("award delivery and cancellation", func(t *testing.T) {
+		awards := make([]string, 2)
+		for i := range awards {
+			tender := testTender(t, d, organizationId, "creator", body, true)
+			bid := testBid(t, d, tender.Id, "User", supplierId)
+			_, err := d.SubmitBidDecision(bid.Id, model.SubmitBidDecisionParams{Decision: "Accepted", Username: "creator"})
+			if err != nil {
+				t.Fatalf("accept bid: %v", err)
+			}
+			err = d.Client.QueryRow(context.Background(), `SELECT id FROM tender_award WHERE bid_id = $1`, bid.Id).Scan(&awards[i])
+			if err != nil {
+				t.Fatalf("award: %v", err)
+			}
+		}
+
+		onTime := true
+		expectOutboxEvent(t, d, "bid.award_delivered", func() error {
+			_, err := d.RecordAwardDelivery(awards[0], model.RecordAwardDeliveryParams{Username: "creator"},
+				model.RecordAwardDeliveryJSONBody{OnTime: &onTime})
+			return err
+		})
+		expectOutboxEvent(t, d, "bid.award_canceled", func() error {
+			_, err := d.CancelTenderAward(awards[1], model.CancelTenderAwardParams{Username: "creator"},
+				model.CancelTenderAwardJSONBody{Reason: "Supplier refused"})
+			return err
+		})
+	})
+
+	t.Run("lots", func(t *testing.T) {
+		tender := testTender(t, d, organizationId, "creator", body, false)
+		var lot *model.TenderLot
+		expectOutboxEvent(t, d, "tender.lot_created", func() error {
+			var err error
+			lot, err = d.CreateTenderLot(tender.Id, model.CreateTenderLotParams{Username: "creator"},
+				model.CreateTenderLotJSONBody{Name: "Lot", Description: "Lot", ServiceType: "Construction"})
+			return err
+		})
+		expectOutboxEvent(t, d, "tender.lot_canceled", func() error {
+			_, err := d.CancelTenderLot(tender.Id, lot.Id, model.CancelTenderLotParams{Username: "creator"})
+			return err
+		})
+	})
+}
@@ -0,0 +1,219 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// questionColumns - список колонок вопроса в порядке, который ожидает scanQuestion.
+// Последняя колонка сравнивает автора вопроса с запрашивающим ($1 - id сотрудника).
+const questionColumns = `id, tender_id, question, answer, is_public, answered_at, created_at, asker_id = $1`
+
+// scanQuestion сканирует строку, выбранную по questionColumns.
+func scanQuestion(row pgx.Row) (*model.TenderQuestion, error) {
+	var question model.TenderQuestion
+	var answer *string
+
+	if err := row.Scan(
+		&question.Id,
+		&question.TenderId,
+		&question.Question,
+		&answer,
+		&question.Public,
+		&question.AnsweredAt,
+		&question.CreatedAt,
+		&question.Own,
+	); err != nil {
+		return nil, err
+	}
+
+	if answer != nil {
+		question.Answer = *answer
+	}
+
+	return &question, nil
+}
+
+// employeeId возвращает id сотрудника по username.
+func employeeId(ctx context.Context, q querier, username string) (string, error) {
+	var id string
+	err := q.QueryRow(ctx, `SELECT id FROM employee WHERE username = $1`, username).Scan(&id)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return "", errors.New("user not found")
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+func (d *Database) CreateTenderQuestion(tenderId string, params model.CreateTenderQuestionParams,
+	body model.CreateTenderQuestionJSONBody) (*model.TenderQuestion, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	askerId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	var status string
+	if err = tx.QueryRow(ctx, `SELECT status FROM tender WHERE id = $1`, tenderId).Scan(&status); err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("tender not found")
+		}
+		return nil, err
+	}
+	if status != "Published" {
+		return nil, errors.New("questions can only be asked on a published tender")
+	}
+
+	if err = checkTenderVisible(ctx, tx, tenderId, params.Username); err != nil {
+		return nil, err
+	}
+
+	question, err := scanQuestion(tx.QueryRow(ctx, `
+        INSERT INTO tender_question (tender_id, asker_id, question)
+        VALUES ($2, $1, $3)
+        RETURNING `+questionColumns,
+		askerId, tenderId, body.Question))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "question.create",
+		EntityType: "question", EntityId: question.Id}, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+func (d *Database) AnswerTenderQuestion(tenderId, questionId string, params model.AnswerTenderQuestionParams,
+	body model.AnswerTenderQuestionJSONBody) (*model.TenderQuestion, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	answererId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := auditSnapshot(ctx, tx, "question", questionId)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := scanQuestion(tx.QueryRow(ctx, `
+        UPDATE tender_question
+        SET answer = $4, is_public = $5, answered_by = $1, answered_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND tender_id = $3
+        RETURNING `+questionColumns,
+		answererId, questionId, tenderId, body.Answer, body.Public))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("question not found")
+		}
+		return nil, err
+	}
+
+	if err = recordQuestionEvent(ctx, tx, "question.answered", questionId); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "question.answer",
+		EntityType: "question", EntityId: questionId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+// GetTenderQuestions возвращает вопросы тендера: ответственным организации - все,
+// остальным - публичные ответы и собственные вопросы.
+func (d *Database) GetTenderQuestions(tenderId string, params model.GetTenderQuestionsParams) ([]*model.TenderQuestion, error) {
+	var questions []*model.TenderQuestion
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	requesterId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkTenderVisible(ctx, conn, tenderId, params.Username); err != nil {
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+questionColumns+`
+        FROM tender_question
+        WHERE tender_id = $2
+          AND ($3 OR asker_id = $1 OR (is_public AND answer IS NOT NULL))
+        ORDER BY created_at DESC, id
+        LIMIT $4 OFFSET $5`,
+		requesterId, tenderId, isResponsible, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestTenderQuestionsVisibility(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	testEmployee(t, d, "alice")
+	testEmployee(t, d, "bob")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+
+	ask := func(username, text string) *model.TenderQuestion {
+		t.Helper()
+		question, err := d.CreateTenderQuestion(tender.Id, model.CreateTenderQuestionParams{Username: username},
+			model.CreateTenderQuestionJSONBody{Question: text})
+		if err != nil {
+			t.Fatalf("ask %q: %v", text, err)
+		}
+		return question
+	}
+	answer := func(question *model.TenderQuestion, public bool) {
+		t.Helper()
+		_, err := d.AnswerTenderQuestion(tender.Id, question.Id, model.AnswerTenderQuestionParams{Username: "creator"},
+			model.AnswerTenderQuestionJSONBody{Answer: "answer", Public: public})
+		if err != nil {
+			t.Fatalf("answer %q: %v", question.Question, err)
+		}
+	}
+
+	public := ask("alice", "public")
+	private := ask("alice", "private")
+	ask("alice", "unanswered")
+	answer(public, true)
+	answer(private, false)
+
+	_, err := d.AnswerTenderQuestion(tender.Id, public.Id, model.AnswerTenderQuestionParams{Username: "bob"},
+		model.AnswerTenderQuestionJSONBody{Answer: "spoof", Public: true})
+	if err == nil {
+		t.Fatal("non-responsible user answered a question")
+	}
+
+	visible := func(username string) map[string]bool {
+		t.Helper()
+		questions, err := d.GetTenderQuestions(tender.Id, model.GetTenderQuestionsParams{Username: username, Limit: 10})
+		if err != nil {
+			t.Fatalf("questions for %s: %v", username, err)
+		}
+		result := make(map[string]bool)
+		for _, question := range questions {
+			result[question.Question] = question.Own
+		}
+		return result
+	}
+
+	if got := visible("creator"); len(got) != 3 {
+		t.Fatalf("responsible sees %v, want all 3 questions", got)
+	}
+	if got := visible("alice"); len(got) != 3 || !got["private"] {
+		t.Fatalf("asker sees %v, want all own questions", got)
+	}
+	got := visible("bob")
+	if own, ok := got["public"]; len(got) != 1 || !ok || own {
+		t.Fatalf("other user sees %v, want only the public answer", got)
+	}
+}
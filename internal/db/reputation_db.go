@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// reputationDelta - приращения счетчиков репутации поставщика от одного события.
+type reputationDelta struct {
+	reviews          int
+	reviewScore      int
+	decided          int
+	won              int
+	awards           int
+	awardsCanceled   int
+	deliveries       int
+	deliveriesOnTime int
+}
+
+// decisionDelta считает изменение счетчиков решений при смене итогового решения по предложению.
+func decisionDelta(previous, next string) reputationDelta {
+	var delta reputationDelta
+	if previous != "" {
+		delta.decided--
+	}
+	if next != "" {
+		delta.decided++
+	}
+	if previous == "Accepted" {
+		delta.won--
+	}
+	if next == "Accepted" {
+		delta.won++
+	}
+	return delta
+}
+
+// bumpReputation прибавляет delta к репутации сотрудника-автора предложения bidId и к репутации
+// организаций, за которые он отвечает на момент события. Автор - всегда сотрудник, поэтому организации
+// определяются через organization_responsible при любом author_type.
+func bumpReputation(ctx context.Context, q querier, bidId string, delta reputationDelta) error {
+	if delta == (reputationDelta{}) {
+		return nil
+	}
+
+	_, err := q.Exec(ctx, `
+        INSERT INTO supplier_reputation AS r (subject_type, subject_id, review_count, review_score_sum,
+                                              bids_decided, bids_won, awards, awards_canceled,
+                                              deliveries, deliveries_on_time)
+        SELECT s.subject_type, s.subject_id, $2, $3, $4, $5, $6, $7, $8, $9
+        FROM bid b
+        CROSS JOIN LATERAL (
+            SELECT 'User'::author_type AS subject_type, b.author_id AS subject_id
+            UNION
+            SELECT 'Organization'::author_type, o.organization_id
+            FROM organization_responsible o
+            WHERE o.user_id = b.author_id
+        ) s
+        WHERE b.id = $1
+        ON CONFLICT (subject_type, subject_id) DO UPDATE SET
+            review_count = r.review_count + EXCLUDED.review_count,
+            review_score_sum = r.review_score_sum + EXCLUDED.review_score_sum,
+            bids_decided = r.bids_decided + EXCLUDED.bids_decided,
+            bids_won = r.bids_won + EXCLUDED.bids_won,
+            awards = r.awards + EXCLUDED.awards,
+            awards_canceled = r.awards_canceled + EXCLUDED.awards_canceled,
+            deliveries = r.deliveries + EXCLUDED.deliveries,
+            deliveries_on_time = r.deliveries_on_time + EXCLUDED.deliveries_on_time,
+            updated_at = CURRENT_TIMESTAMP`,
+		bidId, delta.reviews, delta.reviewScore, delta.decided, delta.won, delta.awards, delta.awardsCanceled,
+		delta.deliveries, delta.deliveriesOnTime)
+	return err
+}
+
+// bidDecision возвращает текущее итоговое решение по предложению или пустую строку.
+func bidDecision(ctx context.Context, q querier, bidId string) (string, error) {
+	var decision *string
+	if err := q.QueryRow(ctx, `SELECT decision FROM bid WHERE id = $1`, bidId).Scan(&decision); err != nil {
+		return "", err
+	}
+	if decision == nil {
+		return "", nil
+	}
+	return *decision, nil
+}
+
+const reputationColumns = `subject_type, subject_id, review_count, review_score_sum, bids_decided, bids_won,
+        awards, awards_canceled, deliveries, deliveries_on_time, updated_at`
+
+func scanReputation(row pgx.Row) (*model.SupplierReputation, error) {
+	var reputation model.SupplierReputation
+	var scoreSum int32
+
+	if err := row.Scan(
+		&reputation.SubjectType,
+		&reputation.SubjectId,
+		&reputation.ReviewCount,
+		&scoreSum,
+		&reputation.BidsDecided,
+		&reputation.BidsWon,
+		&reputation.Awards,
+		&reputation.AwardsCanceled,
+		&reputation.Deliveries,
+		&reputation.OnTimeDeliveries,
+		&reputation.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if reputation.ReviewCount > 0 {
+		reputation.AverageScore = float64(scoreSum) / float64(reputation.ReviewCount)
+	}
+	if reputation.BidsDecided > 0 {
+		reputation.WinRate = float64(reputation.BidsWon) / float64(reputation.BidsDecided)
+	}
+	if reputation.Deliveries > 0 {
+		reputation.OnTimeRate = float64(reputation.OnTimeDeliveries) / float64(reputation.Deliveries)
+	}
+
+	return &reputation, nil
+}
+
+// GetOrganizationReputation возвращает репутацию организации как поставщика.
+// У организации без истории все показатели нулевые.
+func (d *Database) GetOrganizationReputation(organizationId string, params model.GetOrganizationReputationParams) (*model.SupplierReputation, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	if _, err = employeeId(ctx, conn, params.Username); err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	err = conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM organization WHERE id = $1)`, organizationId).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("organization not found")
+	}
+
+	reputation, err := scanReputation(conn.QueryRow(ctx, `
+        SELECT `+reputationColumns+`
+        FROM supplier_reputation
+        WHERE subject_type = 'Organization' AND subject_id = $1`,
+		organizationId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return &model.SupplierReputation{SubjectType: "Organization", SubjectId: organizationId}, nil
+		}
+		return nil, err
+	}
+
+	return reputation, nil
+}
+
+// attachAuthorReputation одним запросом заполняет репутацию сотрудников-авторов у списка предложений.
+func attachAuthorReputation(ctx context.Context, q querier, bids []*model.Bid) error {
+	if len(bids) == 0 {
+		return nil
+	}
+
+	authorIds := make([]string, 0, len(bids))
+	for _, bid := range bids {
+		authorIds = append(authorIds, bid.AuthorId)
+	}
+
+	rows, err := q.Query(ctx, `
+        SELECT `+reputationColumns+`
+        FROM supplier_reputation
+        WHERE subject_type = 'User' AND subject_id = ANY($1::uuid[])`,
+		authorIds)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	reputations := make(map[string]*model.SupplierReputation)
+	for rows.Next() {
+		reputation, err := scanReputation(rows)
+		if err != nil {
+			return err
+		}
+		reputations[reputation.SubjectId] = reputation
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, bid := range bids {
+		reputation, ok := reputations[bid.AuthorId]
+		if !ok {
+			reputation = &model.SupplierReputation{SubjectType: "User", SubjectId: bid.AuthorId}
+		}
+		bid.AuthorReputation = reputation
+	}
+
+	return nil
+}
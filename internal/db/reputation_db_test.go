@@ -0,0 +1,74 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestFeedbackOnOrganizationBidCreditsAuthorOrganization(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	supplierOrganizationId := testOrganization(t, d, "Supplier", supplierId)
+
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	bid := testBid(t, d, tender.Id, "Organization", supplierId)
+
+	rating := int32(4)
+	_, err := d.SubmitBidFeedback(bid.Id, model.SubmitBidFeedbackParams{
+		BidFeedback: "Good",
+		Username:    "creator",
+		Rating:      &rating,
+	})
+	if err != nil {
+		t.Fatalf("submit feedback: %v", err)
+	}
+
+	reputation, err := d.GetOrganizationReputation(supplierOrganizationId,
+		model.GetOrganizationReputationParams{Username: "creator"})
+	if err != nil {
+		t.Fatalf("get organization reputation: %v", err)
+	}
+	if reputation.ReviewCount != 1 || reputation.AverageScore != 4 {
+		t.Fatalf("organization reputation = %+v, want one review with score 4", reputation)
+	}
+	if count := testCount(t, d, `SELECT count(*) FROM supplier_reputation WHERE subject_type = 'Organization' AND subject_id = $1`,
+		supplierId); count != 0 {
+		t.Fatalf("employee id was credited as an organization")
+	}
+
+	bids, err := d.GetBidsForTender(tender.Id, model.GetBidsForTenderParams{
+		Username:       "creator",
+		Limit:          10,
+		WithReputation: true,
+	})
+	if err != nil {
+		t.Fatalf("get bids: %v", err)
+	}
+	if len(bids) != 1 || bids[0].AuthorReputation == nil || bids[0].AuthorReputation.ReviewCount != 1 {
+		t.Fatalf("author reputation = %+v, want the review of the author", bids[0].AuthorReputation)
+	}
+}
+
+func TestFeedbackWithoutRatingKeepsReputation(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	testOrganization(t, d, "Supplier", supplierId)
+
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	bid := testBid(t, d, tender.Id, "User", supplierId)
+
+	_, err := d.SubmitBidFeedback(bid.Id, model.SubmitBidFeedbackParams{BidFeedback: "Noted", Username: "creator"})
+	if err != nil {
+		t.Fatalf("submit feedback: %v", err)
+	}
+	if count := testCount(t, d, `SELECT count(*) FROM bid_review WHERE bid_id = $1 AND rating IS NULL`, bid.Id); count != 1 {
+		t.Fatalf("feedback without rating stored %d unrated reviews", count)
+	}
+	if count := testCount(t, d, `SELECT count(*) FROM supplier_reputation WHERE review_count > 0`); count != 0 {
+		t.Fatalf("feedback without rating changed %d reputations", count)
+	}
+}
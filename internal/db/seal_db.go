@@ -0,0 +1,312 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log"
+)
+
+// sealedBidContent - часть предложения, скрытая от владельца тендера до вскрытия.
+type sealedBidContent struct {
+	Description string       `json:"description"`
+	Price       *model.Money `json:"price,omitempty"`
+}
+
+// sealBidContent шифрует описание и цену предложения серверным ключом.
+func (d *Database) sealBidContent(content sealedBidContent) ([]byte, error) {
+	if d.Sealer == nil {
+		return nil, errors.New("bid sealing key is not configured")
+	}
+
+	plaintext, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Sealer.Seal(plaintext)
+}
+
+// openBidContent расшифровывает содержимое, зашифрованное sealBidContent.
+func (d *Database) openBidContent(payload []byte) (sealedBidContent, error) {
+	var content sealedBidContent
+	if d.Sealer == nil {
+		return content, errors.New("bid sealing key is not configured")
+	}
+
+	plaintext, err := d.Sealer.Open(payload)
+	if err != nil {
+		return content, err
+	}
+
+	err = json.Unmarshal(plaintext, &content)
+	return content, err
+}
+
+// unsealBid подставляет в запечатанное предложение его содержимое. Вызывается только
+// для автора предложения - остальные видят лишь метаданные.
+func (d *Database) unsealBid(bid *model.Bid) error {
+	if len(bid.SealedPayload) == 0 {
+		return nil
+	}
+
+	content, err := d.openBidContent(bid.SealedPayload)
+	if err != nil {
+		return err
+	}
+
+	bid.Description = content.Description
+	bid.Price = content.Price
+	return nil
+}
+
+// checkSubmissionOpen проверяет, что тендер опубликован и еще принимает предложения, и сообщает,
+// нужно ли запечатывать их содержимое.
+func checkSubmissionOpen(ctx context.Context, q querier, tenderId string) (bool, error) {
+	var sealed, deadlinePassed, revealed, bafo bool
+	var status string
+	err := q.QueryRow(ctx, `
+        SELECT sealed,
+               submission_deadline IS NOT NULL AND submission_deadline <= now(),
+               revealed_at IS NOT NULL,
+               status,
+               bafo_opened_at IS NOT NULL
+        FROM tender
+        WHERE id = $1`,
+		tenderId).Scan(&sealed, &deadlinePassed, &revealed, &status, &bafo)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return false, errors.New("tender not found")
+		}
+		return false, err
+	}
+
+	if status == "Canceled" {
+		return false, errors.New("tender is canceled")
+	}
+	if status != "Published" {
+		return false, errors.New("tender is not published")
+	}
+	if bafo {
+		return false, errors.New("tender is in a best and final offer round")
+	}
+	if deadlinePassed {
+		return false, errors.New("submission deadline has passed")
+	}
+	if sealed && revealed {
+		return false, errors.New("tender bids are already revealed")
+	}
+
+	return sealed, nil
+}
+
+// revealTender расшифровывает все запечатанные предложения тендера внутри транзакции tx,
+// отмечает тендер вскрытым и пишет запись в журнал вскрытий.
+func (d *Database) revealTender(ctx context.Context, tx pgx.Tx, tenderId, revealedBy, reason string) (int, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT id, sealed_payload
+        FROM bid
+        WHERE tender_id = $1 AND sealed_payload IS NOT NULL
+        FOR UPDATE`,
+		tenderId)
+	if err != nil {
+		return 0, err
+	}
+
+	payloads := make(map[string][]byte)
+	for rows.Next() {
+		var id string
+		var payload []byte
+		if err = rows.Scan(&id, &payload); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		payloads[id] = payload
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for id, payload := range payloads {
+		content, err := d.openBidContent(payload)
+		if err != nil {
+			return 0, err
+		}
+
+		priceAmount, priceCurrency := moneyArgs(content.Price)
+		_, err = tx.Exec(ctx, `
+            UPDATE bid
+            SET description = $2, price_amount = $3, price_currency = $4, sealed_payload = NULL
+            WHERE id = $1`,
+			id, content.Description, priceAmount, priceCurrency)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE tender SET revealed_at = now() WHERE id = $1`, tenderId); err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO bid_reveal_log (tender_id, reason, revealed_by, bid_count)
+        VALUES ($1, $2, NULLIF($3, ''), $4)`,
+		tenderId, reason, revealedBy, len(payloads))
+	if err != nil {
+		return 0, err
+	}
+
+	if err = recordTenderEvent(ctx, tx, "tender.revealed", tenderId, nil); err != nil {
+		return 0, err
+	}
+
+	return len(payloads), nil
+}
+
+// revealIfDue вскрывает предложения запечатанного тендера, если срок подачи уже истек.
+func (d *Database) revealIfDue(ctx context.Context, conn *pgxpool.Conn, tenderId string) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var due bool
+	err = tx.QueryRow(ctx, `
+        SELECT sealed AND revealed_at IS NULL AND submission_deadline <= now()
+        FROM tender
+        WHERE id = $1
+        FOR UPDATE`,
+		tenderId).Scan(&due)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil
+		}
+		return err
+	}
+	if !due {
+		return nil
+	}
+
+	count, err := d.revealTender(ctx, tx, tenderId, "", "deadline")
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	log.Printf("revealed %d sealed bids of tender %s: submission deadline passed", count, tenderId)
+	return nil
+}
+
+func (d *Database) RevealTenderBids(tenderId string, params model.RevealTenderBidsParams) (*model.Tender, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var sealed, revealed bool
+	err = tx.QueryRow(ctx, `SELECT sealed, revealed_at IS NOT NULL FROM tender WHERE id = $1 FOR UPDATE`,
+		tenderId).Scan(&sealed, &revealed)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("tender not found")
+		}
+		return nil, err
+	}
+	if !sealed {
+		return nil, errors.New("tender is not sealed")
+	}
+	if revealed {
+		return nil, errors.New("tender bids are already revealed")
+	}
+	before, err := auditSnapshot(ctx, tx, "tender", tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := d.revealTender(ctx, tx, tenderId, params.Username, "manual")
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := scanTender(tx.QueryRow(ctx, `SELECT `+tenderColumns+` FROM tender WHERE id = $1`, tenderId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.reveal",
+		EntityType: "tender", EntityId: tenderId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	log.Printf("revealed %d sealed bids of tender %s: opened by %s", count, tenderId, params.Username)
+	return tender, nil
+}
+
+// RevealDueTenders вскрывает все запечатанные тендеры с истекшим сроком подачи.
+func (d *Database) RevealDueTenders() (int, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return 0, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, `
+        SELECT id
+        FROM tender
+        WHERE sealed AND revealed_at IS NULL AND submission_deadline <= now()`)
+	if err != nil {
+		return 0, err
+	}
+
+	var tenderIds []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		tenderIds = append(tenderIds, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range tenderIds {
+		if err = d.revealIfDue(ctx, conn, id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(tenderIds), nil
+}
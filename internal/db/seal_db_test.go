@@ -0,0 +1,75 @@
+package db
+
+import (
+	"bytes"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/instinctG/tender/internal/seal"
+	"testing"
+	"time"
+)
+
+func TestSealedBidStaysHiddenUntilRevealed(t *testing.T) {
+	d := testDatabase(t)
+	sealer, err := seal.NewSealer(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("new sealer: %v", err)
+	}
+	d.Sealer = sealer
+
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	deadline := time.Now().Add(time.Hour)
+	tender := testTender(t, d, organizationId, "creator",
+		model.CreateTenderJSONBody{Sealed: true, SubmissionDeadline: &deadline}, true)
+
+	authorId := testEmployee(t, d, "alice")
+	testOrganization(t, d, "Supplier", authorId)
+	bid, err := d.CreateBid(model.CreateBidJSONBody{
+		Name: "Bid", Description: "Delivery in 3 days", TenderId: tender.Id, AuthorType: "User", AuthorId: authorId,
+		Price: &model.Money{Amount: 150000, Currency: "RUB"},
+	})
+	if err != nil {
+		t.Fatalf("create bid: %v", err)
+	}
+
+	if n := testCount(t, d, `SELECT count(*) FROM bid
+        WHERE id = $1 AND description IS NULL AND price_amount IS NULL AND sealed_payload IS NOT NULL`, bid.Id); n != 1 {
+		t.Fatal("sealed bid contents are stored in plaintext")
+	}
+
+	list := func(username string) *model.Bid {
+		t.Helper()
+		bids, err := d.GetBidsForTender(tender.Id,
+			model.GetBidsForTenderParams{Username: username, Limit: 5})
+		if err != nil {
+			t.Fatalf("list bids as %s: %v", username, err)
+		}
+		if len(bids) != 1 {
+			t.Fatalf("%s sees %d bids, want 1", username, len(bids))
+		}
+		return bids[0]
+	}
+
+	if got := list("creator"); got.Description != "" || got.Price != nil {
+		t.Fatalf("tender owner sees sealed contents: %q %+v", got.Description, got.Price)
+	}
+	if got := list("alice"); got.Description != "Delivery in 3 days" || got.Price == nil || got.Price.Amount != 150000 {
+		t.Fatalf("author sees %q %+v, want own contents", got.Description, got.Price)
+	}
+	if _, err = d.SubmitBidDecision(bid.Id, model.SubmitBidDecisionParams{Decision: "Accepted", Username: "creator"}); err == nil {
+		t.Fatal("decision accepted before the bids were revealed")
+	}
+
+	// Срок подачи истек: предложения вскрываются при первом обращении.
+	testExec(t, d, `UPDATE tender SET submission_deadline = now() - interval '1 minute' WHERE id = $1`, tender.Id)
+
+	if got := list("creator"); got.Description != "Delivery in 3 days" || got.Price == nil || got.Price.Amount != 150000 {
+		t.Fatalf("tender owner sees %q %+v after reveal", got.Description, got.Price)
+	}
+	if n := testCount(t, d, `SELECT count(*) FROM tender WHERE id = $1 AND revealed_at IS NOT NULL`, tender.Id); n != 1 {
+		t.Fatal("revealed_at is not set after the deadline reveal")
+	}
+	if n := testCount(t, d, `SELECT count(*) FROM bid_reveal_log WHERE tender_id = $1 AND reason = 'deadline'`, tender.Id); n != 1 {
+		t.Fatalf("reveal log has %d deadline entries, want 1", n)
+	}
+}
@@ -0,0 +1,441 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+	"time"
+)
+
+// getTenderShortlist собирает короткий список тендера. Если authorId не пуст, в список попадают только
+// предложения этого автора.
+func getTenderShortlist(ctx context.Context, q querier, tenderId, authorId string) (*model.TenderShortlist, error) {
+	shortlist := model.TenderShortlist{TenderId: tenderId, Bids: []*model.ShortlistedBid{}}
+	var bafoStatus *string
+	err := q.QueryRow(ctx, `
+        SELECT CASE WHEN bafo_deadline IS NULL THEN NULL WHEN bafo_deadline > now() THEN 'Open' ELSE 'Closed' END,
+               bafo_deadline
+        FROM tender
+        WHERE id = $1`,
+		tenderId).Scan(&bafoStatus, &shortlist.BafoDeadline)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("tender not found")
+		}
+		return nil, err
+	}
+	if bafoStatus != nil {
+		shortlist.BafoStatus = *bafoStatus
+	}
+
+	rows, err := q.Query(ctx, `
+        SELECT b.id, b.name, b.author_type, b.author_id, s.created_at, s.bafo_submitted_at, s.bafo_version
+        FROM tender_shortlist s
+        JOIN bid b ON b.id = s.bid_id
+        WHERE s.tender_id = $1 AND ($2 = '' OR b.author_id::text = $2)
+        ORDER BY s.created_at, b.id`,
+		tenderId, authorId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bid model.ShortlistedBid
+		var bafoVersion *int32
+		if err = rows.Scan(
+			&bid.BidId,
+			&bid.BidName,
+			&bid.AuthorType,
+			&bid.AuthorId,
+			&bid.ShortlistedAt,
+			&bid.BafoSubmittedAt,
+			&bafoVersion,
+		); err != nil {
+			return nil, err
+		}
+		if bafoVersion != nil {
+			bid.BafoVersion = *bafoVersion
+		}
+		shortlist.Bids = append(shortlist.Bids, &bid)
+	}
+
+	return &shortlist, rows.Err()
+}
+
+// SetTenderShortlist заменяет короткий список тендера. Список можно менять до открытия раунда BAFO,
+// в него входят только действующие вскрытые предложения тендера без решения.
+func (d *Database) SetTenderShortlist(tenderId string, params model.SetTenderShortlistParams,
+	body model.SetTenderShortlistJSONBody) (*model.TenderShortlist, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	var bafo bool
+	err = tx.QueryRow(ctx, `SELECT status, bafo_opened_at IS NOT NULL FROM tender WHERE id = $1 FOR UPDATE`,
+		tenderId).Scan(&status, &bafo)
+	if err != nil {
+		return nil, err
+	}
+	if status != "Published" {
+		return nil, errors.New("tender is not published")
+	}
+	if bafo {
+		return nil, errors.New("shortlist is fixed once the best and final offer round is opened")
+	}
+
+	var matched int
+	err = tx.QueryRow(ctx, `
+        SELECT count(*)
+        FROM bid
+        WHERE tender_id = $1
+          AND id = ANY($2::uuid[])
+          AND status IN ('Created', 'Published')
+          AND decision IS NULL
+          AND sealed_payload IS NULL`,
+		tenderId, body.BidIds).Scan(&matched)
+	if err != nil {
+		return nil, err
+	}
+	if matched != len(body.BidIds) {
+		return nil, errors.New("shortlist may contain only active revealed bids of this tender")
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM tender_shortlist WHERE tender_id = $1`, tenderId); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+        INSERT INTO tender_shortlist (tender_id, bid_id)
+        SELECT $1, bid_id FROM unnest($2::uuid[]) AS bid_id`,
+		tenderId, body.BidIds)
+	if err != nil {
+		return nil, err
+	}
+
+	shortlist, err := getTenderShortlist(ctx, tx, tenderId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.shortlist",
+		EntityType: "tender", EntityId: tenderId}, nil, shortlist); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return shortlist, nil
+}
+
+// GetTenderShortlist возвращает короткий список ответственным организации-заказчика целиком,
+// а авторам предложений - только их собственные предложения из списка.
+func (d *Database) GetTenderShortlist(tenderId string, params model.GetTenderShortlistParams) (*model.TenderShortlist, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err = checkTenderVisible(ctx, conn, tenderId, params.Username); err != nil {
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	authorId := ""
+	if !isResponsible {
+		if authorId, err = employeeId(ctx, conn, params.Username); err != nil {
+			return nil, err
+		}
+	}
+
+	return getTenderShortlist(ctx, conn, tenderId, authorId)
+}
+
+// OpenBafoRound открывает раунд лучших и окончательных предложений для короткого списка.
+// С этого момента обычная подача и редактирование предложений по тендеру закрыты.
+func (d *Database) OpenBafoRound(tenderId string, params model.OpenBafoRoundParams,
+	body model.OpenBafoRoundJSONBody) (*model.Tender, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := auditSnapshot(ctx, tx, "tender", tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	var isAuction bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tender_auction WHERE tender_id = $1)`, tenderId).Scan(&isAuction)
+	if err != nil {
+		return nil, err
+	}
+	if isAuction {
+		return nil, errors.New("auction tenders have no best and final offer round")
+	}
+
+	tender, err := scanTender(tx.QueryRow(ctx, `
+        UPDATE tender
+        SET bafo_opened_at = CURRENT_TIMESTAMP, bafo_deadline = $2
+        WHERE id = $1
+          AND status = 'Published'
+          AND bafo_opened_at IS NULL
+          AND EXISTS (SELECT 1 FROM tender_shortlist WHERE tender_id = $1)
+        RETURNING `+tenderColumns,
+		tenderId, body.Deadline))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("best and final offer round requires a published tender with a shortlist and no previous round")
+		}
+		return nil, err
+	}
+
+	var bidIds []string
+	rows, err := tx.Query(ctx, `SELECT bid_id::text FROM tender_shortlist WHERE tender_id = $1`, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var bidId string
+		if err = rows.Scan(&bidId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		bidIds = append(bidIds, bidId)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = notifyBidAuthors(ctx, tx, tenderId, bidIds, "BafoOpened", map[string]time.Time{"deadline": body.Deadline}); err != nil {
+		return nil, err
+	}
+
+	err = recordTenderEvent(ctx, tx, "tender.bafo_opened", tenderId, map[string]any{"bafoDeadline": body.Deadline})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.bafo.open",
+		EntityType: "tender", EntityId: tenderId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+// SubmitBafo принимает единственное финальное предложение автора из короткого списка до окончания раунда.
+// Текущая версия предложения сохраняется в bid_version.
+func (d *Database) SubmitBafo(bidId string, params model.SubmitBafoParams, body model.SubmitBafoJSONBody) (*model.Bid, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	authorId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var tenderId, status string
+	var bafoOpen, submitted bool
+	err = tx.QueryRow(ctx, `
+        SELECT b.tender_id, b.status, t.bafo_deadline > now(), s.bafo_submitted_at IS NOT NULL
+        FROM bid b
+        JOIN tender t ON t.id = b.tender_id
+        JOIN tender_shortlist s ON s.tender_id = b.tender_id AND s.bid_id = b.id
+        WHERE b.id = $1 AND b.author_id = $2
+        FOR UPDATE OF b, s`,
+		bidId, authorId).Scan(&tenderId, &status, &bafoOpen, &submitted)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("bid is not shortlisted")
+		}
+		return nil, err
+	}
+	if status != "Created" && status != "Published" {
+		return nil, errors.New("bid is not active")
+	}
+	if !bafoOpen {
+		return nil, errors.New("best and final offer round is not open")
+	}
+	if submitted {
+		return nil, errors.New("best and final offer is already submitted")
+	}
+	before, err := auditSnapshot(ctx, tx, "bid", bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Price != nil {
+		if err = checkBidPrice(ctx, tx, tenderId, body.Price); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = archiveBidVersion(ctx, tx, bidId); err != nil {
+		return nil, err
+	}
+
+	priceAmount, priceCurrency := moneyArgs(body.Price)
+	bid, err := scanBid(tx.QueryRow(ctx, `
+        UPDATE bid
+        SET description = COALESCE(NULLIF($2, ''), description),
+            price_amount = COALESCE($3, price_amount),
+            price_currency = COALESCE($4, price_currency),
+            version = version + 1
+        WHERE id = $1
+        RETURNING `+bidColumns,
+		bidId, body.Description, priceAmount, priceCurrency))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE tender_shortlist SET bafo_submitted_at = CURRENT_TIMESTAMP, bafo_version = $3
+        WHERE tender_id = $1 AND bid_id = $2`,
+		tenderId, bidId, bid.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordBidEvent(ctx, tx, "bid.edited", bidId, map[string]any{"bestAndFinalOffer": true}); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.bafo", EntityType: "bid",
+		EntityId: bidId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+// GetBidVersions возвращает сохраненные предыдущие версии предложения автору и ответственным организации-заказчика.
+func (d *Database) GetBidVersions(bidId string, params model.GetBidVersionsParams) ([]*model.BidVersion, error) {
+	var versions []*model.BidVersion
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	var tenderId string
+	var isAuthor bool
+	err = conn.QueryRow(ctx, `
+        SELECT tender_id, author_id = (SELECT id FROM employee WHERE username = $2)
+        FROM bid
+        WHERE id = $1`,
+		bidId, params.Username).Scan(&tenderId, &isAuthor)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("bid not found")
+		}
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthor && !isResponsible {
+		return nil, errors.New("bid not found")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT bid_id, version, name, description, price_amount, price_currency, recorded_at
+        FROM bid_version
+        WHERE bid_id = $1
+        ORDER BY version`,
+		bidId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version model.BidVersion
+		var description, priceCurrency *string
+		var priceAmount *int64
+		if err = rows.Scan(
+			&version.BidId,
+			&version.Version,
+			&version.Name,
+			&description,
+			&priceAmount,
+			&priceCurrency,
+			&version.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		if description != nil {
+			version.Description = *description
+		}
+		version.Price = newMoney(priceAmount, priceCurrency)
+		versions = append(versions, &version)
+	}
+
+	return versions, rows.Err()
+}
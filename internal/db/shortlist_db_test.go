@@ -0,0 +1,80 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+	"time"
+)
+
+func TestShortlistAndBafoRound(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	bids := make(map[string]*model.Bid)
+	for _, username := range []string{"alice", "bob", "carol"} {
+		authorId := testEmployee(t, d, username)
+		testOrganization(t, d, username+" LLC", authorId)
+		bids[username] = testBid(t, d, tender.Id, "User", authorId)
+	}
+
+	body := model.SetTenderShortlistJSONBody{BidIds: []string{bids["alice"].Id, bids["bob"].Id}}
+	if _, err := d.SetTenderShortlist(tender.Id, model.SetTenderShortlistParams{Username: "alice"}, body); err == nil {
+		t.Fatal("bidder set the shortlist")
+	}
+	shortlist, err := d.SetTenderShortlist(tender.Id, model.SetTenderShortlistParams{Username: "creator"}, body)
+	if err != nil {
+		t.Fatalf("set shortlist: %v", err)
+	}
+	if len(shortlist.Bids) != 2 || shortlist.BafoStatus != "" {
+		t.Fatalf("shortlist = %+v", shortlist)
+	}
+
+	// Автор видит в списке только свое предложение.
+	own, err := d.GetTenderShortlist(tender.Id, model.GetTenderShortlistParams{Username: "alice"})
+	if err != nil {
+		t.Fatalf("bidder shortlist: %v", err)
+	}
+	if len(own.Bids) != 1 || own.Bids[0].BidId != bids["alice"].Id {
+		t.Fatalf("bidder shortlist = %+v", own)
+	}
+
+	bafo := model.SubmitBafoJSONBody{Description: "Final terms", Price: &model.Money{Amount: 90000, Currency: "RUB"}}
+	if _, err = d.SubmitBafo(bids["alice"].Id, model.SubmitBafoParams{Username: "alice"}, bafo); err == nil {
+		t.Fatal("final offer accepted before the round was opened")
+	}
+
+	_, err = d.OpenBafoRound(tender.Id, model.OpenBafoRoundParams{Username: "creator"},
+		model.OpenBafoRoundJSONBody{Deadline: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("open round: %v", err)
+	}
+	if n := testCount(t, d, `SELECT count(*) FROM notification WHERE tender_id = $1 AND kind = 'BafoOpened'`, tender.Id); n != 2 {
+		t.Fatalf("%d BafoOpened notifications, want 2", n)
+	}
+	if _, err = d.SetTenderShortlist(tender.Id, model.SetTenderShortlistParams{Username: "creator"}, body); err == nil {
+		t.Fatal("shortlist changed after the round was opened")
+	}
+
+	if _, err = d.SubmitBafo(bids["carol"].Id, model.SubmitBafoParams{Username: "carol"}, bafo); err == nil {
+		t.Fatal("final offer accepted from a bid outside the shortlist")
+	}
+	final, err := d.SubmitBafo(bids["alice"].Id, model.SubmitBafoParams{Username: "alice"}, bafo)
+	if err != nil {
+		t.Fatalf("submit final offer: %v", err)
+	}
+	if final.Version != bids["alice"].Version+1 || final.Price == nil || final.Price.Amount != 90000 {
+		t.Fatalf("final offer = %+v", final)
+	}
+	if _, err = d.SubmitBafo(bids["alice"].Id, model.SubmitBafoParams{Username: "alice"}, bafo); err == nil {
+		t.Fatal("second final offer accepted")
+	}
+
+	versions, err := d.GetBidVersions(bids["alice"].Id, model.GetBidVersionsParams{Username: "creator"})
+	if err != nil {
+		t.Fatalf("bid versions: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != bids["alice"].Version {
+		t.Fatalf("archived versions = %+v", versions)
+	}
+}
@@ -0,0 +1,371 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// scanTemplate сканирует строку id, organization_id, name, payload, created_at шаблона.
+func scanTemplate(row pgx.Row) (*model.TenderTemplate, error) {
+	var template model.TenderTemplate
+	var payload []byte
+
+	if err := row.Scan(
+		&template.Id,
+		&template.OrganizationId,
+		&template.Name,
+		&payload,
+		&template.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(payload, &template.Tender); err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// insertTenderContent добавляет в новый тендер лоты и критерии оценки.
+func insertTenderContent(ctx context.Context, q querier, tenderId string, lots []model.CreateTenderLotJSONBody,
+	criteria []model.CreateEvaluationCriterionJSONBody) error {
+	for _, lot := range lots {
+		budgetAmount, budgetCurrency := moneyArgs(lot.Budget)
+		_, err := q.Exec(ctx, `
+            INSERT INTO tender_lot (tender_id, name, description, service_type, budget_amount, budget_currency)
+            VALUES ($1, $2, $3, $4, $5, $6)`,
+			tenderId, lot.Name, lot.Description, lot.ServiceType, budgetAmount, budgetCurrency)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, criterion := range criteria {
+		_, err := q.Exec(ctx, `
+            INSERT INTO evaluation_criterion (tender_id, name, kind, weight)
+            VALUES ($1, $2, $3, $4)`,
+			tenderId, criterion.Name, criterion.Kind, criterion.Weight)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tenderTemplateContent собирает содержимое шаблона из существующего тендера. Отмененные лоты не переносятся.
+func tenderTemplateContent(ctx context.Context, q querier, tenderId string) (*model.TenderTemplateContent, string, error) {
+	tender, err := scanTender(q.QueryRow(ctx, `SELECT `+tenderColumns+` FROM tender WHERE id = $1`, tenderId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, "", errors.New("tender not found")
+		}
+		return nil, "", err
+	}
+
+	content := model.TenderTemplateContent{
+		Name:         tender.Name,
+		Description:  tender.Description,
+		ServiceType:  tender.ServiceType,
+		Budget:       tender.Budget,
+		StrictBudget: tender.StrictBudget,
+		Sealed:       tender.Sealed,
+		Visibility:   tender.Visibility,
+	}
+
+	rows, err := q.Query(ctx, `
+        SELECT `+lotColumns+`
+        FROM tender_lot
+        WHERE tender_id = $1 AND status <> 'Canceled'
+        ORDER BY created_at, id`,
+		tenderId)
+	if err != nil {
+		return nil, "", err
+	}
+	for rows.Next() {
+		lot, err := scanLot(rows)
+		if err != nil {
+			rows.Close()
+			return nil, "", err
+		}
+		content.Lots = append(content.Lots, model.CreateTenderLotJSONBody{
+			Name:        lot.Name,
+			Description: lot.Description,
+			ServiceType: lot.ServiceType,
+			Budget:      lot.Budget,
+		})
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	criteria, err := getEvaluationCriteria(ctx, q, tenderId)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, criterion := range criteria {
+		content.Criteria = append(content.Criteria, model.CreateEvaluationCriterionJSONBody{
+			Name:   criterion.Name,
+			Kind:   criterion.Kind,
+			Weight: criterion.Weight,
+		})
+	}
+
+	return &content, tender.OrganizationId, nil
+}
+
+// CloneTender создает копию тендера в статусе Created вместе с лотами и критериями оценки.
+// Срок подачи не копируется - его нужно задать заново. Вложения копирует сервис.
+func (d *Database) CloneTender(tenderId string, params model.CloneTenderParams) (*model.Tender, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	content, organizationId, err := tenderTemplateContent(ctx, tx, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if content.Sealed && d.Sealer == nil {
+		return nil, errors.New("bid sealing key is not configured")
+	}
+
+	tender, err := insertTender(ctx, tx, model.CreateTenderJSONBody{
+		CreatorUsername: params.Username,
+		Description:     content.Description,
+		Name:            content.Name,
+		OrganizationId:  organizationId,
+		ServiceType:     content.ServiceType,
+		Budget:          content.Budget,
+		StrictBudget:    content.StrictBudget,
+		Sealed:          content.Sealed,
+		Visibility:      content.Visibility,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = insertTenderContent(ctx, tx, tender.Id, content.Lots, content.Criteria); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.clone",
+		EntityType: "tender", EntityId: tender.Id}, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+func (d *Database) CreateTenderTemplate(organizationId string, params model.CreateTenderTemplateParams,
+	body model.CreateTenderTemplateJSONBody) (*model.TenderTemplate, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isOrganizationResponsible(ctx, tx, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	creatorId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	content := body.Tender
+	if body.SourceTenderId != "" {
+		var sourceOrganizationId string
+		content, sourceOrganizationId, err = tenderTemplateContent(ctx, tx, body.SourceTenderId)
+		if err != nil {
+			return nil, err
+		}
+		if sourceOrganizationId != organizationId {
+			return nil, errors.New("tender not found")
+		}
+	}
+
+	payload, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := scanTemplate(tx.QueryRow(ctx, `
+        INSERT INTO tender_template (organization_id, name, payload, created_by)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, organization_id, name, payload, created_at`,
+		organizationId, body.Name, payload, creatorId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "template.create",
+		EntityType: "template", EntityId: template.Id}, nil, template); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (d *Database) GetTenderTemplates(organizationId string, params model.GetTenderTemplatesParams) ([]*model.TenderTemplate, error) {
+	var templates []*model.TenderTemplate
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isOrganizationResponsible(ctx, conn, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT id, organization_id, name, payload, created_at
+        FROM tender_template
+        WHERE organization_id = $1
+        ORDER BY name, id`,
+		organizationId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, rows.Err()
+}
+
+func (d *Database) GetTenderTemplate(templateId string, params model.InstantiateTenderTemplateParams) (*model.TenderTemplate, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	template, err := scanTemplate(conn.QueryRow(ctx, `
+        SELECT id, organization_id, name, payload, created_at
+        FROM tender_template
+        WHERE id = $1`,
+		templateId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("template not found")
+		}
+		return nil, err
+	}
+
+	isResponsible, err := isOrganizationResponsible(ctx, conn, template.OrganizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("template not found")
+	}
+
+	return template, nil
+}
+
+// CreateTenderWithContent атомарно создает тендер вместе с лотами и критериями оценки.
+func (d *Database) CreateTenderWithContent(params model.CreateTenderJSONBody, lots []model.CreateTenderLotJSONBody,
+	criteria []model.CreateEvaluationCriterionJSONBody) (*model.Tender, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isOrganizationResponsible(ctx, conn, params.OrganizationId, params.CreatorUsername)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	if params.Sealed && d.Sealer == nil {
+		return nil, errors.New("bid sealing key is not configured")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tender, err := insertTender(ctx, tx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = insertTenderContent(ctx, tx, tender.Id, lots, criteria); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.CreatorUsername, Action: "tender.create",
+		EntityType: "tender", EntityId: tender.Id}, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
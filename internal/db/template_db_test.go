@@ -0,0 +1,88 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestCloneTenderAndTemplateCopyLotsAndCriteria(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	strangerId := testEmployee(t, d, "stranger")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	testOrganization(t, d, "Other", strangerId)
+	source := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{
+		Name:   "Roof repair",
+		Budget: &model.Money{Amount: 500000, Currency: "RUB"},
+	}, false)
+
+	lot := func(name string) *model.TenderLot {
+		t.Helper()
+		created, err := d.CreateTenderLot(source.Id, model.CreateTenderLotParams{Username: "creator"},
+			model.CreateTenderLotJSONBody{Name: name, ServiceType: "Construction"})
+		if err != nil {
+			t.Fatalf("create lot %s: %v", name, err)
+		}
+		return created
+	}
+	lot("Roof")
+	canceled := lot("Gutters")
+	if _, err := d.CancelTenderLot(source.Id, canceled.Id, model.CancelTenderLotParams{Username: "creator"}); err != nil {
+		t.Fatalf("cancel lot: %v", err)
+	}
+	_, err := d.CreateEvaluationCriterion(source.Id, model.CreateEvaluationCriterionParams{Username: "creator"},
+		model.CreateEvaluationCriterionJSONBody{Name: "Price", Kind: "Price", Weight: 2})
+	if err != nil {
+		t.Fatalf("create criterion: %v", err)
+	}
+
+	if _, err = d.CloneTender(source.Id, model.CloneTenderParams{Username: "stranger"}); err == nil {
+		t.Fatal("user of another organization cloned the tender")
+	}
+	clone, err := d.CloneTender(source.Id, model.CloneTenderParams{Username: "creator"})
+	if err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	if clone.Id == source.Id || clone.Status != "Created" || clone.Name != source.Name ||
+		clone.Budget == nil || clone.Budget.Amount != 500000 {
+		t.Fatalf("clone = %+v", clone)
+	}
+	// Отмененный лот в копию не переносится.
+	if n := testCount(t, d, `SELECT count(*) FROM tender_lot WHERE tender_id = $1`, clone.Id); n != 1 {
+		t.Fatalf("clone has %d lots, want 1", n)
+	}
+	if n := testCount(t, d, `SELECT count(*) FROM evaluation_criterion WHERE tender_id = $1 AND weight = 2`, clone.Id); n != 1 {
+		t.Fatalf("clone has %d criteria, want 1", n)
+	}
+
+	_, err = d.CreateTenderTemplate(organizationId, model.CreateTenderTemplateParams{Username: "stranger"},
+		model.CreateTenderTemplateJSONBody{Name: "Roof", SourceTenderId: source.Id})
+	if err == nil {
+		t.Fatal("user of another organization created a template")
+	}
+	template, err := d.CreateTenderTemplate(organizationId, model.CreateTenderTemplateParams{Username: "creator"},
+		model.CreateTenderTemplateJSONBody{Name: "Roof", SourceTenderId: source.Id})
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if template.Tender.Name != source.Name || len(template.Tender.Lots) != 1 || len(template.Tender.Criteria) != 1 {
+		t.Fatalf("template content = %+v", template.Tender)
+	}
+
+	stored, err := d.GetTenderTemplate(template.Id, model.InstantiateTenderTemplateParams{Username: "creator"})
+	if err != nil {
+		t.Fatalf("get template: %v", err)
+	}
+	tender, err := d.CreateTenderWithContent(model.CreateTenderJSONBody{
+		CreatorUsername: "creator",
+		Name:            stored.Tender.Name,
+		OrganizationId:  organizationId,
+		ServiceType:     stored.Tender.ServiceType,
+	}, stored.Tender.Lots, stored.Tender.Criteria)
+	if err != nil {
+		t.Fatalf("instantiate template: %v", err)
+	}
+	if n := testCount(t, d, `SELECT count(*) FROM tender_lot WHERE tender_id = $1 AND name = 'Roof'`, tender.Id); n != 1 {
+		t.Fatalf("instantiated tender has %d lots, want 1", n)
+	}
+}
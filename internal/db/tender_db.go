@@ -5,10 +5,133 @@ import (
 	"errors"
 	"fmt"
 	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"log"
 )
 
+// tenderColumns - список колонок тендера в порядке, который ожидает scanTender.
+const tenderColumns = `id, name, description, status, service_type, organization_id, version, created_at,
+	budget_amount, budget_currency, strict_budget, sealed, submission_deadline, revealed_at, visibility,
+	cancel_reason, canceled_at,
+	CASE WHEN bafo_deadline IS NULL THEN NULL WHEN bafo_deadline > now() THEN 'Open' ELSE 'Closed' END, bafo_deadline`
+
+// scanTender сканирует строку, выбранную по tenderColumns. Колонки, выбранные после tenderColumns,
+// сканируются в extra.
+func scanTender(row pgx.Row, extra ...any) (*model.Tender, error) {
+	var tender model.Tender
+	var description *string
+	var budgetAmount *int64
+	var budgetCurrency *string
+	var cancelReason *string
+	var bafoStatus *string
+
+	dest := []any{
+		&tender.Id,
+		&tender.Name,
+		&description,
+		&tender.Status,
+		&tender.ServiceType,
+		&tender.OrganizationId,
+		&tender.Version,
+		&tender.CreatedAt,
+		&budgetAmount,
+		&budgetCurrency,
+		&tender.StrictBudget,
+		&tender.Sealed,
+		&tender.SubmissionDeadline,
+		&tender.RevealedAt,
+		&tender.Visibility,
+		&cancelReason,
+		&tender.CanceledAt,
+		&bafoStatus,
+		&tender.BafoDeadline,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if description != nil {
+		tender.Description = *description
+	}
+	if cancelReason != nil {
+		tender.CancelReason = *cancelReason
+	}
+	if bafoStatus != nil {
+		tender.BafoStatus = *bafoStatus
+	}
+	tender.Budget = newMoney(budgetAmount, budgetCurrency)
+
+	return &tender, nil
+}
+
+// isTenderResponsible проверяет, что пользователь является ответственным организации, которой принадлежит тендер.
+func isTenderResponsible(ctx context.Context, q querier, tenderId, username string) (bool, error) {
+	var isResponsible bool
+	err := q.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1
+            FROM organization_responsible r
+            JOIN tender t ON t.organization_id = r.organization_id
+            WHERE t.id = $1
+              AND r.user_id = (SELECT id FROM employee WHERE username = $2)
+        )`,
+		tenderId, username).Scan(&isResponsible)
+	return isResponsible, err
+}
+
+// checkTenderVisible проверяет, что пользователь видит тендер. Тендер в статусе Created видят только
+// ответственные организации-заказчика, закрытый (InviteOnly) - еще и ответственные приглашенных организаций.
+// Невидимый тендер считается ненайденным, чтобы не раскрывать его существование.
+func checkTenderVisible(ctx context.Context, q querier, tenderId, username string) error {
+	var visible bool
+	err := q.QueryRow(ctx, `
+        SELECT EXISTS (
+                   SELECT 1
+                   FROM organization_responsible r
+                   WHERE r.organization_id = t.organization_id
+                     AND r.user_id = (SELECT id FROM employee WHERE username = $2)
+               )
+               OR (t.status <> 'Created' AND (t.visibility = 'Public' OR EXISTS (
+                   SELECT 1
+                   FROM tender_invitation i
+                   JOIN organization_responsible r ON r.organization_id = i.organization_id
+                   WHERE i.tender_id = t.id
+                     AND r.user_id = (SELECT id FROM employee WHERE username = $2)
+               )))
+        FROM tender t
+        WHERE t.id = $1`,
+		tenderId, username).Scan(&visible)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return errors.New("tender not found")
+		}
+		return err
+	}
+	if !visible {
+		return errors.New("tender not found")
+	}
+	return nil
+}
+
+// tenderSearchConditions возвращает условия поиска тендеров по параметрам GET /api/tenders в виде " AND ..."
+// и выражение полнотекстового запроса, если он задан. Поиск ведется по русским и английским словоформам.
+func tenderSearchConditions(args *queryArgs, params model.GetTendersParams) (string, string) {
+	var conditions, tsQuery string
+
+	if len(params.ServiceType) > 0 {
+		conditions += " AND service_type::text = ANY(" + args.add(params.ServiceType) + "::text[])"
+	}
+
+	if params.Query != "" {
+		q := args.add(params.Query)
+		tsQuery = "(websearch_to_tsquery('russian', " + q + ") || websearch_to_tsquery('english', " + q + "))"
+		conditions += " AND search_vector @@ " + tsQuery
+	}
+
+	return conditions + tenderListSpec.conditions(args, params.Filter), tsQuery
+}
+
 func (d *Database) GetTenders(params model.GetTendersParams) ([]*model.Tender, error) {
 	var tenders []*model.Tender
 	ctx := context.Background()
@@ -19,23 +142,25 @@ func (d *Database) GetTenders(params model.GetTendersParams) ([]*model.Tender, e
 	}
 	defer conn.Release()
 
-	query := `
-        SELECT id, name, description, status, service_type, version, created_at
-        FROM tender
-        WHERE status = 'Published'
-    `
-
-	var args []interface{}
+	var args queryArgs
+	query := `SELECT ` + tenderColumns
+	conditions, tsQuery := tenderSearchConditions(&args, params)
+	where := ` FROM tender WHERE status = 'Published' AND visibility = 'Public'` + conditions
+	fallback := "name ASC"
+
+	// По умолчанию результаты полнотекстового поиска упорядочены по релевантности.
+	if tsQuery != "" {
+		query += `, ts_headline('russian', name || '. ' || coalesce(description, ''), ` + tsQuery + `,
+            'StartSel=<b>, StopSel=</b>, MaxFragments=2, MaxWords=30, MinWords=10')`
+		fallback = "ts_rank(search_vector, " + tsQuery + ") DESC, name ASC"
+	}
 
-	if len(params.ServiceType) > 0 {
-		query += " AND service_type = ANY($1)"
-		args = append(args, params.ServiceType)
-		query += " ORDER BY name ASC LIMIT $2 OFFSET $3"
-	} else {
-		query += " ORDER BY name ASC LIMIT $1 OFFSET $2"
+	order, err := tenderListSpec.orderBy(params.Filter.Sort, fallback)
+	if err != nil {
+		return nil, err
 	}
 
-	args = append(args, params.Limit, params.Offset)
+	query += where + order + " LIMIT " + args.add(params.Limit) + " OFFSET " + args.add(params.Offset)
 
 	rows, err := conn.Query(ctx, query, args...)
 	if err != nil {
@@ -44,25 +169,43 @@ func (d *Database) GetTenders(params model.GetTendersParams) ([]*model.Tender, e
 	defer rows.Close()
 
 	for rows.Next() {
-		var tender model.Tender
-		err = rows.Scan(
-			&tender.Id,
-			&tender.Name,
-			&tender.Description,
-			&tender.Status,
-			&tender.ServiceType,
-			&tender.Version,
-			&tender.CreatedAt,
-		)
+		var extra []any
+		var snippet string
+		if params.Query != "" {
+			extra = append(extra, &snippet)
+		}
+		tender, err := scanTender(rows, extra...)
 		if err != nil {
 			return nil, err
 		}
-		tenders = append(tenders, &tender)
+		tender.Snippet = snippet
+		tenders = append(tenders, tender)
 	}
 
 	return tenders, nil
 }
 
+func (d *Database) GetTender(tenderId string, params model.GetTenderParams) (*model.Tender, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err = checkTenderVisible(ctx, conn, tenderId, params.Username); err != nil {
+		return nil, err
+	}
+
+	tender, err := scanTender(conn.QueryRow(ctx, `SELECT `+tenderColumns+` FROM tender WHERE id = $1`, tenderId))
+	if err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
+
 func (d *Database) GetUserTenders(params model.GetUserTendersParams) ([]*model.Tender, error) {
 	var tenders []*model.Tender
 	ctx := context.Background()
@@ -73,39 +216,79 @@ func (d *Database) GetUserTenders(params model.GetUserTendersParams) ([]*model.T
 	}
 	defer conn.Release()
 
-	query := `SELECT id, name, description, service_type, status, version, created_at
-        FROM tender
-        WHERE creator_username=$1
-		ORDER BY created_at DESC LIMIT $2 OFFSET $3
-        `
+	args := queryArgs{params.Username}
+	query := `SELECT ` + tenderColumns + ` FROM tender WHERE creator_username = $1` +
+		tenderListSpec.conditions(&args, params.Filter)
+
+	order, err := tenderListSpec.orderBy(params.Filter.Sort, "created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	query += order + " LIMIT " + args.add(params.Limit) + " OFFSET " + args.add(params.Offset)
 
-	rows, err := conn.Query(ctx, query, params.Username, params.Limit, params.Offset)
+	rows, err := conn.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var tender model.Tender
-		if err = rows.Scan(
-			&tender.Id,
-			&tender.Name,
-			&tender.Description,
-			&tender.ServiceType,
-			&tender.Status,
-			&tender.Version,
-			&tender.CreatedAt,
-		); err != nil {
+		tender, err := scanTender(rows)
+		if err != nil {
 			return nil, err
 		}
-		tenders = append(tenders, &tender)
+		tenders = append(tenders, tender)
 	}
 
 	return tenders, nil
 }
 
+// isOrganizationResponsible проверяет, что пользователь - ответственный организации.
+func isOrganizationResponsible(ctx context.Context, q querier, organizationId, username string) (bool, error) {
+	var isResponsible bool
+	err := q.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1
+            FROM organization_responsible
+            WHERE organization_id = $1
+            AND user_id = (SELECT id FROM employee WHERE username = $2)
+        )`,
+		organizationId, username).Scan(&isResponsible)
+	return isResponsible, err
+}
+
+// insertTender создает тендер в статусе Created. Права проверяет вызывающий.
+func insertTender(ctx context.Context, q querier, params model.CreateTenderJSONBody) (*model.Tender, error) {
+	budgetAmount, budgetCurrency := moneyArgs(params.Budget)
+
+	query := `
+        INSERT INTO tender (name, description, service_type,organization_id, creator_username,
+                            budget_amount, budget_currency, strict_budget, sealed, submission_deadline, visibility)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE(NULLIF($11, ''), 'Public')::tender_visibility)
+        RETURNING ` + tenderColumns + `;
+    `
+
+	row := q.QueryRow(ctx, query, params.Name, params.Description, params.ServiceType, params.OrganizationId,
+		params.CreatorUsername, budgetAmount, budgetCurrency, params.StrictBudget, params.Sealed, params.SubmissionDeadline,
+		params.Visibility)
+
+	createdTender, err := scanTender(row)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			log.Println(pgErr.Message)
+		}
+		log.Printf("Unable to scan JSON body: %v", err)
+		return nil, err
+	}
+
+	if err = recordTenderEvent(ctx, q, "tender.created", createdTender.Id, nil); err != nil {
+		return nil, err
+	}
+
+	return createdTender, nil
+}
+
 func (d *Database) CreateTender(params model.CreateTenderJSONBody) (*model.Tender, error) {
-	var createdTender model.Tender
 	ctx := context.Background()
 	conn, err := d.Client.Acquire(ctx)
 	if err != nil {
@@ -114,16 +297,7 @@ func (d *Database) CreateTender(params model.CreateTenderJSONBody) (*model.Tende
 	}
 	defer conn.Release()
 
-	var isResponsible bool
-	err = conn.QueryRow(ctx, `
-        SELECT EXISTS (
-            SELECT 1 
-            FROM organization_responsible 
-            WHERE organization_id = $1 
-            AND user_id = (SELECT id FROM employee WHERE username = $2)
-        )`,
-		params.OrganizationId, params.CreatorUsername).Scan(&isResponsible)
-
+	isResponsible, err := isOrganizationResponsible(ctx, conn, params.OrganizationId, params.CreatorUsername)
 	if err != nil {
 		return nil, err
 	}
@@ -132,37 +306,33 @@ func (d *Database) CreateTender(params model.CreateTenderJSONBody) (*model.Tende
 		return nil, errors.New("user is not responsible for this organization")
 	}
 
-	query := `
-        INSERT INTO tender (name, description, service_type,organization_id, creator_username)
-        VALUES ($1, $2, $3, $4, $5)
-        RETURNING id,name,description,status,service_type,version,created_at;
-    `
-
-	row := conn.QueryRow(ctx, query, params.Name, params.Description, params.ServiceType, params.OrganizationId, params.CreatorUsername)
+	if params.Sealed && d.Sealer == nil {
+		return nil, errors.New("bid sealing key is not configured")
+	}
 
-	err = row.Scan(
-		&createdTender.Id,
-		&createdTender.Name,
-		&createdTender.Description,
-		&createdTender.Status,
-		&createdTender.ServiceType,
-		&createdTender.Version,
-		&createdTender.CreatedAt,
-	)
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
 
+	createdTender, err := insertTender(ctx, tx, params)
 	if err != nil {
-		if pgErr, ok := err.(*pgconn.PgError); ok {
-			log.Println(pgErr.Message)
-		}
-		log.Fatalf("Unable to scan JSON body: %w", err)
 		return nil, err
 	}
 
-	return &createdTender, nil
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.CreatorUsername, Action: "tender.create",
+		EntityType: "tender", EntityId: createdTender.Id}, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return createdTender, nil
 }
 
 func (d *Database) EditTender(tenderId string, par model.EditTenderParams, params model.EditTenderJSONBody) (*model.Tender, error) {
-	var updatedTender model.Tender
 	ctx := context.Background()
 	conn, err := d.Client.Acquire(ctx)
 	if err != nil {
@@ -171,14 +341,32 @@ func (d *Database) EditTender(tenderId string, par model.EditTenderParams, param
 	}
 	defer conn.Release()
 
-	err = conn.QueryRow(ctx, `
+	budgetAmount, budgetCurrency := moneyArgs(params.Budget)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := auditSnapshot(ctx, tx, "tender", tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedTender, err := scanTender(tx.QueryRow(ctx, `
         UPDATE tender
         SET name = COALESCE($1, name),
             description = COALESCE($2, description),
             service_type = COALESCE($3, service_type),
+            budget_amount = COALESCE($6, budget_amount),
+            budget_currency = COALESCE($7, budget_currency),
+            strict_budget = COALESCE($8, strict_budget),
+            submission_deadline = COALESCE($9, submission_deadline),
+            visibility = COALESCE(NULLIF($10, '')::tender_visibility, visibility),
             version = version + 1
         WHERE id = $4 AND creator_username = $5
-        RETURNING id, name, description, service_type, status, version, created_at
+        RETURNING `+tenderColumns+`
     `,
 		func() *string {
 			if params.Name != "" {
@@ -201,15 +389,8 @@ func (d *Database) EditTender(tenderId string, par model.EditTenderParams, param
 				return nil
 			}
 		}(),
-		tenderId, par.Username).Scan(
-		&updatedTender.Id,
-		&updatedTender.Name,
-		&updatedTender.Description,
-		&updatedTender.ServiceType,
-		&updatedTender.Status,
-		&updatedTender.Version,
-		&updatedTender.CreatedAt,
-	)
+		tenderId, par.Username, budgetAmount, budgetCurrency, params.StrictBudget, params.SubmissionDeadline,
+		params.Visibility))
 
 	if err != nil {
 		if err.Error() == "no rows in result set" {
@@ -218,7 +399,28 @@ func (d *Database) EditTender(tenderId string, par model.EditTenderParams, param
 		return nil, err
 	}
 
-	return &updatedTender, nil
+	// Бюджет и строгость могут прийти в разных правках, поэтому проверяется уже объединенное состояние.
+	if updatedTender.StrictBudget && updatedTender.Budget == nil {
+		return nil, errors.New("strict budget requires a budget")
+	}
+
+	if err = notifyTenderSubscribers(ctx, tx, tenderId, "Edited"); err != nil {
+		return nil, err
+	}
+
+	if err = recordTenderEvent(ctx, tx, "tender.edited", tenderId, nil); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: par.Username, Action: "tender.edit",
+		EntityType: "tender", EntityId: tenderId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return updatedTender, nil
 }
 
 func (d *Database) RollbackTender(tenderId string, version int32, params model.RollbackTenderParams) (*model.Tender, error) {
@@ -244,6 +446,7 @@ func (d *Database) GetTenderStatus(tenderId string, params model.GetTenderStatus
 		query += ` AND creator_username = $2`
 		err = conn.QueryRow(ctx, query, tenderId, params.Username).Scan(&status)
 	} else {
+		query += ` AND visibility = 'Public'`
 		err = conn.QueryRow(ctx, query, tenderId).Scan(&status)
 	}
 
@@ -257,7 +460,6 @@ func (d *Database) GetTenderStatus(tenderId string, params model.GetTenderStatus
 }
 
 func (d *Database) UpdateTenderStatus(tenderId string, params model.UpdateTenderStatusParams) (*model.Tender, error) {
-	var updatedTender model.Tender
 	ctx := context.Background()
 	conn, err := d.Client.Acquire(ctx)
 	if err != nil {
@@ -266,22 +468,24 @@ func (d *Database) UpdateTenderStatus(tenderId string, params model.UpdateTender
 	}
 	defer conn.Release()
 
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `UPDATE tender SET
 			  status = $1
-			  WHERE id = $2 AND creator_username = $3
-			  RETURNING id,name,description,status,service_type,version,created_at
+			  WHERE id = $2 AND creator_username = $3 AND status <> 'Canceled'
+			  RETURNING ` + tenderColumns + `
               `
 
-	err = conn.QueryRow(ctx, query, params.Status, tenderId, params.Username).Scan(
-		&updatedTender.Id,
-		&updatedTender.Name,
-		&updatedTender.Description,
-		&updatedTender.Status,
-		&updatedTender.ServiceType,
-		&updatedTender.Version,
-		&updatedTender.CreatedAt,
-	)
+	before, err := auditSnapshot(ctx, tx, "tender", tenderId)
+	if err != nil {
+		return nil, err
+	}
 
+	updatedTender, err := scanTender(tx.QueryRow(ctx, query, params.Status, tenderId, params.Username))
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, errors.New("tender not found")
@@ -289,5 +493,131 @@ func (d *Database) UpdateTenderStatus(tenderId string, params model.UpdateTender
 		return nil, err
 	}
 
-	return &updatedTender, nil
+	// Многолотовый тендер закрывается сам, когда по всем лотам принято решение.
+	if updatedTender.Status == "Closed" {
+		var openLots int
+		err = tx.QueryRow(ctx, `SELECT count(*) FROM tender_lot WHERE tender_id = $1 AND status = 'Open'`,
+			tenderId).Scan(&openLots)
+		if err != nil {
+			return nil, err
+		}
+		if openLots > 0 {
+			return nil, errors.New("tender with open lots can not be closed")
+		}
+	}
+
+	if err = notifyTenderSubscribers(ctx, tx, tenderId, updatedTender.Status); err != nil {
+		return nil, err
+	}
+
+	eventType := "tender.status_changed"
+	if updatedTender.Status == "Published" {
+		eventType = "tender.published"
+	}
+	if err = recordTenderEvent(ctx, tx, eventType, tenderId, nil); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.status",
+		EntityType: "tender", EntityId: tenderId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return updatedTender, nil
+}
+
+// CancelTender отменяет тендер с указанием причины: все действующие предложения переходят
+// в конечный статус Void, а их авторам ставится в очередь уведомление об отмене.
+func (d *Database) CancelTender(tenderId string, params model.CancelTenderParams, body model.CancelTenderJSONBody) (*model.Tender, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := auditSnapshot(ctx, tx, "tender", tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := scanTender(tx.QueryRow(ctx, `
+        UPDATE tender
+        SET status = 'Canceled', cancel_reason = $2, canceled_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status IN ('Created', 'Published')
+        RETURNING `+tenderColumns,
+		tenderId, body.Reason))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("only created or published tenders can be canceled")
+		}
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+        UPDATE bid SET status = 'Void'
+        WHERE tender_id = $1 AND status IN ('Created', 'Published')
+        RETURNING id`,
+		tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	var bidIds []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		bidIds = append(bidIds, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = notifyBidAuthors(ctx, tx, tenderId, bidIds, "TenderCanceled", map[string]string{
+		"tenderName": tender.Name,
+		"reason":     tender.CancelReason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = notifyTenderSubscribers(ctx, tx, tenderId, "Canceled"); err != nil {
+		return nil, err
+	}
+
+	if err = recordTenderEvent(ctx, tx, "tender.canceled", tenderId, nil); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.cancel",
+		EntityType: "tender", EntityId: tenderId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return tender, nil
 }
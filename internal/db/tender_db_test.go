@@ -0,0 +1,110 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestEditTenderStrictBudgetRequiresBudget(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, false)
+
+	strict := true
+	_, err := d.EditTender(tender.Id, model.EditTenderParams{Username: "creator"},
+		model.EditTenderJSONBody{StrictBudget: &strict})
+	if err == nil {
+		t.Fatal("strict budget without a budget was accepted")
+	}
+
+	edited, err := d.EditTender(tender.Id, model.EditTenderParams{Username: "creator"}, model.EditTenderJSONBody{
+		StrictBudget: &strict,
+		Budget:       &model.Money{Amount: 100000, Currency: "RUB"},
+	})
+	if err != nil {
+		t.Fatalf("edit with budget: %v", err)
+	}
+	if !edited.StrictBudget || edited.Version != tender.Version+1 {
+		t.Fatalf("unexpected tender after edit: %+v", edited)
+	}
+}
+
+func TestGetTendersFullTextSearch(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	roof := testTender(t, d, organizationId, "creator",
+		model.CreateTenderJSONBody{Name: "Ремонт кровли школы", Description: "Замена покрытия кровли"}, true)
+	testTender(t, d, organizationId, "creator",
+		model.CreateTenderJSONBody{Name: "Поставка бумаги", Description: "Офисная бумага А4"}, true)
+	testTender(t, d, organizationId, "creator",
+		model.CreateTenderJSONBody{Name: "Ремонт кровли склада"}, false)
+
+	// Словоформа запроса отличается от текста тендера, черновик в выдачу не попадает.
+	tenders, err := d.GetTenders(model.GetTendersParams{Query: "кровля", Limit: 10})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(tenders) != 1 || tenders[0].Id != roof.Id {
+		t.Fatalf("search returned %+v, want only the published roof tender", tenders)
+	}
+	if !strings.Contains(tenders[0].Snippet, "<b>") {
+		t.Fatalf("snippet %q has no highlighted match", tenders[0].Snippet)
+	}
+
+	tenders, err = d.GetTenders(model.GetTendersParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(tenders) != 2 || tenders[0].Snippet != "" {
+		t.Fatalf("list without query returned %+v", tenders)
+	}
+}
+
+func TestCancelTenderVoidsBidsAndNotifiesAuthors(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	firstId := testEmployee(t, d, "first")
+	secondId := testEmployee(t, d, "second")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	contractorId := testOrganization(t, d, "Contractor", firstId, secondId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	userBid := testBid(t, d, tender.Id, "User", supplierId)
+	organizationBid := testBid(t, d, tender.Id, "Organization", contractorId)
+
+	if _, err := d.CancelTender(tender.Id, model.CancelTenderParams{Username: "supplier"},
+		model.CancelTenderJSONBody{Reason: "spoof"}); err == nil {
+		t.Fatal("bidder canceled the tender")
+	}
+	canceled, err := d.CancelTender(tender.Id, model.CancelTenderParams{Username: "creator"},
+		model.CancelTenderJSONBody{Reason: "Budget withdrawn"})
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if canceled.Status != "Canceled" || canceled.CancelReason != "Budget withdrawn" {
+		t.Fatalf("canceled tender = %+v", canceled)
+	}
+
+	if n := testCount(t, d, `SELECT count(*) FROM bid WHERE id IN ($1, $2) AND status = 'Void'`,
+		userBid.Id, organizationBid.Id); n != 2 {
+		t.Fatalf("%d bids voided, want 2", n)
+	}
+	// Автор-пользователь получает одно уведомление, организация - по одному на каждого ответственного.
+	for recipientId, want := range map[string]int{supplierId: 1, firstId: 1, secondId: 1, creatorId: 0} {
+		n := testCount(t, d, `
+            SELECT count(*) FROM notification
+            WHERE recipient_id = $1 AND tender_id = $2 AND kind = 'TenderCanceled'`,
+			recipientId, tender.Id)
+		if n != want {
+			t.Fatalf("recipient %s has %d notifications, want %d", recipientId, n, want)
+		}
+	}
+
+	if _, err = d.CancelTender(tender.Id, model.CancelTenderParams{Username: "creator"},
+		model.CancelTenderJSONBody{Reason: "again"}); err == nil {
+		t.Fatal("canceled tender was canceled again")
+	}
+}
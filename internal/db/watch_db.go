@@ -0,0 +1,445 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+const savedSearchColumns = `id, name, service_types, search_query, organization_id, created_from, created_to,
+        budget_min, budget_max, currency, created_at`
+
+// scanSavedSearch сканирует строку с колонками savedSearchColumns, дополнительные колонки в конце строки
+// сканируются в extra.
+func scanSavedSearch(row pgx.Row, extra ...any) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	var query, organizationId, currency *string
+
+	dest := []any{
+		&search.Id,
+		&search.Name,
+		&search.ServiceType,
+		&query,
+		&organizationId,
+		&search.CreatedFrom,
+		&search.CreatedTo,
+		&search.BudgetMin,
+		&search.BudgetMax,
+		&currency,
+		&search.CreatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if query != nil {
+		search.Query = *query
+	}
+	if organizationId != nil {
+		search.OrganizationId = *organizationId
+	}
+	if currency != nil {
+		search.Currency = *currency
+	}
+
+	return &search, nil
+}
+
+// savedSearchParams восстанавливает параметры GET /api/tenders, по которым был сохранен поиск.
+func savedSearchParams(search *model.SavedSearch) model.GetTendersParams {
+	return model.GetTendersParams{
+		ServiceType: search.ServiceType,
+		Query:       search.Query,
+		Filter: model.ListFilter{
+			OrganizationId: search.OrganizationId,
+			CreatedFrom:    search.CreatedFrom,
+			CreatedTo:      search.CreatedTo,
+			AmountMin:      search.BudgetMin,
+			AmountMax:      search.BudgetMax,
+			Currency:       search.Currency,
+		},
+	}
+}
+
+// notifyTenderSubscribers вызывается при публикации и изменении тендера: уведомляет тех, кто следит
+// за тендером, и прогоняет тендер через сохраненные поиски.
+func notifyTenderSubscribers(ctx context.Context, q querier, tenderId, change string) error {
+	data, err := json.Marshal(map[string]string{"change": change})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+        INSERT INTO notification (recipient_id, kind, tender_id, payload)
+        SELECT w.user_id, 'WatchedTenderChanged', w.tender_id, $2
+        FROM tender_watch w
+        WHERE w.tender_id = $1 AND `+notificationEnabled("w.user_id", "'WatchedTenderChanged'"),
+		tenderId, data)
+	if err != nil {
+		return err
+	}
+
+	return matchSavedSearches(ctx, q, tenderId)
+}
+
+// matchSavedSearches проверяет опубликованный публичный тендер условиями GET /api/tenders каждого
+// сохраненного поиска, который еще не совпадал с этим тендером, и уведомляет владельцев новых совпадений.
+func matchSavedSearches(ctx context.Context, q querier, tenderId string) error {
+	rows, err := q.Query(ctx, `
+        SELECT `+savedSearchColumns+`, user_id
+        FROM saved_search s
+        WHERE EXISTS (
+                  SELECT 1 FROM tender t
+                  WHERE t.id = $1 AND t.status = 'Published' AND t.visibility = 'Public'
+                    AND (s.service_types = '{}' OR t.service_type::text = ANY(s.service_types))
+              )
+          AND NOT EXISTS (SELECT 1 FROM saved_search_match m WHERE m.search_id = s.id AND m.tender_id = $1)`,
+		tenderId)
+	if err != nil {
+		return err
+	}
+
+	type candidate struct {
+		search *model.SavedSearch
+		userId string
+	}
+	var candidates []candidate
+	for rows.Next() {
+		var userId string
+		search, err := scanSavedSearch(rows, &userId)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		candidates = append(candidates, candidate{search, userId})
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		args := queryArgs{tenderId}
+		conditions, _ := tenderSearchConditions(&args, savedSearchParams(c.search))
+
+		var matched bool
+		err = q.QueryRow(ctx, `
+            SELECT EXISTS (
+                SELECT 1 FROM tender
+                WHERE id = $1 AND status = 'Published' AND visibility = 'Public'`+conditions+`
+            )`,
+			args...).Scan(&matched)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		if _, err = q.Exec(ctx, `INSERT INTO saved_search_match (search_id, tender_id) VALUES ($1, $2)`,
+			c.search.Id, tenderId); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(map[string]string{"searchId": c.search.Id, "searchName": c.search.Name})
+		if err != nil {
+			return err
+		}
+		_, err = q.Exec(ctx, `
+            INSERT INTO notification (recipient_id, kind, tender_id, payload)
+            SELECT $1, 'SavedSearchMatch', $2, $3
+            WHERE `+notificationEnabled("$1::uuid", "'SavedSearchMatch'"),
+			c.userId, tenderId, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WatchTender добавляет видимый пользователю тендер в его список отслеживания.
+func (d *Database) WatchTender(tenderId string, params model.WatchTenderParams) (*model.Tender, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	userId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkTenderVisible(ctx, tx, tenderId, params.Username); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO tender_watch (user_id, tender_id) VALUES ($1, $2)
+        ON CONFLICT (user_id, tender_id) DO NOTHING`,
+		userId, tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := scanTender(tx.QueryRow(ctx, `SELECT `+tenderColumns+` FROM tender WHERE id = $1`, tenderId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.watch",
+		EntityType: "tender", EntityId: tenderId}, nil, map[string]bool{"watched": true}); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+func (d *Database) UnwatchTender(tenderId string, params model.UnwatchTenderParams) (*model.Tender, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	userId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM tender_watch WHERE user_id = $1 AND tender_id = $2`, userId, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, errors.New("tender is not watched")
+	}
+
+	tender, err := scanTender(tx.QueryRow(ctx, `SELECT `+tenderColumns+` FROM tender WHERE id = $1`, tenderId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "tender.unwatch",
+		EntityType: "tender", EntityId: tenderId}, map[string]bool{"watched": true}, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+// GetWatchedTenders возвращает отслеживаемые тендеры, которые пользователь по-прежнему может видеть.
+func (d *Database) GetWatchedTenders(params model.GetWatchedTendersParams) ([]*model.Tender, error) {
+	var tenders []*model.Tender
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	userId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+tenderColumns+`
+        FROM tender
+        WHERE id IN (SELECT tender_id FROM tender_watch WHERE user_id = $1)
+          AND (EXISTS (
+                   SELECT 1 FROM organization_responsible r
+                   WHERE r.organization_id = tender.organization_id AND r.user_id = $1
+               )
+               OR (status <> 'Created' AND (visibility = 'Public' OR EXISTS (
+                   SELECT 1
+                   FROM tender_invitation i
+                   JOIN organization_responsible r ON r.organization_id = i.organization_id
+                   WHERE i.tender_id = tender.id AND r.user_id = $1
+               ))))
+        ORDER BY created_at DESC, id
+        LIMIT $2 OFFSET $3`,
+		userId, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tender, err := scanTender(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenders = append(tenders, tender)
+	}
+
+	return tenders, rows.Err()
+}
+
+// CreateSavedSearch сохраняет параметры поиска тендеров. Уже опубликованные подходящие тендеры
+// сразу отмечаются совпавшими, чтобы оповещения приходили только о новых.
+func (d *Database) CreateSavedSearch(params model.CreateSavedSearchParams) (*model.SavedSearch, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	userId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	filter := params.Search.Filter
+	serviceTypes := params.Search.ServiceType
+	if serviceTypes == nil {
+		serviceTypes = []string{}
+	}
+
+	search, err := scanSavedSearch(tx.QueryRow(ctx, `
+        INSERT INTO saved_search (user_id, name, service_types, search_query, organization_id, created_from, created_to,
+                                  budget_min, budget_max, currency)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, '')::uuid, $6, $7, $8, $9, NULLIF($10, ''))
+        RETURNING `+savedSearchColumns,
+		userId, params.Name, serviceTypes, params.Search.Query, filter.OrganizationId, filter.CreatedFrom,
+		filter.CreatedTo, filter.AmountMin, filter.AmountMax, filter.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	args := queryArgs{search.Id}
+	conditions, _ := tenderSearchConditions(&args, savedSearchParams(search))
+	_, err = tx.Exec(ctx, `
+        INSERT INTO saved_search_match (search_id, tender_id)
+        SELECT $1, id FROM tender
+        WHERE status = 'Published' AND visibility = 'Public'`+conditions,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "saved_search.create",
+		EntityType: "saved_search", EntityId: search.Id}, nil, search); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return search, nil
+}
+
+func (d *Database) GetSavedSearches(params model.GetSavedSearchesParams) ([]*model.SavedSearch, error) {
+	var searches []*model.SavedSearch
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	userId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+savedSearchColumns+`
+        FROM saved_search
+        WHERE user_id = $1
+        ORDER BY created_at DESC, id
+        LIMIT $2 OFFSET $3`,
+		userId, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, search)
+	}
+
+	return searches, rows.Err()
+}
+
+func (d *Database) DeleteSavedSearch(searchId string, params model.DeleteSavedSearchParams) (*model.SavedSearch, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	userId, err := employeeId(ctx, tx, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	search, err := scanSavedSearch(tx.QueryRow(ctx, `
+        DELETE FROM saved_search
+        WHERE id = $1 AND user_id = $2
+        RETURNING `+savedSearchColumns,
+		searchId, userId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("saved search not found")
+		}
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "saved_search.delete",
+		EntityType: "saved_search", EntityId: searchId}, search, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return search, nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestWatchAndSavedSearchAlerts(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	watcherId := testEmployee(t, d, "watcher")
+	searcherId := testEmployee(t, d, "searcher")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	watched := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{ServiceType: "Delivery"}, true)
+
+	notifications := func(recipientId, kind string) int {
+		t.Helper()
+		return testCount(t, d, `SELECT count(*) FROM notification WHERE recipient_id = $1 AND kind = $2`, recipientId, kind)
+	}
+
+	if _, err := d.WatchTender(watched.Id, model.WatchTenderParams{Username: "watcher"}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	_, err := d.EditTender(watched.Id, model.EditTenderParams{Username: "creator"},
+		model.EditTenderJSONBody{Description: "New delivery terms"})
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if n := notifications(watcherId, "WatchedTenderChanged"); n != 1 {
+		t.Fatalf("watcher has %d change notifications, want 1", n)
+	}
+
+	// Тендер, подходивший под поиск в момент сохранения, оповещения не порождает.
+	_, err = d.CreateSavedSearch(model.CreateSavedSearchParams{
+		Username: "searcher",
+		Name:     "Delivery",
+		Search:   model.GetTendersParams{ServiceType: []string{"Delivery"}},
+	})
+	if err != nil {
+		t.Fatalf("save search: %v", err)
+	}
+	if _, err = d.EditTender(watched.Id, model.EditTenderParams{Username: "creator"},
+		model.EditTenderJSONBody{Description: "Final delivery terms"}); err != nil {
+		t.Fatalf("edit again: %v", err)
+	}
+	if n := notifications(searcherId, "SavedSearchMatch"); n != 0 {
+		t.Fatalf("search owner has %d matches for a known tender, want 0", n)
+	}
+
+	fresh := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{ServiceType: "Delivery"}, true)
+	testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{ServiceType: "Construction"}, true)
+	if _, err = d.EditTender(fresh.Id, model.EditTenderParams{Username: "creator"},
+		model.EditTenderJSONBody{Description: "Updated"}); err != nil {
+		t.Fatalf("edit fresh tender: %v", err)
+	}
+	if n := notifications(searcherId, "SavedSearchMatch"); n != 1 {
+		t.Fatalf("search owner has %d matches, want exactly 1 for the new delivery tender", n)
+	}
+	if n := testCount(t, d, `SELECT count(*) FROM notification WHERE kind = 'SavedSearchMatch' AND tender_id = $1`, fresh.Id); n != 1 {
+		t.Fatalf("new delivery tender produced %d matches, want 1", n)
+	}
+
+	if _, err = d.UnwatchTender(watched.Id, model.UnwatchTenderParams{Username: "watcher"}); err != nil {
+		t.Fatalf("unwatch: %v", err)
+	}
+	if _, err = d.EditTender(watched.Id, model.EditTenderParams{Username: "creator"},
+		model.EditTenderJSONBody{Description: "After unwatch"}); err != nil {
+		t.Fatalf("edit after unwatch: %v", err)
+	}
+	if n := notifications(watcherId, "WatchedTenderChanged"); n != 2 {
+		t.Fatalf("watcher has %d change notifications, want 2 (none after unwatch)", n)
+	}
+}
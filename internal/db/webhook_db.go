@@ -0,0 +1,502 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// webhookLease - на сколько доставка, взятая воркером, скрывается от других воркеров.
+// Если воркер упал, не записав попытку, доставка снова станет доступной по истечении аренды.
+const webhookLease = "5 minutes"
+
+const webhookDeliveryColumns = `d.id, d.endpoint_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+        d.next_attempt_at, d.last_status_code, d.last_error, d.delivered_at, d.created_at`
+
+func scanWebhookDelivery(row pgx.Row) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	var lastStatusCode *int32
+	var lastError *string
+
+	if err := row.Scan(
+		&delivery.Id,
+		&delivery.WebhookId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&lastStatusCode,
+		&lastError,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if lastStatusCode != nil {
+		delivery.LastStatusCode = *lastStatusCode
+	}
+	if lastError != nil {
+		delivery.LastError = *lastError
+	}
+	if delivery.Status != "Pending" {
+		delivery.NextAttemptAt = nil
+	}
+
+	return &delivery, nil
+}
+
+func (d *Database) CreateWebhook(organizationId string, params model.CreateWebhookParams,
+	body model.CreateWebhookJSONBody, secret string) (*model.Webhook, error) {
+	var webhook model.Webhook
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isOrganizationResponsible(ctx, tx, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	eventTypes := body.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO webhook_endpoint (organization_id, url, secret, event_types, created_by)
+        VALUES ($1, $2, $3, $4, (SELECT id FROM employee WHERE username = $5))
+        RETURNING id, organization_id, url, event_types, secret, created_at`,
+		organizationId, body.URL, secret, eventTypes, params.Username).Scan(
+		&webhook.Id,
+		&webhook.OrganizationId,
+		&webhook.URL,
+		&webhook.EventTypes,
+		&webhook.Secret,
+		&webhook.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "webhook.create",
+		EntityType: "webhook", EntityId: webhook.Id}, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (d *Database) GetWebhooks(organizationId string, params model.GetWebhooksParams) ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isOrganizationResponsible(ctx, conn, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT id, organization_id, url, event_types, created_at
+        FROM webhook_endpoint
+        WHERE organization_id = $1
+        ORDER BY created_at, id`,
+		organizationId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var webhook model.Webhook
+		if err = rows.Scan(
+			&webhook.Id,
+			&webhook.OrganizationId,
+			&webhook.URL,
+			&webhook.EventTypes,
+			&webhook.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook удаляет подписку вместе с ее журналом доставок.
+func (d *Database) DeleteWebhook(organizationId, webhookId string, params model.DeleteWebhookParams) (*model.Webhook, error) {
+	var webhook model.Webhook
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	isResponsible, err := isOrganizationResponsible(ctx, tx, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	before, err := auditSnapshot(ctx, tx, "webhook", webhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
+        DELETE FROM webhook_endpoint
+        WHERE id = $1 AND organization_id = $2
+        RETURNING id, organization_id, url, event_types, created_at`,
+		webhookId, organizationId).Scan(
+		&webhook.Id,
+		&webhook.OrganizationId,
+		&webhook.URL,
+		&webhook.EventTypes,
+		&webhook.CreatedAt,
+	)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("webhook not found")
+		}
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "webhook.delete",
+		EntityType: "webhook", EntityId: webhookId}, before, nil); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// GetWebhookDeliveries возвращает журнал доставок подписки, новые сверху.
+func (d *Database) GetWebhookDeliveries(organizationId, webhookId string,
+	params model.GetWebhookDeliveriesParams) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	isResponsible, err := isOrganizationResponsible(ctx, conn, organizationId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !isResponsible {
+		return nil, errors.New("user is not responsible for this organization")
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT `+webhookDeliveryColumns+`
+        FROM webhook_delivery d
+        JOIN webhook_endpoint e ON e.id = d.endpoint_id
+        WHERE e.id = $1 AND e.organization_id = $2 AND ($3 = '' OR d.status::text = $3)
+        ORDER BY d.created_at DESC, d.id
+        LIMIT $4 OFFSET $5`,
+		webhookId, organizationId, params.Status, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// checkWebhookDeliveryAccess проверяет, что пользователь отвечает за организацию подписки доставки.
+func checkWebhookDeliveryAccess(ctx context.Context, q querier, deliveryId, username string) error {
+	var allowed bool
+	err := q.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1
+            FROM organization_responsible r
+            WHERE r.organization_id = e.organization_id
+              AND r.user_id = (SELECT id FROM employee WHERE username = $2)
+        )
+        FROM webhook_delivery d
+        JOIN webhook_endpoint e ON e.id = d.endpoint_id
+        WHERE d.id = $1`,
+		deliveryId, username).Scan(&allowed)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return errors.New("webhook delivery not found")
+		}
+		return err
+	}
+	if !allowed {
+		return errors.New("webhook delivery not found")
+	}
+	return nil
+}
+
+func (d *Database) GetWebhookDeliveryAttempts(deliveryId string,
+	params model.GetWebhookDeliveryAttemptsParams) ([]*model.WebhookDeliveryAttempt, error) {
+	var attempts []*model.WebhookDeliveryAttempt
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err = checkWebhookDeliveryAccess(ctx, conn, deliveryId, params.Username); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT attempt, status_code, error, duration_ms, attempted_at
+        FROM webhook_delivery_attempt
+        WHERE delivery_id = $1
+        ORDER BY attempt`,
+		deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attempt model.WebhookDeliveryAttempt
+		var statusCode *int32
+		var attemptErr *string
+		if err = rows.Scan(&attempt.Attempt, &statusCode, &attemptErr, &attempt.DurationMs, &attempt.AttemptedAt); err != nil {
+			return nil, err
+		}
+		if statusCode != nil {
+			attempt.StatusCode = *statusCode
+		}
+		if attemptErr != nil {
+			attempt.Error = *attemptErr
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+// ReplayWebhookDelivery ставит доставку в очередь заново с полным запасом попыток.
+// Повторить можно только завершенную доставку: Delivered или Dead.
+func (d *Database) ReplayWebhookDelivery(deliveryId string, params model.ReplayWebhookDeliveryParams) (*model.WebhookDelivery, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = checkWebhookDeliveryAccess(ctx, tx, deliveryId, params.Username); err != nil {
+		return nil, err
+	}
+
+	delivery, err := scanWebhookDelivery(tx.QueryRow(ctx, `
+        UPDATE webhook_delivery d
+        SET status = 'Pending', attempts = 0, next_attempt_at = now(), delivered_at = NULL
+        WHERE d.id = $1 AND d.status <> 'Pending'
+        RETURNING `+webhookDeliveryColumns,
+		deliveryId))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, errors.New("webhook delivery is still pending")
+		}
+		return nil, err
+	}
+
+	if err = d.writeAudit(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "webhook.replay",
+		EntityType: "webhook_delivery", EntityId: deliveryId}, nil, delivery); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// EnqueueWebhookEvent создает доставки события подпискам организации-заказчика тендера и, для событий
+// предложения, организаций, за которые отвечает его автор. Автор - всегда сотрудник, поэтому организации
+// определяются через organization_responsible при любом author_type.
+func (d *Database) EnqueueWebhookEvent(event model.WebhookEvent) error {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return err
+	}
+	defer conn.Release()
+
+	return enqueueWebhookEvent(ctx, conn, event)
+}
+
+func enqueueWebhookEvent(ctx context.Context, q querier, event model.WebhookEvent) error {
+	_, err := q.Exec(ctx, `
+        INSERT INTO webhook_delivery (endpoint_id, event_id, event_type, payload)
+        SELECT e.id, $1, $2, $3
+        FROM webhook_endpoint e
+        WHERE (e.event_types = '{}' OR $2 = ANY(e.event_types))
+          AND e.organization_id IN (
+              SELECT organization_id FROM tender WHERE id = NULLIF($4, '')::uuid
+              UNION
+              SELECT r.organization_id
+              FROM bid b
+              JOIN organization_responsible r ON r.user_id = b.author_id
+              WHERE b.id = NULLIF($5, '')::uuid
+          )
+        ON CONFLICT (endpoint_id, event_id) DO NOTHING`,
+		event.Id, event.Type, event.Payload, event.TenderId, event.BidId)
+	return err
+}
+
+// ClaimWebhookDeliveries берет в работу до limit доставок, срок попытки которых наступил. Строки, уже
+// заблокированные другим воркером, пропускаются, а взятые доставки откладываются на время аренды.
+func (d *Database) ClaimWebhookDeliveries(limit int) ([]*model.PendingWebhookDelivery, error) {
+	var deliveries []*model.PendingWebhookDelivery
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, `
+        WITH due AS (
+            SELECT id
+            FROM webhook_delivery
+            WHERE status = 'Pending' AND next_attempt_at <= now()
+            ORDER BY next_attempt_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE webhook_delivery d
+        SET next_attempt_at = now() + $2::interval
+        FROM due, webhook_endpoint e
+        WHERE d.id = due.id AND e.id = d.endpoint_id
+        RETURNING d.id, d.event_type, e.url, e.secret, d.payload, d.attempts`,
+		limit, webhookLease)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var delivery model.PendingWebhookDelivery
+		if err = rows.Scan(
+			&delivery.Id,
+			&delivery.EventType,
+			&delivery.URL,
+			&delivery.Secret,
+			&delivery.Payload,
+			&delivery.Attempts,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// RecordWebhookAttempt записывает попытку в журнал и переводит доставку в состояние из result.
+// Журнал нумерует попытки сквозь повторные постановки в очередь, а attempts доставки считает
+// попытки с последней постановки.
+func (d *Database) RecordWebhookAttempt(result model.WebhookAttemptResult) error {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO webhook_delivery_attempt (delivery_id, attempt, status_code, error, duration_ms)
+        SELECT $1, COALESCE(max(attempt), 0) + 1, NULLIF($2, 0), NULLIF($3, ''), $4
+        FROM webhook_delivery_attempt
+        WHERE delivery_id = $1`,
+		result.DeliveryId, result.StatusCode, result.Error, result.Duration.Milliseconds())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE webhook_delivery
+        SET status = $2,
+            attempts = $3,
+            next_attempt_at = $4,
+            last_status_code = NULLIF($5, 0),
+            last_error = NULLIF($6, ''),
+            delivered_at = CASE WHEN $2 = 'Delivered' THEN now() END
+        WHERE id = $1`,
+		result.DeliveryId, result.Status, result.Attempt, result.NextAttemptAt, result.StatusCode, result.Error)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
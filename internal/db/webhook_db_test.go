@@ -0,0 +1,105 @@
+package db
+
+import (
+	"github.com/google/uuid"
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+	"time"
+)
+
+func TestWebhookEventReachesBidAuthorOrganizations(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	otherId := testEmployee(t, d, "other")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	supplierOrganizationId := testOrganization(t, d, "Supplier", supplierId)
+	otherOrganizationId := testOrganization(t, d, "Other", otherId)
+
+	endpoints := map[string]string{}
+	for subscriberId, username := range map[string]string{
+		organizationId:         "creator",
+		supplierOrganizationId: "supplier",
+		otherOrganizationId:    "other",
+	} {
+		endpoint, err := d.CreateWebhook(subscriberId, model.CreateWebhookParams{Username: username},
+			model.CreateWebhookJSONBody{URL: "https://example.com/" + username}, "secret")
+		if err != nil {
+			t.Fatalf("create webhook: %v", err)
+		}
+		endpoints[username] = endpoint.Id
+	}
+
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	bid := testBid(t, d, tender.Id, "Organization", supplierId)
+
+	err := d.EnqueueWebhookEvent(model.WebhookEvent{
+		Id:       uuid.NewString(),
+		Type:     "bid.submitted",
+		TenderId: tender.Id,
+		BidId:    bid.Id,
+		Payload:  []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	want := map[string]int{"creator": 1, "supplier": 1, "other": 0}
+	for username, count := range want {
+		got := testCount(t, d, `SELECT count(*) FROM webhook_delivery WHERE endpoint_id = $1`, endpoints[username])
+		if got != count {
+			t.Fatalf("webhook of %s got %d deliveries, want %d", username, got, count)
+		}
+	}
+}
+
+func TestReplayWebhookDeliveryRequeuesDeadDelivery(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	_, err := d.CreateWebhook(organizationId, model.CreateWebhookParams{Username: "creator"},
+		model.CreateWebhookJSONBody{URL: "https://example.com/hook"}, "secret")
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, false)
+
+	err = d.EnqueueWebhookEvent(model.WebhookEvent{Id: uuid.NewString(), Type: "tender.created", TenderId: tender.Id,
+		Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	claimed, err := d.ClaimWebhookDeliveries(10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("claim = %v, %v, want one delivery", claimed, err)
+	}
+	err = d.RecordWebhookAttempt(model.WebhookAttemptResult{
+		DeliveryId:    claimed[0].Id,
+		Attempt:       8,
+		Status:        "Dead",
+		StatusCode:    500,
+		Error:         "webhook receiver responded with 500",
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("record attempt: %v", err)
+	}
+
+	params := model.ReplayWebhookDeliveryParams{Username: "creator"}
+	replayed, err := d.ReplayWebhookDelivery(claimed[0].Id, params)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed.Status != "Pending" || replayed.Attempts != 0 {
+		t.Fatalf("replayed delivery = %+v, want pending with no attempts", replayed)
+	}
+	if _, err = d.ReplayWebhookDelivery(claimed[0].Id, params); err == nil {
+		t.Fatal("pending delivery was replayed again")
+	}
+
+	claimed, err = d.ClaimWebhookDeliveries(10)
+	if err != nil || len(claimed) != 1 || claimed[0].Attempts != 0 || claimed[0].Secret != "secret" {
+		t.Fatalf("claim after replay = %v, %v", claimed, err)
+	}
+}
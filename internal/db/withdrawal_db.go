@@ -0,0 +1,264 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+// lockAuthorBid блокирует предложение автора и сообщает его тендер, статус и то,
+// принято ли по нему (или по одному из его лотов) решение.
+func lockAuthorBid(ctx context.Context, tx pgx.Tx, bidId, authorId string) (string, string, bool, error) {
+	var tenderId, status string
+	var decided bool
+	err := tx.QueryRow(ctx, `
+        SELECT tender_id, status,
+               decision IS NOT NULL OR EXISTS (SELECT 1 FROM bid_lot WHERE bid_id = bid.id AND decision IS NOT NULL)
+        FROM bid
+        WHERE id = $1 AND author_id = $2
+        FOR UPDATE`,
+		bidId, authorId).Scan(&tenderId, &status, &decided)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return "", "", false, errors.New("bid not found")
+		}
+		return "", "", false, err
+	}
+	return tenderId, status, decided, nil
+}
+
+// WithdrawBid отзывает действующее предложение, пока тендер принимает предложения и по нему
+// не принято решение. Отозванное предложение получает статус Canceled и не участвует в оценке.
+func (d *Database) WithdrawBid(bidId string, params model.WithdrawBidParams, body model.WithdrawBidJSONBody) (*model.Bid, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	authorId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tenderId, status, decided, err := lockAuthorBid(ctx, tx, bidId, authorId)
+	if err != nil {
+		return nil, err
+	}
+	before, err := auditSnapshot(ctx, tx, "bid", bidId)
+	if err != nil {
+		return nil, err
+	}
+	if status != "Created" && status != "Published" {
+		return nil, errors.New("bid is not active")
+	}
+	if decided {
+		return nil, errors.New("bid already has a decision")
+	}
+	if _, err = checkSubmissionOpen(ctx, tx, tenderId); err != nil {
+		return nil, err
+	}
+
+	bid, err := scanBid(tx.QueryRow(ctx, `UPDATE bid SET status = 'Canceled' WHERE id = $1 RETURNING `+bidColumns, bidId))
+	if err != nil {
+		return nil, err
+	}
+
+	// Отзыв завершает открытые переговоры по предложению.
+	_, err = tx.Exec(ctx, `
+        UPDATE bid_negotiation_round SET status = 'Declined', responded_at = now()
+        WHERE bid_id = $1 AND status = 'Open'`,
+		bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO bid_withdrawal (bid_id, tender_id, action, reason, performed_by)
+        VALUES ($1, $2, 'Withdrawn', NULLIF($3, ''), $4)`,
+		bidId, tenderId, body.Reason, authorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordBidEvent(ctx, tx, "bid.withdrawn", bidId, map[string]any{"reason": body.Reason}); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.withdraw", EntityType: "bid",
+		EntityId: bidId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = d.unsealBid(bid); err != nil {
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+// ResubmitBid возвращает отозванное предложение в статус Published с новой версией. Действуют те же
+// правила, что и при подаче: тендер принимает предложения, лоты открыты, у автора нет другого
+// действующего предложения.
+func (d *Database) ResubmitBid(bidId string, params model.ResubmitBidParams) (*model.Bid, error) {
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	authorId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tenderId, status, decided, err := lockAuthorBid(ctx, tx, bidId, authorId)
+	if err != nil {
+		return nil, err
+	}
+	before, err := auditSnapshot(ctx, tx, "bid", bidId)
+	if err != nil {
+		return nil, err
+	}
+	if status != "Canceled" {
+		return nil, errors.New("only withdrawn bids can be resubmitted")
+	}
+	if decided {
+		return nil, errors.New("bid already has a decision")
+	}
+	if _, err = checkSubmissionOpen(ctx, tx, tenderId); err != nil {
+		return nil, err
+	}
+
+	bid, err := scanBid(tx.QueryRow(ctx, `SELECT `+bidColumns+` FROM bid WHERE id = $1`, bidId))
+	if err != nil {
+		return nil, err
+	}
+	if err = checkBidInvitation(ctx, tx, tenderId, bid.AuthorId); err != nil {
+		return nil, err
+	}
+	if err = checkBidConflict(ctx, tx, tenderId, bid.AuthorId); err != nil {
+		return nil, err
+	}
+	if err = checkBidLots(ctx, tx, tenderId, bid.LotIds); err != nil {
+		return nil, err
+	}
+
+	bid, err = scanBid(tx.QueryRow(ctx, `
+        UPDATE bid SET status = 'Published', version = version + 1
+        WHERE id = $1
+        RETURNING `+bidColumns,
+		bidId))
+	if err != nil {
+		if isUniqueViolation(err, "bid_one_active_per_author_idx") {
+			return nil, errActiveBidExists
+		}
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO bid_withdrawal (bid_id, tender_id, action, performed_by)
+        VALUES ($1, $2, 'Resubmitted', $3)`,
+		bidId, tenderId, authorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordBidEvent(ctx, tx, "bid.submitted", bidId, nil); err != nil {
+		return nil, err
+	}
+
+	if err = d.auditEntity(ctx, tx, model.AuditRecord{Actor: params.Username, Action: "bid.resubmit", EntityType: "bid",
+		EntityId: bidId}, before); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = d.unsealBid(bid); err != nil {
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+// GetBidWithdrawals возвращает историю отзывов тендера: ответственным организации - всю,
+// авторам предложений - только по своим предложениям.
+func (d *Database) GetBidWithdrawals(tenderId string, params model.GetBidWithdrawalsParams) ([]*model.BidWithdrawal, error) {
+	var withdrawals []*model.BidWithdrawal
+	ctx := context.Background()
+	conn, err := d.Client.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("Unable to acquire a database connection: %v", err)
+		return nil, err
+	}
+	defer conn.Release()
+
+	requesterId, err := employeeId(ctx, conn, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	isResponsible, err := isTenderResponsible(ctx, conn, tenderId, params.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        SELECT w.id, w.bid_id, b.name, b.author_type, b.author_id, w.action, w.reason, w.created_at
+        FROM bid_withdrawal w
+        JOIN bid b ON b.id = w.bid_id
+        WHERE w.tender_id = $1 AND ($2 OR b.author_id = $3)
+        ORDER BY w.created_at DESC, w.id
+        LIMIT $4 OFFSET $5`,
+		tenderId, isResponsible, requesterId, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var withdrawal model.BidWithdrawal
+		var reason *string
+		if err = rows.Scan(
+			&withdrawal.Id,
+			&withdrawal.BidId,
+			&withdrawal.BidName,
+			&withdrawal.AuthorType,
+			&withdrawal.AuthorId,
+			&withdrawal.Action,
+			&reason,
+			&withdrawal.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if reason != nil {
+			withdrawal.Reason = *reason
+		}
+		withdrawals = append(withdrawals, &withdrawal)
+	}
+
+	return withdrawals, rows.Err()
+}
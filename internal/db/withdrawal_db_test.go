@@ -0,0 +1,74 @@
+package db
+
+import (
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestWithdrawAndResubmitKeepOneActiveBidPerAuthor(t *testing.T) {
+	d := testDatabase(t)
+	creatorId := testEmployee(t, d, "creator")
+	supplierId := testEmployee(t, d, "supplier")
+	testEmployee(t, d, "stranger")
+	organizationId := testOrganization(t, d, "Buyer", creatorId)
+	testOrganization(t, d, "Supplier", supplierId)
+	tender := testTender(t, d, organizationId, "creator", model.CreateTenderJSONBody{}, true)
+	first := testBid(t, d, tender.Id, "User", supplierId)
+
+	duplicate := model.CreateBidJSONBody{Name: "Bid", TenderId: tender.Id, AuthorType: "User", AuthorId: supplierId}
+	if _, err := d.CreateBid(duplicate); !errors.Is(err, errActiveBidExists) {
+		t.Fatalf("second active bid: err = %v, want errActiveBidExists", err)
+	}
+
+	if _, err := d.WithdrawBid(first.Id, model.WithdrawBidParams{Username: "stranger"}, model.WithdrawBidJSONBody{}); err == nil {
+		t.Fatal("stranger withdrew the bid")
+	}
+	withdrawn, err := d.WithdrawBid(first.Id, model.WithdrawBidParams{Username: "supplier"},
+		model.WithdrawBidJSONBody{Reason: "Price changed"})
+	if err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+	if withdrawn.Status != "Canceled" {
+		t.Fatalf("withdrawn bid status = %s", withdrawn.Status)
+	}
+
+	// По отозванному предложению решение принять нельзя.
+	_, err = d.SubmitBidDecision(first.Id, model.SubmitBidDecisionParams{Decision: "Accepted", Username: "creator", Rationale: "Best offer"})
+	if err == nil {
+		t.Fatal("decision accepted for a withdrawn bid")
+	}
+
+	second, err := d.CreateBid(duplicate)
+	if err != nil {
+		t.Fatalf("new bid after withdrawal: %v", err)
+	}
+	if _, err = d.ResubmitBid(first.Id, model.ResubmitBidParams{Username: "supplier"}); !errors.Is(err, errActiveBidExists) {
+		t.Fatalf("resubmit next to an active bid: err = %v, want errActiveBidExists", err)
+	}
+
+	if _, err = d.WithdrawBid(second.Id, model.WithdrawBidParams{Username: "supplier"}, model.WithdrawBidJSONBody{}); err != nil {
+		t.Fatalf("withdraw second bid: %v", err)
+	}
+	resubmitted, err := d.ResubmitBid(first.Id, model.ResubmitBidParams{Username: "supplier"})
+	if err != nil {
+		t.Fatalf("resubmit: %v", err)
+	}
+	if resubmitted.Status != "Published" || resubmitted.Version != first.Version+1 {
+		t.Fatalf("resubmitted bid = %+v", resubmitted)
+	}
+	if _, err = d.ResubmitBid(first.Id, model.ResubmitBidParams{Username: "supplier"}); err == nil {
+		t.Fatal("active bid was resubmitted")
+	}
+
+	history, err := d.GetBidWithdrawals(tender.Id, model.GetBidWithdrawalsParams{Username: "supplier", Limit: 10})
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(history) != 3 || history[0].Action != "Resubmitted" {
+		t.Fatalf("history = %+v", history)
+	}
+	if history, err = d.GetBidWithdrawals(tender.Id, model.GetBidWithdrawalsParams{Username: "stranger", Limit: 10}); err != nil || len(history) != 0 {
+		t.Fatalf("stranger history = %+v, %v", history, err)
+	}
+}
@@ -1,6 +1,16 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+// Money описывает денежную сумму в минорных единицах валюты (копейки, центы),
+// чтобы избежать ошибок округления при работе с float.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
 
 type Bid struct {
 	Id          string    `json:"id"`
@@ -12,12 +22,27 @@ type Bid struct {
 	AuthorId    string    `json:"authorId"`
 	Version     int32     `json:"version"`
 	CreatedAt   time.Time `json:"createdAt"`
+	Price       *Money    `json:"price,omitempty"`
+	Decision    string    `json:"decision,omitempty"`
+	LotIds      []string  `json:"lotIds,omitempty"`
+	Sealed      bool      `json:"sealed,omitempty"`
+
+	DecisionRationale string `json:"decisionRationale,omitempty"`
+
+	// AuthorReputation - репутация автора, заполняется в списке предложений тендера по запросу.
+	AuthorReputation *SupplierReputation `json:"authorReputation,omitempty"`
+
+	// SealedPayload - зашифрованные описание и цена, пока тендер запечатан.
+	SealedPayload []byte `json:"-"`
 }
 
 type BidReview struct {
-	CreatedAt   string `json:"createdAt"`
-	Description string `json:"description"`
-	Id          string `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Description string    `json:"description"`
+	Id          string    `json:"id"`
+	BidId       string    `json:"bidId"`
+	TenderId    string    `json:"tenderId"`
+	Rating      int32     `json:"rating,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -25,34 +50,71 @@ type ErrorResponse struct {
 }
 
 type Tender struct {
-	Id             string    `json:"id"`
-	Name           string    `json:"name"`
-	Description    string    `json:"description"`
-	Status         string    `json:"status"`
-	ServiceType    string    `json:"serviceType"`
-	OrganizationId string    `json:"organizationId,omitempty"`
-	Version        int32     `json:"version"`
-	CreatedAt      time.Time `json:"createdAt"`
+	Id                 string     `json:"id"`
+	Name               string     `json:"name"`
+	Description        string     `json:"description"`
+	Status             string     `json:"status"`
+	ServiceType        string     `json:"serviceType"`
+	OrganizationId     string     `json:"organizationId,omitempty"`
+	Version            int32      `json:"version"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	Budget             *Money     `json:"budget,omitempty"`
+	StrictBudget       bool       `json:"strictBudget"`
+	Sealed             bool       `json:"sealed"`
+	SubmissionDeadline *time.Time `json:"submissionDeadline,omitempty"`
+	RevealedAt         *time.Time `json:"revealedAt,omitempty"`
+	Visibility         string     `json:"visibility"`
+	CancelReason       string     `json:"cancelReason,omitempty"`
+	CanceledAt         *time.Time `json:"canceledAt,omitempty"`
+	// BafoStatus - состояние раунда лучших и окончательных предложений: Open или Closed, пусто - раунда не было.
+	BafoStatus   string     `json:"bafoStatus,omitempty"`
+	BafoDeadline *time.Time `json:"bafoDeadline,omitempty"`
+	// Snippet - фрагмент названия и описания с подсветкой совпадений, заполняется только при поиске.
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// SortField - поле сортировки списка, задается как field:asc или field:desc.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListFilter - общие фильтры и сортировка списков тендеров и предложений.
+// Диапазон сумм относится к бюджету тендера или к цене предложения.
+type ListFilter struct {
+	Status         []string
+	OrganizationId string
+	CreatedFrom    *time.Time
+	// CreatedTo - верхняя граница даты создания, не включительно.
+	CreatedTo *time.Time
+	AmountMin *int64
+	AmountMax *int64
+	Currency  string
+	Sort      []SortField
 }
 
 type GetUserBidsParams struct {
-	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
-	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
-	Username string `form:"username,omitempty" json:"username,omitempty"`
+	Limit    int32      `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32      `form:"offset,omitempty" json:"offset,omitempty"`
+	Username string     `form:"username,omitempty" json:"username,omitempty"`
+	Filter   ListFilter `json:"-"`
 }
 
 type CreateBidJSONBody struct {
-	AuthorType  string `json:"authorType"`
-	AuthorId    string `json:"authorId"`
-	Description string `json:"description"`
-	Name        string `json:"name"`
-	Status      string `json:"status"`
-	TenderId    string `json:"tenderId"`
+	AuthorType  string   `json:"authorType"`
+	AuthorId    string   `json:"authorId"`
+	Description string   `json:"description"`
+	Name        string   `json:"name"`
+	Status      string   `json:"status"`
+	TenderId    string   `json:"tenderId"`
+	Price       *Money   `json:"price,omitempty"`
+	LotIds      []string `json:"lotIds,omitempty"`
 }
 
 type EditBidJSONBody struct {
 	Description string `json:"description,omitempty"`
 	Name        string `json:"name,omitempty"`
+	Price       *Money `json:"price,omitempty"`
 }
 
 type EditBidParams struct {
@@ -62,6 +124,8 @@ type EditBidParams struct {
 type SubmitBidFeedbackParams struct {
 	BidFeedback string `form:"bidFeedback" json:"bidFeedback"`
 	Username    string `form:"username" json:"username"`
+	// Rating - необязательная оценка от 1 до 5, учитывается в репутации автора.
+	Rating *int32 `form:"rating,omitempty" json:"rating,omitempty"`
 }
 
 type RollbackBidParams struct {
@@ -78,14 +142,22 @@ type UpdateBidStatusParams struct {
 }
 
 type SubmitBidDecisionParams struct {
-	Decision string `form:"decision" json:"decision"`
-	Username string `form:"username" json:"username"`
+	Decision  string `form:"decision" json:"decision"`
+	Username  string `form:"username" json:"username"`
+	LotId     string `form:"lotId,omitempty" json:"lotId,omitempty"`
+	Rationale string `form:"rationale,omitempty" json:"rationale,omitempty"`
+}
+
+type SubmitBidDecisionJSONBody struct {
+	Rationale string `json:"rationale,omitempty"`
 }
 
 type GetBidsForTenderParams struct {
-	Username string `form:"username" json:"username"`
-	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
-	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
+	Username       string     `form:"username" json:"username"`
+	Limit          int32      `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset         int32      `form:"offset,omitempty" json:"offset,omitempty"`
+	Filter         ListFilter `json:"-"`
+	WithReputation bool       `form:"with_reputation,omitempty" json:"with_reputation,omitempty"`
 }
 
 type GetBidReviewsParams struct {
@@ -96,29 +168,41 @@ type GetBidReviewsParams struct {
 }
 
 type GetTendersParams struct {
-	Limit       int32    `form:"limit,omitempty" json:"limit,omitempty"`
-	Offset      int32    `form:"offset,omitempty" json:"offset,omitempty"`
-	ServiceType []string `form:"service_type,omitempty" json:"service_type,omitempty"`
+	Limit       int32      `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset      int32      `form:"offset,omitempty" json:"offset,omitempty"`
+	ServiceType []string   `form:"service_type,omitempty" json:"service_type,omitempty"`
+	Query       string     `form:"q,omitempty" json:"q,omitempty"`
+	Filter      ListFilter `json:"-"`
 }
 
 type GetUserTendersParams struct {
-	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
-	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
-	Username string `form:"username,omitempty" json:"username,omitempty"`
+	Limit    int32      `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32      `form:"offset,omitempty" json:"offset,omitempty"`
+	Username string     `form:"username,omitempty" json:"username,omitempty"`
+	Filter   ListFilter `json:"-"`
 }
 
 type CreateTenderJSONBody struct {
-	CreatorUsername string `json:"creatorUsername"`
-	Description     string `json:"description"`
-	Name            string `json:"name"`
-	OrganizationId  string `json:"organizationId"`
-	ServiceType     string `json:"serviceType"`
+	CreatorUsername    string     `json:"creatorUsername"`
+	Description        string     `json:"description"`
+	Name               string     `json:"name"`
+	OrganizationId     string     `json:"organizationId"`
+	ServiceType        string     `json:"serviceType"`
+	Budget             *Money     `json:"budget,omitempty"`
+	StrictBudget       bool       `json:"strictBudget,omitempty"`
+	Sealed             bool       `json:"sealed,omitempty"`
+	SubmissionDeadline *time.Time `json:"submissionDeadline,omitempty"`
+	Visibility         string     `json:"visibility,omitempty"`
 }
 
 type EditTenderJSONBody struct {
-	Description string `json:"description,omitempty"`
-	Name        string `json:"name,omitempty"`
-	ServiceType string `json:"serviceType,omitempty"`
+	Description        string     `json:"description,omitempty"`
+	Name               string     `json:"name,omitempty"`
+	ServiceType        string     `json:"serviceType,omitempty"`
+	Budget             *Money     `json:"budget,omitempty"`
+	StrictBudget       *bool      `json:"strictBudget,omitempty"`
+	SubmissionDeadline *time.Time `json:"submissionDeadline,omitempty"`
+	Visibility         string     `json:"visibility,omitempty"`
 }
 
 type EditTenderParams struct {
@@ -129,11 +213,855 @@ type RollbackTenderParams struct {
 	Username string `form:"username" json:"username"`
 }
 
+type RevealTenderBidsParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetTenderParams struct {
+	Username string `form:"username,omitempty" json:"username,omitempty"`
+}
+
 type GetTenderStatusParams struct {
 	Username string `form:"username,omitempty" json:"username,omitempty"`
 }
 
+type CancelTenderJSONBody struct {
+	Reason string `json:"reason"`
+}
+
+type CancelTenderParams struct {
+	Username string `form:"username" json:"username"`
+}
+
 type UpdateTenderStatusParams struct {
 	Status   string `form:"status" json:"status"`
 	Username string `form:"username" json:"username"`
 }
+
+type TenderLot struct {
+	Id           string    `json:"id"`
+	TenderId     string    `json:"tenderId"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description,omitempty"`
+	ServiceType  string    `json:"serviceType"`
+	Budget       *Money    `json:"budget,omitempty"`
+	Status       string    `json:"status"`
+	AwardedBidId string    `json:"awardedBidId,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type CreateTenderLotJSONBody struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ServiceType string `json:"serviceType"`
+	Budget      *Money `json:"budget,omitempty"`
+}
+
+type CreateTenderLotParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetTenderLotsParams struct {
+	Username string `form:"username,omitempty" json:"username,omitempty"`
+}
+
+type CancelTenderLotParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type Auction struct {
+	TenderId               string    `json:"tenderId"`
+	Status                 string    `json:"status"`
+	StartsAt               time.Time `json:"startsAt"`
+	EndsAt                 time.Time `json:"endsAt"`
+	MinDecrement           Money     `json:"minDecrement"`
+	ExtensionWindowSeconds int32     `json:"extensionWindowSeconds"`
+	ExtensionSeconds       int32     `json:"extensionSeconds"`
+	BestPrice              *Money    `json:"bestPrice,omitempty"`
+	OfferCount             int32     `json:"offerCount"`
+}
+
+type AuctionResult struct {
+	Rank        int32     `json:"rank"`
+	BidId       string    `json:"bidId"`
+	BidName     string    `json:"bidName"`
+	AuthorType  string    `json:"authorType"`
+	AuthorId    string    `json:"authorId"`
+	BestPrice   Money     `json:"bestPrice"`
+	OfferCount  int32     `json:"offerCount"`
+	LastOfferAt time.Time `json:"lastOfferAt"`
+}
+
+type ConfigureAuctionJSONBody struct {
+	StartsAt               time.Time `json:"startsAt"`
+	EndsAt                 time.Time `json:"endsAt"`
+	MinDecrement           Money     `json:"minDecrement"`
+	ExtensionWindowSeconds int32     `json:"extensionWindowSeconds"`
+	ExtensionSeconds       int32     `json:"extensionSeconds"`
+}
+
+type ConfigureAuctionParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type PlaceAuctionOfferJSONBody struct {
+	BidId  string `json:"bidId"`
+	Amount int64  `json:"amount"`
+}
+
+type PlaceAuctionOfferParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetAuctionResultsParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type EvaluationCriterion struct {
+	Id        string    `json:"id"`
+	TenderId  string    `json:"tenderId"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	Weight    int32     `json:"weight"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateEvaluationCriterionJSONBody struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Weight int32  `json:"weight"`
+}
+
+type CreateEvaluationCriterionParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetEvaluationCriteriaParams struct {
+	Username string `form:"username,omitempty" json:"username,omitempty"`
+}
+
+type BidScore struct {
+	BidId       string    `json:"bidId"`
+	CriterionId string    `json:"criterionId"`
+	Score       float64   `json:"score"`
+	Comment     string    `json:"comment,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type BidScoreJSONBody struct {
+	CriterionId string  `json:"criterionId"`
+	Score       float64 `json:"score"`
+	Comment     string  `json:"comment,omitempty"`
+}
+
+type SubmitBidScoresParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type CriterionScore struct {
+	CriterionId  string  `json:"criterionId"`
+	Name         string  `json:"name"`
+	Weight       int32   `json:"weight"`
+	AverageScore float64 `json:"averageScore"`
+	Evaluations  int32   `json:"evaluations"`
+}
+
+type BidEvaluation struct {
+	Rank              int32            `json:"rank"`
+	BidId             string           `json:"bidId"`
+	BidName           string           `json:"bidName"`
+	AuthorId          string           `json:"authorId"`
+	Price             *Money           `json:"price,omitempty"`
+	Decision          string           `json:"decision,omitempty"`
+	DecisionRationale string           `json:"decisionRationale,omitempty"`
+	TotalScore        float64          `json:"totalScore"`
+	Complete          bool             `json:"complete"`
+	Criteria          []CriterionScore `json:"criteria"`
+}
+
+type TenderEvaluation struct {
+	TenderId string                 `json:"tenderId"`
+	LockedAt *time.Time             `json:"lockedAt,omitempty"`
+	Criteria []*EvaluationCriterion `json:"criteria"`
+	Bids     []*BidEvaluation       `json:"bids"`
+}
+
+type GetTenderEvaluationParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// TenderQuestion - вопрос по тендеру. Автор вопроса никогда не раскрывается,
+// Own лишь сообщает запрашивающему, что вопрос задал он сам.
+type TenderQuestion struct {
+	Id         string     `json:"id"`
+	TenderId   string     `json:"tenderId"`
+	Question   string     `json:"question"`
+	Answer     string     `json:"answer,omitempty"`
+	Public     bool       `json:"public"`
+	Own        bool       `json:"own,omitempty"`
+	AnsweredAt *time.Time `json:"answeredAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type CreateTenderQuestionJSONBody struct {
+	Question string `json:"question"`
+}
+
+type CreateTenderQuestionParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type AnswerTenderQuestionJSONBody struct {
+	Answer string `json:"answer"`
+	Public bool   `json:"public"`
+}
+
+type AnswerTenderQuestionParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetTenderQuestionsParams struct {
+	Username string `form:"username" json:"username"`
+	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// Attachment - метаданные файла, приложенного к тендеру или предложению. Само содержимое
+// лежит в блоб-хранилище под ключом StorageKey.
+type Attachment struct {
+	Id          string    `json:"id"`
+	ParentType  string    `json:"parentType"`
+	ParentId    string    `json:"parentId"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"sha256"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type UploadAttachmentParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetAttachmentsParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// TenderInvitation - приглашение организации к участию в закрытом (InviteOnly) тендере.
+type TenderInvitation struct {
+	TenderId       string    `json:"tenderId"`
+	OrganizationId string    `json:"organizationId"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type CreateTenderInvitationJSONBody struct {
+	OrganizationId string `json:"organizationId"`
+}
+
+type CreateTenderInvitationParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetTenderInvitationsParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type DeleteTenderInvitationParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetInvitedTendersParams struct {
+	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
+	Username string `form:"username" json:"username"`
+}
+
+type CloneTenderParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// TenderTemplateContent - содержимое шаблона: поля будущего тендера, его лоты и критерии оценки.
+type TenderTemplateContent struct {
+	Name         string                              `json:"name"`
+	Description  string                              `json:"description,omitempty"`
+	ServiceType  string                              `json:"serviceType"`
+	Budget       *Money                              `json:"budget,omitempty"`
+	StrictBudget bool                                `json:"strictBudget,omitempty"`
+	Sealed       bool                                `json:"sealed,omitempty"`
+	Visibility   string                              `json:"visibility,omitempty"`
+	Lots         []CreateTenderLotJSONBody           `json:"lots,omitempty"`
+	Criteria     []CreateEvaluationCriterionJSONBody `json:"criteria,omitempty"`
+}
+
+type TenderTemplate struct {
+	Id             string                `json:"id"`
+	OrganizationId string                `json:"organizationId"`
+	Name           string                `json:"name"`
+	Tender         TenderTemplateContent `json:"tender"`
+	CreatedAt      time.Time             `json:"createdAt"`
+}
+
+type CreateTenderTemplateJSONBody struct {
+	Name string `json:"name"`
+	// SourceTenderId - тендер организации, содержимое которого сохраняется в шаблон вместо Tender.
+	SourceTenderId string                 `json:"sourceTenderId,omitempty"`
+	Tender         *TenderTemplateContent `json:"tender,omitempty"`
+}
+
+type CreateTenderTemplateParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetTenderTemplatesParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// InstantiateTenderTemplateJSONBody - поля, переопределяющие содержимое шаблона в создаваемом тендере.
+type InstantiateTenderTemplateJSONBody struct {
+	Name               string     `json:"name,omitempty"`
+	Description        string     `json:"description,omitempty"`
+	ServiceType        string     `json:"serviceType,omitempty"`
+	Budget             *Money     `json:"budget,omitempty"`
+	StrictBudget       *bool      `json:"strictBudget,omitempty"`
+	Sealed             *bool      `json:"sealed,omitempty"`
+	SubmissionDeadline *time.Time `json:"submissionDeadline,omitempty"`
+	Visibility         string     `json:"visibility,omitempty"`
+}
+
+type InstantiateTenderTemplateParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// BidWithdrawal - запись истории отзыва и повторной подачи предложения.
+type BidWithdrawal struct {
+	Id         string    `json:"id"`
+	BidId      string    `json:"bidId"`
+	BidName    string    `json:"bidName"`
+	AuthorType string    `json:"authorType"`
+	AuthorId   string    `json:"authorId"`
+	Action     string    `json:"action"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type WithdrawBidJSONBody struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type WithdrawBidParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type ResubmitBidParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetBidWithdrawalsParams struct {
+	Username string `form:"username" json:"username"`
+	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// BlockedOrganization - связанная организация, предложения которой организация-заказчик не принимает.
+type BlockedOrganization struct {
+	OrganizationId        string    `json:"organizationId"`
+	BlockedOrganizationId string    `json:"blockedOrganizationId"`
+	Reason                string    `json:"reason,omitempty"`
+	CreatedAt             time.Time `json:"createdAt"`
+}
+
+type BlockOrganizationJSONBody struct {
+	OrganizationId string `json:"organizationId"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+type BlockOrganizationParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetOrganizationBlocklistParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type UnblockOrganizationParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// BidNegotiationRound - раунд переговоров по предложению: встречное предложение заказчика (Buyer)
+// или участника (Bidder) с ценой и условиями.
+type BidNegotiationRound struct {
+	Id          string     `json:"id"`
+	BidId       string     `json:"bidId"`
+	Round       int32      `json:"round"`
+	Party       string     `json:"party"`
+	Price       *Money     `json:"price,omitempty"`
+	Terms       string     `json:"terms,omitempty"`
+	Status      string     `json:"status"`
+	BidVersion  int32      `json:"bidVersion,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+}
+
+type CreateCounterOfferJSONBody struct {
+	Price *Money `json:"price,omitempty"`
+	Terms string `json:"terms,omitempty"`
+}
+
+type CreateCounterOfferParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type RespondCounterOfferParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetBidNegotiationParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// ShortlistedBid - предложение из короткого списка тендера и его финальное предложение (BAFO).
+type ShortlistedBid struct {
+	BidId           string     `json:"bidId"`
+	BidName         string     `json:"bidName"`
+	AuthorType      string     `json:"authorType"`
+	AuthorId        string     `json:"authorId"`
+	ShortlistedAt   time.Time  `json:"shortlistedAt"`
+	BafoSubmittedAt *time.Time `json:"bafoSubmittedAt,omitempty"`
+	BafoVersion     int32      `json:"bafoVersion,omitempty"`
+}
+
+// TenderShortlist - короткий список тендера и состояние раунда BAFO.
+type TenderShortlist struct {
+	TenderId     string            `json:"tenderId"`
+	BafoStatus   string            `json:"bafoStatus,omitempty"`
+	BafoDeadline *time.Time        `json:"bafoDeadline,omitempty"`
+	Bids         []*ShortlistedBid `json:"bids"`
+}
+
+type SetTenderShortlistJSONBody struct {
+	BidIds []string `json:"bidIds"`
+}
+
+type SetTenderShortlistParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetTenderShortlistParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type OpenBafoRoundJSONBody struct {
+	Deadline time.Time `json:"deadline"`
+}
+
+type OpenBafoRoundParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type SubmitBafoJSONBody struct {
+	Description string `json:"description,omitempty"`
+	Price       *Money `json:"price,omitempty"`
+}
+
+type SubmitBafoParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// BidVersion - сохраненная предыдущая версия предложения.
+type BidVersion struct {
+	BidId       string    `json:"bidId"`
+	Version     int32     `json:"version"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Price       *Money    `json:"price,omitempty"`
+	RecordedAt  time.Time `json:"recordedAt"`
+}
+
+type GetBidVersionsParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// TenderAward - запись о присуждении тендера (или лота) предложению: победившая версия, согласованная цена и стороны.
+type TenderAward struct {
+	Id                    string `json:"id"`
+	TenderId              string `json:"tenderId"`
+	TenderName            string `json:"tenderName"`
+	LotId                 string `json:"lotId,omitempty"`
+	LotName               string `json:"lotName,omitempty"`
+	BidId                 string `json:"bidId"`
+	BidName               string `json:"bidName"`
+	BidVersion            int32  `json:"bidVersion"`
+	Price                 *Money `json:"price,omitempty"`
+	BuyerOrganizationId   string `json:"buyerOrganizationId"`
+	BuyerOrganizationName string `json:"buyerOrganizationName"`
+	SupplierType          string `json:"supplierType"`
+	SupplierId            string `json:"supplierId"`
+	SupplierName          string `json:"supplierName"`
+	Rationale             string `json:"rationale,omitempty"`
+	// ShareKind и Share заданы для долевых присуждений: доля в процентах (Percentage) или количество (Quantity).
+	ShareKind string    `json:"shareKind,omitempty"`
+	Share     float64   `json:"share,omitempty"`
+	AwardedAt time.Time `json:"awardedAt"`
+
+	CanceledAt      *time.Time `json:"canceledAt,omitempty"`
+	CancelReason    string     `json:"cancelReason,omitempty"`
+	DeliveredAt     *time.Time `json:"deliveredAt,omitempty"`
+	DeliveredOnTime *bool      `json:"deliveredOnTime,omitempty"`
+}
+
+type GetOrganizationAwardsParams struct {
+	Username string `form:"username" json:"username"`
+	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+type GetTenderAwardParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// AwardShare - доля предложения в присуждении тендера или лота.
+type AwardShare struct {
+	LotId string  `json:"lotId,omitempty"`
+	BidId string  `json:"bidId"`
+	Share float64 `json:"share"`
+}
+
+// TenderAwardSet - набор долевых присуждений тендера: черновик (Draft) до финализации и Finalized после нее.
+type TenderAwardSet struct {
+	TenderId      string        `json:"tenderId"`
+	Kind          string        `json:"kind"`
+	TotalQuantity int64         `json:"totalQuantity,omitempty"`
+	Status        string        `json:"status"`
+	Shares        []*AwardShare `json:"shares"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	FinalizedAt   *time.Time    `json:"finalizedAt,omitempty"`
+}
+
+type SetTenderAwardSetJSONBody struct {
+	Kind          string       `json:"kind"`
+	TotalQuantity int64        `json:"totalQuantity,omitempty"`
+	Shares        []AwardShare `json:"shares"`
+}
+
+type SetTenderAwardSetParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetTenderAwardSetParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type FinalizeTenderAwardSetParams struct {
+	Username  string `form:"username" json:"username"`
+	Rationale string `form:"rationale" json:"rationale"`
+}
+
+type FinalizeTenderAwardSetJSONBody struct {
+	Rationale string `json:"rationale,omitempty"`
+}
+
+// SupplierReputation - накопленная репутация поставщика: пользователя-автора предложений или организации.
+type SupplierReputation struct {
+	SubjectType      string    `json:"subjectType"`
+	SubjectId        string    `json:"subjectId"`
+	ReviewCount      int32     `json:"reviewCount"`
+	AverageScore     float64   `json:"averageScore"`
+	BidsDecided      int32     `json:"bidsDecided"`
+	BidsWon          int32     `json:"bidsWon"`
+	WinRate          float64   `json:"winRate"`
+	Awards           int32     `json:"awards"`
+	AwardsCanceled   int32     `json:"awardsCanceled"`
+	Deliveries       int32     `json:"deliveries"`
+	OnTimeDeliveries int32     `json:"onTimeDeliveries"`
+	OnTimeRate       float64   `json:"onTimeRate"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+type GetOrganizationReputationParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type CancelTenderAwardJSONBody struct {
+	Reason string `json:"reason"`
+}
+
+type CancelTenderAwardParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type RecordAwardDeliveryJSONBody struct {
+	OnTime *bool `json:"onTime"`
+}
+
+type RecordAwardDeliveryParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type WatchTenderParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type UnwatchTenderParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetWatchedTendersParams struct {
+	Username string `form:"username" json:"username"`
+	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// SavedSearch - сохраненный поиск тендеров с параметрами GET /api/tenders. О каждом новом
+// опубликованном тендере, подходящем под поиск, владельцу приходит уведомление.
+type SavedSearch struct {
+	Id             string     `json:"id"`
+	Name           string     `json:"name"`
+	ServiceType    []string   `json:"serviceType,omitempty"`
+	Query          string     `json:"q,omitempty"`
+	OrganizationId string     `json:"organizationId,omitempty"`
+	CreatedFrom    *time.Time `json:"createdFrom,omitempty"`
+	CreatedTo      *time.Time `json:"createdTo,omitempty"`
+	BudgetMin      *int64     `json:"budgetMin,omitempty"`
+	BudgetMax      *int64     `json:"budgetMax,omitempty"`
+	Currency       string     `json:"currency,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type CreateSavedSearchParams struct {
+	Username string           `form:"username" json:"username"`
+	Name     string           `form:"name" json:"name"`
+	Search   GetTendersParams `json:"-"`
+}
+
+type GetSavedSearchesParams struct {
+	Username string `form:"username" json:"username"`
+	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+type DeleteSavedSearchParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// Notification - уведомление во входящих пользователя. Payload зависит от вида уведомления.
+type Notification struct {
+	Id        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	TenderId  string          `json:"tenderId,omitempty"`
+	BidId     string          `json:"bidId,omitempty"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"createdAt"`
+	ReadAt    *time.Time      `json:"readAt,omitempty"`
+}
+
+type NotificationInbox struct {
+	UnreadCount   int32           `json:"unreadCount"`
+	Notifications []*Notification `json:"notifications"`
+}
+
+type GetNotificationsParams struct {
+	Username   string `form:"username" json:"username"`
+	UnreadOnly bool   `form:"unread,omitempty" json:"unread,omitempty"`
+	Limit      int32  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset     int32  `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// MarkNotificationsReadJSONBody отмечает прочитанными уведомления Ids или, если All, все уведомления пользователя.
+type MarkNotificationsReadJSONBody struct {
+	Ids []string `json:"ids,omitempty"`
+	All bool     `json:"all,omitempty"`
+}
+
+type MarkNotificationsReadParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type NotificationPreference struct {
+	Kind    string `json:"kind"`
+	Enabled bool   `json:"enabled"`
+}
+
+type GetNotificationPreferencesParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type SetNotificationPreferencesParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type SetNotificationPreferencesJSONBody struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+// NotificationEvent - событие, о котором сервис уведомляет получателей Audience:
+// TenderResponsibles - ответственных организации-заказчика, BidAuthors - автора предложения BidId,
+// TenderBidders - авторов действующих предложений тендера.
+type NotificationEvent struct {
+	Kind     string
+	Audience string
+	TenderId string
+	BidId    string
+	Payload  any
+}
+
+// Webhook - адрес организации, на который доставляются события тендеров и предложений.
+// Пустой EventTypes означает все события. Secret возвращается только при создании.
+type Webhook struct {
+	Id             string    `json:"id"`
+	OrganizationId string    `json:"organizationId"`
+	URL            string    `json:"url"`
+	EventTypes     []string  `json:"eventTypes"`
+	Secret         string    `json:"secret,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type CreateWebhookJSONBody struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes,omitempty"`
+}
+
+type CreateWebhookParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type GetWebhooksParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type DeleteWebhookParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type WebhookDelivery struct {
+	Id             string          `json:"id"`
+	WebhookId      string          `json:"webhookId"`
+	EventId        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"`
+	LastStatusCode int32           `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+type WebhookDeliveryAttempt struct {
+	Attempt     int32     `json:"attempt"`
+	StatusCode  int32     `json:"statusCode,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int64     `json:"durationMs"`
+	AttemptedAt time.Time `json:"attemptedAt"`
+}
+
+type GetWebhookDeliveriesParams struct {
+	Username string `form:"username" json:"username"`
+	Status   string `form:"status,omitempty" json:"status,omitempty"`
+	Limit    int32  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset   int32  `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+type GetWebhookDeliveryAttemptsParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+type ReplayWebhookDeliveryParams struct {
+	Username string `form:"username" json:"username"`
+}
+
+// WebhookEvent - событие для доставки подпискам организации тендера и организаций автора предложения BidId.
+type WebhookEvent struct {
+	Id       string
+	Type     string
+	TenderId string
+	BidId    string
+	Payload  []byte
+}
+
+// PendingWebhookDelivery - доставка, взятая в работу воркером.
+type PendingWebhookDelivery struct {
+	Id        string
+	EventType string
+	URL       string
+	Secret    string
+	Payload   []byte
+	Attempts  int32
+}
+
+// WebhookAttemptResult - итог попытки доставки с номером Attempt с последней постановки в очередь.
+// Status - Delivered, Pending (тогда NextAttemptAt - время повторной попытки) или Dead.
+type WebhookAttemptResult struct {
+	DeliveryId    string
+	Attempt       int32
+	Status        string
+	StatusCode    int
+	Error         string
+	Duration      time.Duration
+	NextAttemptAt time.Time
+}
+
+// OutboxEvent - доменное событие, записанное в outbox в одной транзакции с изменением.
+// PublishedSinks - приемники, уже получившие событие: при повторной передаче они пропускаются.
+// Sequence - номер публикации, который растет в порядке фиксации публикаций, у неопубликованного события 0.
+type OutboxEvent struct {
+	Id             int64           `json:"-"`
+	Sequence       int64           `json:"-"`
+	EventId        string          `json:"id"`
+	Type           string          `json:"type"`
+	TenderId       string          `json:"tenderId"`
+	BidId          string          `json:"bidId,omitempty"`
+	Payload        json.RawMessage `json:"data"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	Attempts       int32           `json:"-"`
+	PublishedSinks []string        `json:"-"`
+}
+
+// GetTenderEventsParams - параметры чтения событий тендера. LastEventId - номер публикации (Sequence)
+// последнего полученного события.
+type GetTenderEventsParams struct {
+	Username    string `form:"username" json:"username"`
+	LastEventId int64  `form:"lastEventId,omitempty" json:"lastEventId,omitempty"`
+	Limit       int32  `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// AuditChange - значение поля сущности до и после изменения. Для созданной сущности Before пустой,
+// для удаленной - After.
+type AuditChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+type AuditEntry struct {
+	Id         int64                  `json:"id"`
+	RequestId  string                 `json:"requestId,omitempty"`
+	Actor      string                 `json:"actor"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entityType"`
+	EntityId   string                 `json:"entityId"`
+	Changes    map[string]AuditChange `json:"changes"`
+	CreatedAt  time.Time              `json:"createdAt"`
+}
+
+// AuditRecord - запись аудита об изменении. Автор задается именем Actor или, если имя неизвестно, ActorId.
+// Идентификатор запроса и разница состояний добавляются при записи.
+type AuditRecord struct {
+	Actor      string
+	ActorId    string
+	Action     string
+	EntityType string
+	EntityId   string
+}
+
+type GetAuditLogParams struct {
+	Username       string     `form:"username" json:"username"`
+	OrganizationId string     `form:"organizationId" json:"organizationId"`
+	EntityType     string     `form:"entityType,omitempty" json:"entityType,omitempty"`
+	EntityId       string     `form:"entityId,omitempty" json:"entityId,omitempty"`
+	Actor          string     `form:"actor,omitempty" json:"actor,omitempty"`
+	From           *time.Time `form:"from,omitempty" json:"from,omitempty"`
+	To             *time.Time `form:"to,omitempty" json:"to,omitempty"`
+	Limit          int32      `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset         int32      `form:"offset,omitempty" json:"offset,omitempty"`
+}
@@ -0,0 +1,80 @@
+package outbox
+
+import (
+	"context"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+	"sync"
+)
+
+// MaxAttempts - число неудачных передач, после которого событие outbox считается невыполнимым.
+const MaxAttempts = 20
+
+// Sink получает события outbox. Реле передает событие каждому приемнику, который еще не получил его,
+// поэтому после сбоя событие может прийти в приемник повторно: различать его нужно по EventId.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event *model.OutboxEvent) error
+}
+
+// LogSink пишет события в журнал приложения.
+type LogSink struct{}
+
+func (LogSink) Name() string {
+	return "log"
+}
+
+func (LogSink) Publish(_ context.Context, event *model.OutboxEvent) error {
+	log.Printf("outbox event %s: %s tender=%s bid=%s %s", event.EventId, event.Type, event.TenderId, event.BidId,
+		event.Payload)
+	return nil
+}
+
+// Bus - шина событий внутри процесса. Подписчик, который не успевает читать, теряет события,
+// но не задерживает реле и других подписчиков.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan *model.OutboxEvent]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan *model.OutboxEvent]struct{})}
+}
+
+func (b *Bus) Name() string {
+	return "bus"
+}
+
+// Subscribe возвращает канал событий с буфером buffer и функцию отписки, закрывающую канал.
+func (b *Bus) Subscribe(buffer int) (<-chan *model.OutboxEvent, func()) {
+	events := make(chan *model.OutboxEvent, buffer)
+
+	b.mu.Lock()
+	b.subscribers[events] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, events)
+			b.mu.Unlock()
+			close(events)
+		})
+	}
+}
+
+func (b *Bus) Publish(_ context.Context, event *model.OutboxEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+			log.Printf("outbox bus subscriber is full, event %s dropped", event.EventId)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package pdf формирует простые текстовые PDF-документы без внешних зависимостей.
+// Используются стандартные шрифты Helvetica, поэтому кириллица транслитерируется.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 56
+
+	titleSize   = 16
+	textSize    = 11
+	leading     = 16
+	maxLineRune = 90
+)
+
+// Document - текстовый документ из заголовка и строк, разбитый на страницы A4.
+type Document struct {
+	title string
+	lines []string
+}
+
+// New создает документ с заголовком title.
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// Line добавляет строку текста, длинные строки переносятся по словам.
+func (d *Document) Line(text string) {
+	d.lines = append(d.lines, wrap(text, maxLineRune)...)
+}
+
+// Field добавляет строку вида "label: value".
+func (d *Document) Field(label, value string) {
+	d.Line(label + ": " + value)
+}
+
+// Blank добавляет пустую строку.
+func (d *Document) Blank() {
+	d.lines = append(d.lines, "")
+}
+
+// Bytes возвращает содержимое PDF-файла.
+func (d *Document) Bytes() []byte {
+	pages := d.paginate()
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Объекты 1-4 - каталог, дерево страниц и шрифты, дальше по паре (страница, содержимое) на страницу.
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+
+		content := d.content(page, i == 0)
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// paginate раскладывает строки по страницам, на первой странице место занимает заголовок.
+func (d *Document) paginate() [][]string {
+	perPage := (pageHeight - 2*margin) / leading
+	first := perPage - 2
+
+	var pages [][]string
+	lines := d.lines
+	limit := first
+	for {
+		if len(lines) <= limit {
+			pages = append(pages, lines)
+			return pages
+		}
+		pages = append(pages, lines[:limit])
+		lines = lines[limit:]
+		limit = perPage
+	}
+}
+
+func (d *Document) content(lines []string, withTitle bool) string {
+	var b strings.Builder
+	y := pageHeight - margin
+
+	b.WriteString("BT\n")
+	if withTitle {
+		fmt.Fprintf(&b, "/F2 %d Tf\n%d %d Td\n(%s) Tj\n", titleSize, margin, y, escape(d.title))
+		fmt.Fprintf(&b, "/F1 %d Tf\n%d TL\n0 %d Td\n", textSize, leading, -2*leading)
+	} else {
+		fmt.Fprintf(&b, "/F1 %d Tf\n%d TL\n%d %d Td\n", textSize, leading, margin, y)
+	}
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("T*\n")
+		}
+		fmt.Fprintf(&b, "(%s) Tj\n", escape(line))
+	}
+	b.WriteString("ET")
+
+	return b.String()
+}
+
+// escape переводит строку в кодировку WinAnsi и экранирует ее для строкового литерала PDF.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range Transliterate(text) {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// wrap разбивает текст на строки не длиннее width символов по границам слов.
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range words {
+		for len([]rune(word)) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, string([]rune(word)[:width]))
+			word = string([]rune(word)[width:])
+		}
+		if line == "" {
+			line = word
+		} else if len([]rune(line))+1+len([]rune(word)) <= width {
+			line += " " + word
+		} else {
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,30 @@
+package pdf
+
+import "strings"
+
+// cyrillic - транслитерация русских букв латиницей (ГОСТ Р 52535.1-2006).
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "tc",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "iu",
+	'я': "ia",
+}
+
+// Transliterate заменяет кириллицу латиницей, остальные символы не меняются.
+func Transliterate(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		lower := []rune(strings.ToLower(string(r)))[0]
+		latin, ok := cyrillic[lower]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		if lower != r && latin != "" {
+			latin = strings.ToUpper(latin[:1]) + latin[1:]
+		}
+		b.WriteString(latin)
+	}
+	return b.String()
+}
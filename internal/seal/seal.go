@@ -0,0 +1,72 @@
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Sealer шифрует содержимое запечатанных предложений серверным ключом (AES-256-GCM).
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// NewSealer создает Sealer из 32-байтового ключа.
+func NewSealer(key []byte) (*Sealer, error) {
+	if len(key) != 32 {
+		return nil, errors.New("seal key must be 32 bytes long")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sealer{aead: aead}, nil
+}
+
+// NewSealerFromEnv создает Sealer из ключа в base64 из переменной окружения BID_SEAL_KEY.
+// Если переменная не задана, возвращает nil без ошибки - запечатанные тендеры тогда недоступны.
+func NewSealerFromEnv() (*Sealer, error) {
+	encoded := os.Getenv("BID_SEAL_KEY")
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode BID_SEAL_KEY: %w", err)
+	}
+
+	return NewSealer(key)
+}
+
+// Seal шифрует данные, случайный nonce записывается в начало результата.
+func (s *Sealer) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return s.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open расшифровывает данные, полученные от Seal.
+func (s *Sealer) Open(ciphertext []byte) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("sealed payload is too short")
+	}
+
+	return s.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+}
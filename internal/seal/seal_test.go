@@ -0,0 +1,79 @@
+package seal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testSealer(t *testing.T, fill byte) *Sealer {
+	t.Helper()
+	sealer, err := NewSealer(bytes.Repeat([]byte{fill}, 32))
+	if err != nil {
+		t.Fatalf("new sealer: %v", err)
+	}
+	return sealer
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	sealer := testSealer(t, 1)
+	plaintext := []byte(`{"description":"Delivery in 3 days","price":{"amount":150000,"currency":"RUB"}}`)
+
+	ciphertext, err := sealer.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("Delivery")) {
+		t.Fatal("sealed payload contains plaintext")
+	}
+
+	opened, err := sealer.Open(ciphertext)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Fatalf("opened %q, want %q", opened, plaintext)
+	}
+
+	// Случайный nonce: одно и то же содержимое шифруется по-разному.
+	again, err := sealer.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if bytes.Equal(again, ciphertext) {
+		t.Fatal("sealing the same plaintext twice produced identical payloads")
+	}
+}
+
+func TestOpenRejectsTamperedOrForeignPayload(t *testing.T) {
+	sealer := testSealer(t, 1)
+	ciphertext, err := sealer.Seal([]byte("secret bid"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		sealer  *Sealer
+		payload []byte
+	}{
+		{name: "tampered ciphertext", sealer: sealer, payload: tampered},
+		{name: "wrong key", sealer: testSealer(t, 2), payload: ciphertext},
+		{name: "truncated payload", sealer: sealer, payload: ciphertext[:4]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.sealer.Open(tt.payload); err == nil {
+				t.Fatal("payload opened without an error")
+			}
+		})
+	}
+}
+
+func TestNewSealerRequires32ByteKey(t *testing.T) {
+	if _, err := NewSealer(make([]byte, 16)); err == nil {
+		t.Fatal("16-byte key accepted")
+	}
+}
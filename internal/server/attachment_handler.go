@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// maxUploadRequestSize ограничивает тело multipart-запроса. Точный предел размера файла
+// проверяет сервис, здесь лишь отсекаются заведомо слишком большие запросы.
+const maxUploadRequestSize = 100 << 20
+
+func (h *Handler) UploadTenderAttachment(w http.ResponseWriter, r *http.Request) {
+	tenderId := mux.Vars(r)["tenderId"]
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	h.uploadAttachment(w, r, "Tender", tenderId)
+}
+
+func (h *Handler) UploadBidAttachment(w http.ResponseWriter, r *http.Request) {
+	bidId := mux.Vars(r)["bidId"]
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	h.uploadAttachment(w, r, "Bid", bidId)
+}
+
+// uploadAttachment принимает файл из поля file multipart-формы.
+func (h *Handler) uploadAttachment(w http.ResponseWriter, r *http.Request, parentType, parentId string) {
+	var params model.UploadAttachmentParams
+	params.Username = r.URL.Query().Get("username")
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadRequestSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			jsonRespond(w, http.StatusRequestEntityTooLarge, "file is too large")
+			return
+		}
+		jsonRespond(w, http.StatusBadRequest, "multipart form with a file field is required")
+		return
+	}
+	defer file.Close()
+	defer r.MultipartForm.RemoveAll()
+
+	attachment, err := h.service(r).UploadAttachment(parentType, parentId, params,
+		header.Filename, header.Header.Get("Content-Type"), header.Size, file)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot upload an attachment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(attachment); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an attachment")
+		return
+	}
+}
+
+func (h *Handler) GetTenderAttachments(w http.ResponseWriter, r *http.Request) {
+	tenderId := mux.Vars(r)["tenderId"]
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	h.getAttachments(w, r, "Tender", tenderId)
+}
+
+func (h *Handler) GetBidAttachments(w http.ResponseWriter, r *http.Request) {
+	bidId := mux.Vars(r)["bidId"]
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	h.getAttachments(w, r, "Bid", bidId)
+}
+
+func (h *Handler) getAttachments(w http.ResponseWriter, r *http.Request, parentType, parentId string) {
+	var params model.GetAttachmentsParams
+	params.Username = r.URL.Query().Get("username")
+
+	attachments, err := h.service(r).GetAttachments(parentType, parentId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get attachments from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(attachments); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of attachments")
+		return
+	}
+}
+
+func (h *Handler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	var params model.GetAttachmentsParams
+	attachmentId := mux.Vars(r)["attachmentId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(attachmentId) {
+		jsonRespond(w, http.StatusBadRequest, "attachment id is invalid")
+		return
+	}
+
+	attachment, content, err := h.service(r).DownloadAttachment(attachmentId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get an attachment")
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Checksum-Sha256", attachment.Checksum)
+	w.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(w, content); err != nil {
+		log.Printf("cannot stream attachment %s: %v", attachmentId, err)
+	}
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+)
+
+func (h *Handler) ConfigureAuction(w http.ResponseWriter, r *http.Request) {
+	var body model.ConfigureAuctionJSONBody
+	var params model.ConfigureAuctionParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding an auction body")
+		return
+	}
+
+	auction, err := h.service(r).ConfigureAuction(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "auction can not be configured")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(auction); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an auction")
+		return
+	}
+}
+
+func (h *Handler) GetAuction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	auction, err := h.service(r).GetAuction(tenderId)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "auction not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(auction); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an auction")
+		return
+	}
+}
+
+func (h *Handler) PlaceAuctionOffer(w http.ResponseWriter, r *http.Request) {
+	var body model.PlaceAuctionOfferJSONBody
+	var params model.PlaceAuctionOfferParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding an offer body")
+		return
+	}
+	if !IsValidUUID(body.BidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	auction, err := h.service(r).PlaceAuctionOffer(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "offer can not be placed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(auction); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an auction")
+		return
+	}
+}
+
+func (h *Handler) GetAuctionResults(w http.ResponseWriter, r *http.Request) {
+	var params model.GetAuctionResultsParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	results, err := h.service(r).GetAuctionResults(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get auction results from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(results); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode auction results")
+		return
+	}
+}
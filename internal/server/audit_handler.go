@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (h *Handler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	var params model.GetAuditLogParams
+	queryParams := r.URL.Query()
+	params.OrganizationId = queryParams.Get("organizationId")
+
+	if !IsValidUUID(params.OrganizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	for name, bound := range map[string]**time.Time{"from": &params.From, "to": &params.To} {
+		if value := queryParams.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				jsonRespond(w, http.StatusBadRequest, name+" must be an RFC 3339 timestamp")
+				return
+			}
+			*bound = &parsed
+		}
+	}
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+	params.EntityType = queryParams.Get("entityType")
+	params.EntityId = queryParams.Get("entityId")
+	params.Actor = queryParams.Get("actor")
+
+	entries, err := h.service(r).GetAuditLog(params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get audit log from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(entries); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode audit log")
+		return
+	}
+}
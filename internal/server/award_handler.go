@@ -0,0 +1,104 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) GetOrganizationAwards(w http.ResponseWriter, r *http.Request) {
+	var params model.GetOrganizationAwardsParams
+	vars := mux.Vars(r)
+	organizationId := vars["organizationId"]
+	queryParams := r.URL.Query()
+
+	if !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+
+	awards, err := h.service(r).GetOrganizationAwards(organizationId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get awards from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(awards); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of awards")
+		return
+	}
+}
+
+func (h *Handler) GetTenderAward(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderAwardParams
+	vars := mux.Vars(r)
+	awardId := vars["awardId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(awardId) {
+		jsonRespond(w, http.StatusBadRequest, "award id is invalid")
+		return
+	}
+
+	award, err := h.service(r).GetTenderAward(awardId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get an award from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(award); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an award")
+		return
+	}
+}
+
+func (h *Handler) DownloadTenderAwardPDF(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderAwardParams
+	vars := mux.Vars(r)
+	awardId := vars["awardId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(awardId) {
+		jsonRespond(w, http.StatusBadRequest, "award id is invalid")
+		return
+	}
+
+	award, content, err := h.service(r).TenderAwardPDF(awardId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot generate an award summary")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"award-%s.pdf\"", award.Id))
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(content); err != nil {
+		log.Printf("cannot write award summary %s: %v", awardId, err)
+	}
+}
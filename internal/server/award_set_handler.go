@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+)
+
+func (h *Handler) SetTenderAwardSet(w http.ResponseWriter, r *http.Request) {
+	var body model.SetTenderAwardSetJSONBody
+	var params model.SetTenderAwardSetParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding an award set body")
+		return
+	}
+
+	for _, share := range body.Shares {
+		if !IsValidUUID(share.BidId) || (share.LotId != "" && !IsValidUUID(share.LotId)) {
+			jsonRespond(w, http.StatusBadRequest, "bid or lot id is invalid")
+			return
+		}
+	}
+
+	awardSet, err := h.service(r).SetTenderAwardSet(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot save an award set")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(awardSet); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an award set")
+		return
+	}
+}
+
+func (h *Handler) GetTenderAwardSet(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderAwardSetParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	awardSet, err := h.service(r).GetTenderAwardSet(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get an award set from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(awardSet); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an award set")
+		return
+	}
+}
+
+func (h *Handler) FinalizeTenderAwardSet(w http.ResponseWriter, r *http.Request) {
+	var params model.FinalizeTenderAwardSetParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	queryParams := r.URL.Query()
+	params.Username = queryParams.Get("username")
+	params.Rationale = queryParams.Get("rationale")
+
+	var body model.FinalizeTenderAwardSetJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err == nil && body.Rationale != "" {
+		params.Rationale = body.Rationale
+	}
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	awardSet, err := h.service(r).FinalizeTenderAwardSet(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "award set can not be finalized")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(awardSet); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an award set")
+		return
+	}
+}
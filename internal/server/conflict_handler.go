@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+)
+
+func (h *Handler) BlockOrganization(w http.ResponseWriter, r *http.Request) {
+	var body model.BlockOrganizationJSONBody
+	var params model.BlockOrganizationParams
+	vars := mux.Vars(r)
+	organizationId := vars["organizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a blocklist body")
+		return
+	}
+
+	if !IsValidUUID(body.OrganizationId) {
+		jsonRespond(w, http.StatusBadRequest, "blocked organization id is invalid")
+		return
+	}
+
+	blockedOrganization, err := h.service(r).BlockOrganization(organizationId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "organization can not be blocked")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(blockedOrganization); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a blocked organization")
+		return
+	}
+}
+
+func (h *Handler) GetOrganizationBlocklist(w http.ResponseWriter, r *http.Request) {
+	var params model.GetOrganizationBlocklistParams
+	vars := mux.Vars(r)
+	organizationId := vars["organizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	blocklist, err := h.service(r).GetOrganizationBlocklist(organizationId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get an organization blocklist from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(blocklist); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an organization blocklist")
+		return
+	}
+}
+
+func (h *Handler) UnblockOrganization(w http.ResponseWriter, r *http.Request) {
+	var params model.UnblockOrganizationParams
+	vars := mux.Vars(r)
+	organizationId, blockedOrganizationId := vars["organizationId"], vars["blockedOrganizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) || !IsValidUUID(blockedOrganizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	blockedOrganization, err := h.service(r).UnblockOrganization(organizationId, blockedOrganizationId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "organization can not be unblocked")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(blockedOrganization); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an unblocked organization")
+		return
+	}
+}
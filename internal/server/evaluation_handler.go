@@ -0,0 +1,125 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+)
+
+func (h *Handler) CreateEvaluationCriterion(w http.ResponseWriter, r *http.Request) {
+	var body model.CreateEvaluationCriterionJSONBody
+	var params model.CreateEvaluationCriterionParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a criterion body")
+		return
+	}
+
+	criterion, err := h.service(r).CreateEvaluationCriterion(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot create an evaluation criterion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(criterion); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an evaluation criterion")
+		return
+	}
+}
+
+func (h *Handler) GetEvaluationCriteria(w http.ResponseWriter, r *http.Request) {
+	var params model.GetEvaluationCriteriaParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	criteria, err := h.service(r).GetEvaluationCriteria(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get evaluation criteria from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(criteria); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of evaluation criteria")
+		return
+	}
+}
+
+func (h *Handler) SubmitBidScores(w http.ResponseWriter, r *http.Request) {
+	var body []model.BidScoreJSONBody
+	var params model.SubmitBidScoresParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a scores body")
+		return
+	}
+	for _, item := range body {
+		if !IsValidUUID(item.CriterionId) {
+			jsonRespond(w, http.StatusBadRequest, "criterion id is invalid")
+			return
+		}
+	}
+
+	scores, err := h.service(r).SubmitBidScores(bidId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "bid scores can not be submitted")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(scores); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode bid scores")
+		return
+	}
+}
+
+func (h *Handler) GetTenderEvaluation(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderEvaluationParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	evaluation, err := h.service(r).GetTenderEvaluation(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get tender evaluation from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(evaluation); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a tender evaluation")
+		return
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// eventsHeartbeat - период комментариев-пингов, которые держат поток открытым за прокси. На каждом пинге
+// поток заодно дочитывает события, сигнал о которых потерялся.
+const eventsHeartbeat = 15 * time.Second
+
+// writeTenderEvents пишет события в поток и сдвигает params.LastEventId, дочитывая события тендера
+// из сервиса, пока они возвращаются полными пачками.
+func (h *Handler) writeTenderEvents(w http.ResponseWriter, flusher http.Flusher, tenderId string,
+	params *model.GetTenderEventsParams, events []*model.OutboxEvent) error {
+	for {
+		for _, event := range events {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if _, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data); err != nil {
+				return err
+			}
+			params.LastEventId = event.Sequence
+		}
+		flusher.Flush()
+
+		if len(events) < int(params.Limit) {
+			return nil
+		}
+
+		var err error
+		if events, err = h.Service.GetTenderEvents(tenderId, *params); err != nil {
+			return err
+		}
+	}
+}
+
+// tenderEventsErrorStatus возвращает код ответа на ошибку первого чтения событий, пока поток еще не открыт.
+func tenderEventsErrorStatus(err error) int {
+	switch err.Error() {
+	case "username is required", "last event id must not be negative":
+		return http.StatusBadRequest
+	case "user not found", "tender not found":
+		return http.StatusNotFound
+	case "user is not responsible for this organization":
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) StreamTenderEvents(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderEventsParams
+	queryParams := r.URL.Query()
+	tenderId := queryParams.Get("tenderId")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	// Браузер при переподключении присылает Last-Event-ID сам, lastEventId нужен остальным клиентам.
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = queryParams.Get("lastEventId")
+	}
+	if lastEventId != "" {
+		id, err := strconv.ParseInt(lastEventId, 10, 64)
+		if err != nil || id < 0 {
+			jsonRespond(w, http.StatusBadRequest, "last event id is invalid")
+			return
+		}
+		params.LastEventId = id
+	}
+	params.Username = queryParams.Get("username")
+	params.Limit = 100
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		jsonRespond(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	// Подписка оформляется до чтения пропущенных событий, чтобы не потерять опубликованные между ними.
+	signals, unsubscribe := h.Service.SubscribeEvents(64)
+	defer unsubscribe()
+
+	events, err := h.Service.GetTenderEvents(tenderId, params)
+	if err != nil {
+		if status := tenderEventsErrorStatus(err); status != http.StatusInternalServerError {
+			jsonRespond(w, status, err.Error())
+			return
+		}
+		jsonRespond(w, http.StatusInternalServerError, "cannot get tender events from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err = h.writeTenderEvents(w, flusher, tenderId, &params, events); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case signal, ok := <-signals:
+			if !ok {
+				return
+			}
+			if signal.TenderId != tenderId {
+				continue
+			}
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+
+		// Права проверяются заново на каждом чтении: пользователь, потерявший доступ, отключается.
+		if events, err = h.Service.GetTenderEvents(tenderId, params); err != nil {
+			return
+		}
+		if err = h.writeTenderEvents(w, flusher, tenderId, &params, events); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeEventService отдает события тендера с номерами публикации больше запрошенного. Остальные методы
+// TenderService в этих тестах не вызываются.
+type fakeEventService struct {
+	TenderService
+	events  []*model.OutboxEvent
+	signals []*model.OutboxEvent
+	cursors []int64
+	err     error
+}
+
+func (f *fakeEventService) GetTenderEvents(tenderId string, params model.GetTenderEventsParams) ([]*model.OutboxEvent, error) {
+	f.cursors = append(f.cursors, params.LastEventId)
+	if f.err != nil {
+		return nil, f.err
+	}
+	var events []*model.OutboxEvent
+	for _, event := range f.events {
+		if event.TenderId == tenderId && event.Sequence > params.LastEventId {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// SubscribeEvents отдает заранее заданные сигналы и закрывает канал, после чего поток завершается.
+func (f *fakeEventService) SubscribeEvents(buffer int) (<-chan *model.OutboxEvent, func()) {
+	signals := make(chan *model.OutboxEvent, len(f.signals))
+	for _, signal := range f.signals {
+		signals <- signal
+	}
+	close(signals)
+	return signals, func() {}
+}
+
+const testTenderId = "5b8e7f8e-2b57-4a3c-9a4e-0f6c1d2e3a4b"
+
+func TestStreamTenderEventsResumesFromPublishSequence(t *testing.T) {
+	// id в outbox назначается при записи, поэтому событие, опубликованное позже, может иметь меньший id.
+	service := &fakeEventService{
+		events: []*model.OutboxEvent{
+			{Id: 3, Sequence: 5, EventId: "published", Type: "tender.published", TenderId: testTenderId},
+			{Id: 40, Sequence: 7, EventId: "submitted", Type: "bid.submitted", TenderId: testTenderId},
+			{Id: 12, Sequence: 9, EventId: "closed", Type: "tender.status_changed", TenderId: testTenderId},
+		},
+		signals: []*model.OutboxEvent{{TenderId: testTenderId}},
+	}
+	h := &Handler{Service: service}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events?tenderId="+testTenderId+"&username=creator", nil)
+	req.Header.Set("Last-Event-ID", "5")
+	rec := httptest.NewRecorder()
+	h.StreamTenderEvents(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("response %d with content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	submitted := strings.Index(body, "id: 7\nevent: bid.submitted\n")
+	closed := strings.Index(body, "id: 9\nevent: tender.status_changed\n")
+	if submitted < 0 || closed < submitted || strings.Contains(body, "tender.published") {
+		t.Fatalf("stream after Last-Event-ID 5:\n%s", body)
+	}
+	if !slices.Equal(service.cursors, []int64{5, 9}) {
+		t.Fatalf("cursors = %v, want the resume point and then the last sent sequence", service.cursors)
+	}
+}
+
+func TestStreamTenderEventsRejectsInvalidLastEventId(t *testing.T) {
+	h := &Handler{Service: &fakeEventService{}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events?tenderId="+testTenderId+"&username=creator&lastEventId=-1", nil)
+	rec := httptest.NewRecorder()
+	h.StreamTenderEvents(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("response %d, want 400", rec.Code)
+	}
+}
+
+func TestStreamTenderEventsMapsErrorsBeforeStreaming(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{errors.New("tender not found"), http.StatusNotFound},
+		{errors.New("user not found"), http.StatusNotFound},
+		{errors.New("user is not responsible for this organization"), http.StatusForbidden},
+		{errors.New("username is required"), http.StatusBadRequest},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		h := &Handler{Service: &fakeEventService{err: tt.err}}
+		req := httptest.NewRequest(http.MethodGet, "/api/events?tenderId="+testTenderId+"&username=creator", nil)
+		rec := httptest.NewRecorder()
+		h.StreamTenderEvents(rec, req)
+
+		if rec.Code != tt.code || rec.Header().Get("Content-Type") == "text/event-stream" {
+			t.Fatalf("%q: response %d with content type %q, want %d", tt.err, rec.Code, rec.Header().Get("Content-Type"), tt.code)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Допустимые поля сортировки списков, sort=field:asc|desc.
+var (
+	tenderSortFields = []string{"name", "created_at", "budget", "status", "version"}
+	bidSortFields    = []string{"name", "created_at", "price", "status", "version"}
+)
+
+// parseListFilter разбирает общие параметры фильтрации и сортировки списков:
+// status (можно повторять или перечислять через запятую), organization_id,
+// created_from и created_to (RFC 3339 или YYYY-MM-DD, created_to не включительно,
+// дата без времени включает весь день), budget_min, budget_max и currency,
+// sort=field:asc|desc через запятую.
+func parseListFilter(query url.Values, sortFields []string) (model.ListFilter, error) {
+	var filter model.ListFilter
+
+	for _, value := range query["status"] {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				filter.Status = append(filter.Status, status)
+			}
+		}
+	}
+
+	if organizationId := query.Get("organization_id"); organizationId != "" {
+		if !IsValidUUID(organizationId) {
+			return filter, errors.New("organization_id is invalid")
+		}
+		filter.OrganizationId = organizationId
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseFilterTime(query.Get("created_from"), false); err != nil {
+		return filter, fmt.Errorf("created_from: %w", err)
+	}
+	if filter.CreatedTo, err = parseFilterTime(query.Get("created_to"), true); err != nil {
+		return filter, fmt.Errorf("created_to: %w", err)
+	}
+
+	if filter.AmountMin, err = parseFilterAmount(query.Get("budget_min")); err != nil {
+		return filter, fmt.Errorf("budget_min: %w", err)
+	}
+	if filter.AmountMax, err = parseFilterAmount(query.Get("budget_max")); err != nil {
+		return filter, fmt.Errorf("budget_max: %w", err)
+	}
+	filter.Currency = query.Get("currency")
+
+	for _, value := range query["sort"] {
+		for _, term := range strings.Split(value, ",") {
+			field, direction, _ := strings.Cut(strings.TrimSpace(term), ":")
+			if !slices.Contains(sortFields, field) {
+				return filter, fmt.Errorf("sort field must be one of %s", strings.Join(sortFields, ", "))
+			}
+			if direction != "" && direction != "asc" && direction != "desc" {
+				return filter, errors.New("sort direction must be asc or desc")
+			}
+			filter.Sort = append(filter.Sort, model.SortField{Field: field, Desc: direction == "desc"})
+		}
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime разбирает дату или время. Для верхней границы дата без времени
+// сдвигается на начало следующего дня.
+func parseFilterTime(value string, upper bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errors.New("must be RFC 3339 time or YYYY-MM-DD date")
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func parseFilterAmount(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || amount < 0 {
+		return nil, errors.New("must be a non-negative integer amount in minor units")
+	}
+	return &amount, nil
+}
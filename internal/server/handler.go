@@ -2,18 +2,29 @@ package server
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
 )
 
+// requestIdHeader - заголовок с идентификатором запроса. Идентификатор клиента сохраняется,
+// если он задан, иначе выдается новый; в обоих случаях он возвращается в ответе.
+const requestIdHeader = "X-Request-ID"
+
+type requestIdKey struct{}
+
 type Handler struct {
 	Router  *mux.Router
 	Service TenderService
 	Server  *http.Server
+	// ForRequest возвращает сервис, записи аудита которого помечаются идентификатором запроса.
+	// Если он не задан, используется Service.
+	ForRequest func(requestId string) TenderService
 }
 
 // NewHandler создает новый экземпляр Handler.
@@ -21,16 +32,42 @@ func NewHandler(service TenderService) *Handler {
 	h := &Handler{Service: service}
 
 	h.Router = mux.NewRouter()
+	h.Router.Use(withRequestId)
 	h.mapRoutes()
 
+	// Контекст запросов отменяется при остановке сервера, чтобы завершились открытые потоки событий.
+	baseCtx, cancel := context.WithCancel(context.Background())
 	h.Server = &http.Server{
-		Addr:    "0.0.0.0:8080",
-		Handler: h.Router,
+		Addr:        "0.0.0.0:8080",
+		Handler:     h.Router,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
 	}
+	h.Server.RegisterOnShutdown(cancel)
 
 	return h
 }
 
+// withRequestId назначает запросу идентификатор и передает его в контексте запроса.
+func withRequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := r.Header.Get(requestIdHeader)
+		if requestId == "" || len(requestId) > 100 {
+			requestId = uuid.NewString()
+		}
+		w.Header().Set(requestIdHeader, requestId)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIdKey{}, requestId)))
+	})
+}
+
+// service возвращает сервис для обработки запроса r.
+func (h *Handler) service(r *http.Request) TenderService {
+	requestId, _ := r.Context().Value(requestIdKey{}).(string)
+	if h.ForRequest == nil || requestId == "" {
+		return h.Service
+	}
+	return h.ForRequest(requestId)
+}
+
 // mapRoutes задает маршруты для API.
 func (h *Handler) mapRoutes() {
 	h.Router.HandleFunc("/api/ping", func(w http.ResponseWriter, r *http.Request) {
@@ -40,15 +77,90 @@ func (h *Handler) mapRoutes() {
 	h.Router.HandleFunc("/api/tenders/new", h.CreateTender).Methods("POST")
 	h.Router.HandleFunc("/api/tenders", h.GetTenders).Methods("GET")
 	h.Router.HandleFunc("/api/tenders/my", h.GetUserTenders).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/invited", h.GetInvitedTenders).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/watched", h.GetWatchedTenders).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}", h.GetTender).Methods("GET")
 	h.Router.HandleFunc("/api/tenders/{tenderId}/status", h.GetTenderStatus).Methods("GET")
 	h.Router.HandleFunc("/api/tenders/{tenderId}/status", h.UpdateTenderStatus).Methods("PUT")
 	h.Router.HandleFunc("/api/tenders/{tenderId}/edit", h.EditTender).Methods("PATCH")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/cancel", h.CancelTender).Methods("PUT")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/reveal", h.RevealTenderBids).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/watch", h.WatchTender).Methods("PUT")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/watch", h.UnwatchTender).Methods("DELETE")
+	h.Router.HandleFunc("/api/searches", h.CreateSavedSearch).Methods("POST")
+	h.Router.HandleFunc("/api/searches", h.GetSavedSearches).Methods("GET")
+	h.Router.HandleFunc("/api/searches/{searchId}", h.DeleteSavedSearch).Methods("DELETE")
+	h.Router.HandleFunc("/api/events", h.StreamTenderEvents).Methods("GET")
+	h.Router.HandleFunc("/api/notifications", h.GetNotifications).Methods("GET")
+	h.Router.HandleFunc("/api/notifications/read", h.MarkNotificationsRead).Methods("PUT")
+	h.Router.HandleFunc("/api/notifications/preferences", h.GetNotificationPreferences).Methods("GET")
+	h.Router.HandleFunc("/api/notifications/preferences", h.SetNotificationPreferences).Methods("PUT")
 	h.Router.HandleFunc("/api/bids/new", h.CreateBid).Methods("POST")
 	h.Router.HandleFunc("/api/bids/my", h.GetUserBids).Methods("GET")
 	h.Router.HandleFunc("/api/bids/{tenderId}/list", h.GetBidsForTender).Methods("GET")
 	h.Router.HandleFunc("/api/bids/{bidId}/status", h.GetBidStatus).Methods("GET")
 	h.Router.HandleFunc("/api/bids/{bidId}/status", h.UpdateBidStatus).Methods("PUT")
 	h.Router.HandleFunc("/api/bids/{bidId}/edit", h.EditBid).Methods("PATCH")
+	h.Router.HandleFunc("/api/bids/{bidId}/submit_decision", h.SubmitBidDecision).Methods("PUT")
+	h.Router.HandleFunc("/api/bids/{bidId}/scores", h.SubmitBidScores).Methods("PUT")
+	h.Router.HandleFunc("/api/bids/{bidId}/feedback", h.SubmitBidFeedback).Methods("PUT")
+	h.Router.HandleFunc("/api/bids/{tenderId}/reviews", h.GetBidReviews).Methods("GET")
+	h.Router.HandleFunc("/api/bids/{bidId}/withdraw", h.WithdrawBid).Methods("PUT")
+	h.Router.HandleFunc("/api/bids/{bidId}/resubmit", h.ResubmitBid).Methods("PUT")
+	h.Router.HandleFunc("/api/bids/{bidId}/negotiation", h.CreateCounterOffer).Methods("POST")
+	h.Router.HandleFunc("/api/bids/{bidId}/negotiation", h.GetBidNegotiation).Methods("GET")
+	h.Router.HandleFunc("/api/bids/{bidId}/negotiation/accept", h.AcceptCounterOffer).Methods("PUT")
+	h.Router.HandleFunc("/api/bids/{bidId}/negotiation/decline", h.DeclineCounterOffer).Methods("PUT")
+	h.Router.HandleFunc("/api/bids/{bidId}/bafo", h.SubmitBafo).Methods("PUT")
+	h.Router.HandleFunc("/api/bids/{bidId}/versions", h.GetBidVersions).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/shortlist", h.SetTenderShortlist).Methods("PUT")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/shortlist", h.GetTenderShortlist).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/bafo", h.OpenBafoRound).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/award-set", h.SetTenderAwardSet).Methods("PUT")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/award-set", h.GetTenderAwardSet).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/award-set/finalize", h.FinalizeTenderAwardSet).Methods("PUT")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/withdrawals", h.GetBidWithdrawals).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/auction", h.ConfigureAuction).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/auction", h.GetAuction).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/auction/offers", h.PlaceAuctionOffer).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/auction/results", h.GetAuctionResults).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/criteria", h.CreateEvaluationCriterion).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/criteria", h.GetEvaluationCriteria).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/evaluation", h.GetTenderEvaluation).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/questions", h.CreateTenderQuestion).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/questions", h.GetTenderQuestions).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/questions/{questionId}/answer", h.AnswerTenderQuestion).Methods("PUT")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/attachments", h.UploadTenderAttachment).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/attachments", h.GetTenderAttachments).Methods("GET")
+	h.Router.HandleFunc("/api/bids/{bidId}/attachments", h.UploadBidAttachment).Methods("POST")
+	h.Router.HandleFunc("/api/bids/{bidId}/attachments", h.GetBidAttachments).Methods("GET")
+	h.Router.HandleFunc("/api/attachments/{attachmentId}", h.DownloadAttachment).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/clone", h.CloneTender).Methods("POST")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/templates", h.CreateTenderTemplate).Methods("POST")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/templates", h.GetTenderTemplates).Methods("GET")
+	h.Router.HandleFunc("/api/templates/{templateId}/instantiate", h.InstantiateTenderTemplate).Methods("POST")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/awards", h.GetOrganizationAwards).Methods("GET")
+	h.Router.HandleFunc("/api/awards/{awardId}", h.GetTenderAward).Methods("GET")
+	h.Router.HandleFunc("/api/awards/{awardId}/pdf", h.DownloadTenderAwardPDF).Methods("GET")
+	h.Router.HandleFunc("/api/awards/{awardId}/cancel", h.CancelTenderAward).Methods("PUT")
+	h.Router.HandleFunc("/api/awards/{awardId}/delivery", h.RecordAwardDelivery).Methods("PUT")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/reputation", h.GetOrganizationReputation).Methods("GET")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/blocklist", h.BlockOrganization).Methods("POST")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/blocklist", h.GetOrganizationBlocklist).Methods("GET")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/blocklist/{blockedOrganizationId}", h.UnblockOrganization).Methods("DELETE")
+	h.Router.HandleFunc("/api/audit", h.GetAuditLog).Methods("GET")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/webhooks", h.CreateWebhook).Methods("POST")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/webhooks", h.GetWebhooks).Methods("GET")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/webhooks/{webhookId}", h.DeleteWebhook).Methods("DELETE")
+	h.Router.HandleFunc("/api/organizations/{organizationId}/webhooks/{webhookId}/deliveries", h.GetWebhookDeliveries).Methods("GET")
+	h.Router.HandleFunc("/api/webhook-deliveries/{deliveryId}/attempts", h.GetWebhookDeliveryAttempts).Methods("GET")
+	h.Router.HandleFunc("/api/webhook-deliveries/{deliveryId}/replay", h.ReplayWebhookDelivery).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/invitations", h.CreateTenderInvitation).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/invitations", h.GetTenderInvitations).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/invitations/{organizationId}", h.DeleteTenderInvitation).Methods("DELETE")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/lots", h.CreateTenderLot).Methods("POST")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/lots", h.GetTenderLots).Methods("GET")
+	h.Router.HandleFunc("/api/tenders/{tenderId}/lots/{lotId}/cancel", h.CancelTenderLot).Methods("PUT")
 }
 
 // Serve запускает HTTP-сервер и обрабатывает остановку сервера.
@@ -59,7 +171,7 @@ func (h *Handler) Serve() error {
 		}
 	}()
 
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	<-c
 
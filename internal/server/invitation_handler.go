@@ -0,0 +1,129 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) CreateTenderInvitation(w http.ResponseWriter, r *http.Request) {
+	var body model.CreateTenderInvitationJSONBody
+	var params model.CreateTenderInvitationParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding an invitation body")
+		return
+	}
+
+	if !IsValidUUID(body.OrganizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	invitation, err := h.service(r).CreateTenderInvitation(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot create an invitation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(invitation); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an invitation")
+		return
+	}
+}
+
+func (h *Handler) GetTenderInvitations(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderInvitationsParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	invitations, err := h.service(r).GetTenderInvitations(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get invitations from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(invitations); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of invitations")
+		return
+	}
+}
+
+func (h *Handler) DeleteTenderInvitation(w http.ResponseWriter, r *http.Request) {
+	var params model.DeleteTenderInvitationParams
+	vars := mux.Vars(r)
+	tenderId, organizationId := vars["tenderId"], vars["organizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) || !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "tender or organization id is invalid")
+		return
+	}
+
+	invitation, err := h.service(r).DeleteTenderInvitation(tenderId, organizationId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "invitation can not be revoked")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(invitation); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a revoked invitation")
+		return
+	}
+}
+
+func (h *Handler) GetInvitedTenders(w http.ResponseWriter, r *http.Request) {
+	var params model.GetInvitedTendersParams
+	queryParams := r.URL.Query()
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+
+	tenders, err := h.service(r).GetInvitedTenders(params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get invited tenders from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(tenders); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of invited tenders")
+		return
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+)
+
+func (h *Handler) CreateTenderLot(w http.ResponseWriter, r *http.Request) {
+	var body model.CreateTenderLotJSONBody
+	var params model.CreateTenderLotParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a lot body")
+		return
+	}
+
+	lot, err := h.service(r).CreateTenderLot(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot create a lot")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(lot); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a lot")
+		return
+	}
+}
+
+func (h *Handler) GetTenderLots(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderLotsParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	lots, err := h.service(r).GetTenderLots(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get lots for tender from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(lots); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of lots")
+		return
+	}
+}
+
+func (h *Handler) CancelTenderLot(w http.ResponseWriter, r *http.Request) {
+	var params model.CancelTenderLotParams
+	vars := mux.Vars(r)
+	tenderId, lotId := vars["tenderId"], vars["lotId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) || !IsValidUUID(lotId) {
+		jsonRespond(w, http.StatusBadRequest, "tender or lot id is invalid")
+		return
+	}
+
+	lot, err := h.service(r).CancelTenderLot(tenderId, lotId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "lot can not be canceled")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(lot); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a canceled lot")
+		return
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+)
+
+func (h *Handler) CreateCounterOffer(w http.ResponseWriter, r *http.Request) {
+	var body model.CreateCounterOfferJSONBody
+	var params model.CreateCounterOfferParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a counter-offer body")
+		return
+	}
+
+	round, err := h.service(r).CreateCounterOffer(bidId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot create a counter-offer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(round); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a counter-offer")
+		return
+	}
+}
+
+func (h *Handler) AcceptCounterOffer(w http.ResponseWriter, r *http.Request) {
+	h.respondCounterOffer(w, r, h.service(r).AcceptCounterOffer)
+}
+
+func (h *Handler) DeclineCounterOffer(w http.ResponseWriter, r *http.Request) {
+	h.respondCounterOffer(w, r, h.service(r).DeclineCounterOffer)
+}
+
+func (h *Handler) respondCounterOffer(w http.ResponseWriter, r *http.Request,
+	respond func(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error)) {
+	var params model.RespondCounterOfferParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	round, err := respond(bidId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot respond to a counter-offer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(round); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a counter-offer")
+		return
+	}
+}
+
+func (h *Handler) GetBidNegotiation(w http.ResponseWriter, r *http.Request) {
+	var params model.GetBidNegotiationParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	rounds, err := h.service(r).GetBidNegotiation(bidId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get a bid negotiation from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(rounds); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a bid negotiation")
+		return
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	var params model.GetNotificationsParams
+	queryParams := r.URL.Query()
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+	params.UnreadOnly = queryParams.Get("unread") == "true"
+
+	inbox, err := h.service(r).GetNotifications(params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get notifications from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(inbox); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode notifications")
+		return
+	}
+}
+
+func (h *Handler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	var body model.MarkNotificationsReadJSONBody
+	var params model.MarkNotificationsReadParams
+	params.Username = r.URL.Query().Get("username")
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a mark-as-read body")
+		return
+	}
+	for _, id := range body.Ids {
+		if !IsValidUUID(id) {
+			jsonRespond(w, http.StatusBadRequest, "notification id is invalid")
+			return
+		}
+	}
+
+	notifications, err := h.service(r).MarkNotificationsRead(params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "notifications can not be marked as read")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(notifications); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode read notifications")
+		return
+	}
+}
+
+func (h *Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var params model.GetNotificationPreferencesParams
+	params.Username = r.URL.Query().Get("username")
+
+	preferences, err := h.service(r).GetNotificationPreferences(params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get notification preferences from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(preferences); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode notification preferences")
+		return
+	}
+}
+
+func (h *Handler) SetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var body model.SetNotificationPreferencesJSONBody
+	var params model.SetNotificationPreferencesParams
+	params.Username = r.URL.Query().Get("username")
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding notification preferences")
+		return
+	}
+
+	preferences, err := h.service(r).SetNotificationPreferences(params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "notification preferences can not be saved")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(preferences); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode notification preferences")
+		return
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) CreateTenderQuestion(w http.ResponseWriter, r *http.Request) {
+	var body model.CreateTenderQuestionJSONBody
+	var params model.CreateTenderQuestionParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a question body")
+		return
+	}
+
+	question, err := h.service(r).CreateTenderQuestion(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot create a question")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(question); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a question")
+		return
+	}
+}
+
+func (h *Handler) AnswerTenderQuestion(w http.ResponseWriter, r *http.Request) {
+	var body model.AnswerTenderQuestionJSONBody
+	var params model.AnswerTenderQuestionParams
+	vars := mux.Vars(r)
+	tenderId, questionId := vars["tenderId"], vars["questionId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) || !IsValidUUID(questionId) {
+		jsonRespond(w, http.StatusBadRequest, "tender or question id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding an answer body")
+		return
+	}
+
+	question, err := h.service(r).AnswerTenderQuestion(tenderId, questionId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "question can not be answered")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(question); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an answered question")
+		return
+	}
+}
+
+func (h *Handler) GetTenderQuestions(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderQuestionsParams
+	queryParams := r.URL.Query()
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+
+	questions, err := h.service(r).GetTenderQuestions(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get questions for tender from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(questions); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of questions")
+		return
+	}
+}
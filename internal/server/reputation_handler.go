@@ -0,0 +1,180 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) SubmitBidFeedback(w http.ResponseWriter, r *http.Request) {
+	var params model.SubmitBidFeedbackParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	queryParams := r.URL.Query()
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	params.BidFeedback = queryParams.Get("bidFeedback")
+	params.Username = queryParams.Get("username")
+	if queryParams.Has("rating") {
+		rating, err := strconv.Atoi(queryParams.Get("rating"))
+		if err != nil || rating < 1 || rating > 5 {
+			jsonRespond(w, http.StatusBadRequest, "rating must be between 1 and 5")
+			return
+		}
+		value := int32(rating)
+		params.Rating = &value
+	}
+
+	bid, err := h.service(r).SubmitBidFeedback(bidId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "feedback can not be submitted")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(bid); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a bid")
+		return
+	}
+}
+
+func (h *Handler) GetBidReviews(w http.ResponseWriter, r *http.Request) {
+	var params model.GetBidReviewsParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	queryParams := r.URL.Query()
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.AuthorUsername = queryParams.Get("authorUsername")
+	params.RequesterUsername = queryParams.Get("requesterUsername")
+
+	reviews, err := h.service(r).GetBidReviews(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get bid reviews from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(reviews); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of bid reviews")
+		return
+	}
+}
+
+func (h *Handler) GetOrganizationReputation(w http.ResponseWriter, r *http.Request) {
+	var params model.GetOrganizationReputationParams
+	vars := mux.Vars(r)
+	organizationId := vars["organizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	reputation, err := h.service(r).GetOrganizationReputation(organizationId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get organization reputation from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(reputation); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode organization reputation")
+		return
+	}
+}
+
+func (h *Handler) CancelTenderAward(w http.ResponseWriter, r *http.Request) {
+	var body model.CancelTenderAwardJSONBody
+	var params model.CancelTenderAwardParams
+	vars := mux.Vars(r)
+	awardId := vars["awardId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(awardId) {
+		jsonRespond(w, http.StatusBadRequest, "award id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding an award cancellation body")
+		return
+	}
+
+	award, err := h.service(r).CancelTenderAward(awardId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "award can not be canceled")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(award); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a canceled award")
+		return
+	}
+}
+
+func (h *Handler) RecordAwardDelivery(w http.ResponseWriter, r *http.Request) {
+	var body model.RecordAwardDeliveryJSONBody
+	var params model.RecordAwardDeliveryParams
+	vars := mux.Vars(r)
+	awardId := vars["awardId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(awardId) {
+		jsonRespond(w, http.StatusBadRequest, "award id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding an award delivery body")
+		return
+	}
+	if body.OnTime == nil {
+		jsonRespond(w, http.StatusBadRequest, "onTime is required")
+		return
+	}
+
+	award, err := h.service(r).RecordAwardDelivery(awardId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "award delivery can not be recorded")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(award); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an award")
+		return
+	}
+}
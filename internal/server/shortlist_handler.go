@@ -0,0 +1,158 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+)
+
+func (h *Handler) SetTenderShortlist(w http.ResponseWriter, r *http.Request) {
+	var body model.SetTenderShortlistJSONBody
+	var params model.SetTenderShortlistParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a shortlist body")
+		return
+	}
+
+	for _, bidId := range body.BidIds {
+		if !IsValidUUID(bidId) {
+			jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+			return
+		}
+	}
+
+	shortlist, err := h.service(r).SetTenderShortlist(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot update a shortlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(shortlist); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a shortlist")
+		return
+	}
+}
+
+func (h *Handler) GetTenderShortlist(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderShortlistParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	shortlist, err := h.service(r).GetTenderShortlist(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get a shortlist from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(shortlist); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a shortlist")
+		return
+	}
+}
+
+func (h *Handler) OpenBafoRound(w http.ResponseWriter, r *http.Request) {
+	var body model.OpenBafoRoundJSONBody
+	var params model.OpenBafoRoundParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a best and final offer round body")
+		return
+	}
+
+	tender, err := h.service(r).OpenBafoRound(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "best and final offer round can not be opened")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(tender); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a tender")
+		return
+	}
+}
+
+func (h *Handler) SubmitBafo(w http.ResponseWriter, r *http.Request) {
+	var body model.SubmitBafoJSONBody
+	var params model.SubmitBafoParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a best and final offer body")
+		return
+	}
+
+	bid, err := h.service(r).SubmitBafo(bidId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "best and final offer can not be submitted")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(bid); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a bid")
+		return
+	}
+}
+
+func (h *Handler) GetBidVersions(w http.ResponseWriter, r *http.Request) {
+	var params model.GetBidVersionsParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	versions, err := h.service(r).GetBidVersions(bidId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get bid versions from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(versions); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of bid versions")
+		return
+	}
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"io"
+	"net/http"
+)
+
+func (h *Handler) CloneTender(w http.ResponseWriter, r *http.Request) {
+	var params model.CloneTenderParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	tender, err := h.service(r).CloneTender(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot clone a tender")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(tender); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a cloned tender")
+		return
+	}
+}
+
+func (h *Handler) CreateTenderTemplate(w http.ResponseWriter, r *http.Request) {
+	var body model.CreateTenderTemplateJSONBody
+	var params model.CreateTenderTemplateParams
+	vars := mux.Vars(r)
+	organizationId := vars["organizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a template body")
+		return
+	}
+
+	if body.SourceTenderId != "" && !IsValidUUID(body.SourceTenderId) {
+		jsonRespond(w, http.StatusBadRequest, "source tender id is invalid")
+		return
+	}
+
+	template, err := h.service(r).CreateTenderTemplate(organizationId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot create a template")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(template); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a template")
+		return
+	}
+}
+
+func (h *Handler) GetTenderTemplates(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderTemplatesParams
+	vars := mux.Vars(r)
+	organizationId := vars["organizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	templates, err := h.service(r).GetTenderTemplates(organizationId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get templates from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(templates); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of templates")
+		return
+	}
+}
+
+// InstantiateTenderTemplate создает тендер по шаблону. Тело запроса с переопределениями необязательно.
+func (h *Handler) InstantiateTenderTemplate(w http.ResponseWriter, r *http.Request) {
+	var body model.InstantiateTenderTemplateJSONBody
+	var params model.InstantiateTenderTemplateParams
+	vars := mux.Vars(r)
+	templateId := vars["templateId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(templateId) {
+		jsonRespond(w, http.StatusBadRequest, "template id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding template overrides")
+		return
+	}
+
+	tender, err := h.service(r).InstantiateTenderTemplate(templateId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot create a tender from template")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(tender); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a tender")
+		return
+	}
+}
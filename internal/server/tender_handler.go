@@ -5,29 +5,121 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/instinctG/tender/internal/model"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type TenderService interface {
 	GetUserBids(params model.GetUserBidsParams) ([]*model.Bid, error)
 	CreateBid(params model.CreateBidJSONBody) (*model.Bid, error)
 	EditBid(bidId string, params model.EditBidParams, body model.EditBidJSONBody) (*model.Bid, error)
-	SubmitBidFeedback(bidId string, params model.SubmitBidFeedbackParams) *model.Bid
+	SubmitBidFeedback(bidId string, params model.SubmitBidFeedbackParams) (*model.Bid, error)
 	RollbackBid(bidId string, version int32, params model.RollbackBidParams) *model.Bid
 	GetBidStatus(bidId string, params model.GetBidStatusParams) (string, error)
 	UpdateBidStatus(bidId string, params model.UpdateBidStatusParams) (*model.Bid, error)
 	SubmitBidDecision(bidId string, params model.SubmitBidDecisionParams) (*model.Bid, error)
 	GetBidsForTender(tenderId string, params model.GetBidsForTenderParams) ([]*model.Bid, error)
-	GetBidReviews(tenderId string, params model.GetBidReviewsParams) []*model.BidReview
+	GetBidReviews(tenderId string, params model.GetBidReviewsParams) ([]*model.BidReview, error)
 	GetTenders(params model.GetTendersParams) ([]*model.Tender, error)
+	GetTender(tenderId string, params model.GetTenderParams) (*model.Tender, error)
 	GetUserTenders(params model.GetUserTendersParams) ([]*model.Tender, error)
 	CreateTender(params model.CreateTenderJSONBody) (*model.Tender, error)
 	EditTender(tenderId string, par model.EditTenderParams, params model.EditTenderJSONBody) (*model.Tender, error)
 	RollbackTender(tenderId string, version int32, params model.RollbackTenderParams) (*model.Tender, error)
 	GetTenderStatus(tenderId string, params model.GetTenderStatusParams) (string, error)
 	UpdateTenderStatus(tenderId string, params model.UpdateTenderStatusParams) (*model.Tender, error)
+	CancelTender(tenderId string, params model.CancelTenderParams, body model.CancelTenderJSONBody) (*model.Tender, error)
+	CreateTenderLot(tenderId string, params model.CreateTenderLotParams, body model.CreateTenderLotJSONBody) (*model.TenderLot, error)
+	GetTenderLots(tenderId string, params model.GetTenderLotsParams) ([]*model.TenderLot, error)
+	CancelTenderLot(tenderId, lotId string, params model.CancelTenderLotParams) (*model.TenderLot, error)
+	RevealTenderBids(tenderId string, params model.RevealTenderBidsParams) (*model.Tender, error)
+	ConfigureAuction(tenderId string, params model.ConfigureAuctionParams, body model.ConfigureAuctionJSONBody) (*model.Auction, error)
+	GetAuction(tenderId string) (*model.Auction, error)
+	PlaceAuctionOffer(tenderId string, params model.PlaceAuctionOfferParams, body model.PlaceAuctionOfferJSONBody) (*model.Auction, error)
+	GetAuctionResults(tenderId string, params model.GetAuctionResultsParams) ([]*model.AuctionResult, error)
+	CreateEvaluationCriterion(tenderId string, params model.CreateEvaluationCriterionParams,
+		body model.CreateEvaluationCriterionJSONBody) (*model.EvaluationCriterion, error)
+	GetEvaluationCriteria(tenderId string, params model.GetEvaluationCriteriaParams) ([]*model.EvaluationCriterion, error)
+	SubmitBidScores(bidId string, params model.SubmitBidScoresParams, body []model.BidScoreJSONBody) ([]*model.BidScore, error)
+	GetTenderEvaluation(tenderId string, params model.GetTenderEvaluationParams) (*model.TenderEvaluation, error)
+	CreateTenderQuestion(tenderId string, params model.CreateTenderQuestionParams,
+		body model.CreateTenderQuestionJSONBody) (*model.TenderQuestion, error)
+	AnswerTenderQuestion(tenderId, questionId string, params model.AnswerTenderQuestionParams,
+		body model.AnswerTenderQuestionJSONBody) (*model.TenderQuestion, error)
+	GetTenderQuestions(tenderId string, params model.GetTenderQuestionsParams) ([]*model.TenderQuestion, error)
+	UploadAttachment(parentType, parentId string, params model.UploadAttachmentParams,
+		fileName, contentType string, size int64, content io.Reader) (*model.Attachment, error)
+	GetAttachments(parentType, parentId string, params model.GetAttachmentsParams) ([]*model.Attachment, error)
+	DownloadAttachment(attachmentId string, params model.GetAttachmentsParams) (*model.Attachment, io.ReadCloser, error)
+	CreateTenderInvitation(tenderId string, params model.CreateTenderInvitationParams,
+		body model.CreateTenderInvitationJSONBody) (*model.TenderInvitation, error)
+	GetTenderInvitations(tenderId string, params model.GetTenderInvitationsParams) ([]*model.TenderInvitation, error)
+	DeleteTenderInvitation(tenderId, organizationId string, params model.DeleteTenderInvitationParams) (*model.TenderInvitation, error)
+	GetInvitedTenders(params model.GetInvitedTendersParams) ([]*model.Tender, error)
+	CloneTender(tenderId string, params model.CloneTenderParams) (*model.Tender, error)
+	CreateTenderTemplate(organizationId string, params model.CreateTenderTemplateParams,
+		body model.CreateTenderTemplateJSONBody) (*model.TenderTemplate, error)
+	GetTenderTemplates(organizationId string, params model.GetTenderTemplatesParams) ([]*model.TenderTemplate, error)
+	InstantiateTenderTemplate(templateId string, params model.InstantiateTenderTemplateParams,
+		body model.InstantiateTenderTemplateJSONBody) (*model.Tender, error)
+	WithdrawBid(bidId string, params model.WithdrawBidParams, body model.WithdrawBidJSONBody) (*model.Bid, error)
+	ResubmitBid(bidId string, params model.ResubmitBidParams) (*model.Bid, error)
+	GetBidWithdrawals(tenderId string, params model.GetBidWithdrawalsParams) ([]*model.BidWithdrawal, error)
+	BlockOrganization(organizationId string, params model.BlockOrganizationParams,
+		body model.BlockOrganizationJSONBody) (*model.BlockedOrganization, error)
+	GetOrganizationBlocklist(organizationId string, params model.GetOrganizationBlocklistParams) ([]*model.BlockedOrganization, error)
+	UnblockOrganization(organizationId, blockedOrganizationId string,
+		params model.UnblockOrganizationParams) (*model.BlockedOrganization, error)
+	CreateCounterOffer(bidId string, params model.CreateCounterOfferParams,
+		body model.CreateCounterOfferJSONBody) (*model.BidNegotiationRound, error)
+	AcceptCounterOffer(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error)
+	DeclineCounterOffer(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error)
+	GetBidNegotiation(bidId string, params model.GetBidNegotiationParams) ([]*model.BidNegotiationRound, error)
+	SetTenderShortlist(tenderId string, params model.SetTenderShortlistParams,
+		body model.SetTenderShortlistJSONBody) (*model.TenderShortlist, error)
+	GetTenderShortlist(tenderId string, params model.GetTenderShortlistParams) (*model.TenderShortlist, error)
+	OpenBafoRound(tenderId string, params model.OpenBafoRoundParams, body model.OpenBafoRoundJSONBody) (*model.Tender, error)
+	SubmitBafo(bidId string, params model.SubmitBafoParams, body model.SubmitBafoJSONBody) (*model.Bid, error)
+	GetBidVersions(bidId string, params model.GetBidVersionsParams) ([]*model.BidVersion, error)
+	GetOrganizationAwards(organizationId string, params model.GetOrganizationAwardsParams) ([]*model.TenderAward, error)
+	GetTenderAward(awardId string, params model.GetTenderAwardParams) (*model.TenderAward, error)
+	TenderAwardPDF(awardId string, params model.GetTenderAwardParams) (*model.TenderAward, []byte, error)
+	SetTenderAwardSet(tenderId string, params model.SetTenderAwardSetParams,
+		body model.SetTenderAwardSetJSONBody) (*model.TenderAwardSet, error)
+	GetTenderAwardSet(tenderId string, params model.GetTenderAwardSetParams) (*model.TenderAwardSet, error)
+	FinalizeTenderAwardSet(tenderId string, params model.FinalizeTenderAwardSetParams) (*model.TenderAwardSet, error)
+	GetOrganizationReputation(organizationId string, params model.GetOrganizationReputationParams) (*model.SupplierReputation, error)
+	CancelTenderAward(awardId string, params model.CancelTenderAwardParams,
+		body model.CancelTenderAwardJSONBody) (*model.TenderAward, error)
+	RecordAwardDelivery(awardId string, params model.RecordAwardDeliveryParams,
+		body model.RecordAwardDeliveryJSONBody) (*model.TenderAward, error)
+	WatchTender(tenderId string, params model.WatchTenderParams) (*model.Tender, error)
+	UnwatchTender(tenderId string, params model.UnwatchTenderParams) (*model.Tender, error)
+	GetWatchedTenders(params model.GetWatchedTendersParams) ([]*model.Tender, error)
+	CreateSavedSearch(params model.CreateSavedSearchParams) (*model.SavedSearch, error)
+	GetSavedSearches(params model.GetSavedSearchesParams) ([]*model.SavedSearch, error)
+	DeleteSavedSearch(searchId string, params model.DeleteSavedSearchParams) (*model.SavedSearch, error)
+	GetNotifications(params model.GetNotificationsParams) (*model.NotificationInbox, error)
+	MarkNotificationsRead(params model.MarkNotificationsReadParams,
+		body model.MarkNotificationsReadJSONBody) ([]*model.Notification, error)
+	GetNotificationPreferences(params model.GetNotificationPreferencesParams) ([]*model.NotificationPreference, error)
+	SetNotificationPreferences(params model.SetNotificationPreferencesParams,
+		body model.SetNotificationPreferencesJSONBody) ([]*model.NotificationPreference, error)
+	CreateWebhook(organizationId string, params model.CreateWebhookParams,
+		body model.CreateWebhookJSONBody) (*model.Webhook, error)
+	GetWebhooks(organizationId string, params model.GetWebhooksParams) ([]*model.Webhook, error)
+	DeleteWebhook(organizationId, webhookId string, params model.DeleteWebhookParams) (*model.Webhook, error)
+	GetWebhookDeliveries(organizationId, webhookId string,
+		params model.GetWebhookDeliveriesParams) ([]*model.WebhookDelivery, error)
+	GetWebhookDeliveryAttempts(deliveryId string,
+		params model.GetWebhookDeliveryAttemptsParams) ([]*model.WebhookDeliveryAttempt, error)
+	ReplayWebhookDelivery(deliveryId string, params model.ReplayWebhookDeliveryParams) (*model.WebhookDelivery, error)
+	GetTenderEvents(tenderId string, params model.GetTenderEventsParams) ([]*model.OutboxEvent, error)
+	SubscribeEvents(buffer int) (<-chan *model.OutboxEvent, func())
+	GetAuditLog(params model.GetAuditLogParams) ([]*model.AuditEntry, error)
 }
 
 func (h *Handler) CreateTender(w http.ResponseWriter, r *http.Request) {
@@ -38,7 +130,7 @@ func (h *Handler) CreateTender(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tender, err := h.Service.CreateTender(params)
+	tender, err := h.service(r).CreateTender(params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "cannot create a tender")
 		return
@@ -72,8 +164,14 @@ func (h *Handler) GetTenders(w http.ResponseWriter, r *http.Request) {
 	params.Limit = int32(limit)
 	params.Offset = int32(offset)
 	params.ServiceType = queryParams["service_type"]
+	params.Query = strings.TrimSpace(queryParams.Get("q"))
 
-	tenders, err := h.Service.GetTenders(params)
+	if params.Filter, err = parseListFilter(queryParams, tenderSortFields); err != nil {
+		jsonRespond(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenders, err := h.service(r).GetTenders(params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "cannot get tenders from service")
 		return
@@ -109,7 +207,12 @@ func (h *Handler) GetUserTenders(w http.ResponseWriter, r *http.Request) {
 	params.Offset = int32(offset)
 	params.Username = queryParams.Get("username")
 
-	myTenders, err := h.Service.GetUserTenders(params)
+	if params.Filter, err = parseListFilter(queryParams, tenderSortFields); err != nil {
+		jsonRespond(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	myTenders, err := h.service(r).GetUserTenders(params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "cannot get user tenders from service")
 		return
@@ -123,6 +226,31 @@ func (h *Handler) GetUserTenders(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) GetTender(w http.ResponseWriter, r *http.Request) {
+	var params model.GetTenderParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	tender, err := h.service(r).GetTender(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get a tender from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(tender); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a tender")
+		return
+	}
+}
+
 func (h *Handler) GetTenderStatus(w http.ResponseWriter, r *http.Request) {
 	var params model.GetTenderStatusParams
 	vars := mux.Vars(r)
@@ -134,7 +262,7 @@ func (h *Handler) GetTenderStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status, err := h.Service.GetTenderStatus(tenderId, params)
+	status, err := h.service(r).GetTenderStatus(tenderId, params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "tender status not found")
 		return
@@ -158,7 +286,7 @@ func (h *Handler) UpdateTenderStatus(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
 	params.Status, params.Username = queryParams.Get("status"), queryParams.Get("username")
 
-	tender, err := h.Service.UpdateTenderStatus(tenderId, params)
+	tender, err := h.service(r).UpdateTenderStatus(tenderId, params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "tender can not be updated")
 		return
@@ -172,6 +300,42 @@ func (h *Handler) UpdateTenderStatus(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) CancelTender(w http.ResponseWriter, r *http.Request) {
+	var body model.CancelTenderJSONBody
+	var params model.CancelTenderParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a cancellation body")
+		return
+	}
+
+	if strings.TrimSpace(body.Reason) == "" {
+		jsonRespond(w, http.StatusBadRequest, "cancellation reason is required")
+		return
+	}
+
+	tender, err := h.service(r).CancelTender(tenderId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "tender can not be canceled")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(tender); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a canceled tender")
+		return
+	}
+}
+
 func (h *Handler) EditTender(w http.ResponseWriter, r *http.Request) {
 	var params model.EditTenderJSONBody
 	var par model.EditTenderParams
@@ -192,7 +356,7 @@ func (h *Handler) EditTender(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	editedTender, err := h.Service.EditTender(tenderId, par, params)
+	editedTender, err := h.service(r).EditTender(tenderId, par, params)
 	if err != nil && err.Error() != "no updates provided" {
 		jsonRespond(w, http.StatusInternalServerError, "tender can not be edited")
 		return
@@ -206,6 +370,31 @@ func (h *Handler) EditTender(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) RevealTenderBids(w http.ResponseWriter, r *http.Request) {
+	var params model.RevealTenderBidsParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	tender, err := h.service(r).RevealTenderBids(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "tender bids can not be revealed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(tender); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a revealed tender")
+		return
+	}
+}
+
 func (h *Handler) CreateBid(w http.ResponseWriter, r *http.Request) {
 	var params model.CreateBidJSONBody
 	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
@@ -213,7 +402,7 @@ func (h *Handler) CreateBid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bid, err := h.Service.CreateBid(params)
+	bid, err := h.service(r).CreateBid(params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "cannot create a bid")
 		return
@@ -247,7 +436,12 @@ func (h *Handler) GetUserBids(w http.ResponseWriter, r *http.Request) {
 	params.Offset = int32(offset)
 	params.Username = queryParams.Get("username")
 
-	myBids, err := h.Service.GetUserBids(params)
+	if params.Filter, err = parseListFilter(queryParams, bidSortFields); err != nil {
+		jsonRespond(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	myBids, err := h.service(r).GetUserBids(params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "cannot get user bids from service")
 		return
@@ -284,8 +478,22 @@ func (h *Handler) GetBidsForTender(w http.ResponseWriter, r *http.Request) {
 	params.Limit = int32(limit)
 	params.Offset = int32(offset)
 	params.Username = queryParams.Get("username")
+	params.WithReputation = queryParams.Get("with_reputation") == "true"
+
+	// sort_by и order - прежняя форма сортировки, сохраненная для совместимости.
+	if !queryParams.Has("sort") && queryParams.Get("sort_by") != "" {
+		queryParams.Set("sort", queryParams.Get("sort_by")+":"+queryParams.Get("order"))
+	} else if order := queryParams.Get("order"); order != "" && order != "asc" && order != "desc" {
+		jsonRespond(w, http.StatusBadRequest, "order must be asc or desc")
+		return
+	}
+
+	if params.Filter, err = parseListFilter(queryParams, bidSortFields); err != nil {
+		jsonRespond(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	bids, err := h.Service.GetBidsForTender(tenderId, params)
+	bids, err := h.service(r).GetBidsForTender(tenderId, params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "cannot get bids for tender from service")
 		return
@@ -311,7 +519,7 @@ func (h *Handler) GetBidStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status, err := h.Service.GetBidStatus(bidId, params)
+	status, err := h.service(r).GetBidStatus(bidId, params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "bid status not found")
 		return
@@ -336,7 +544,7 @@ func (h *Handler) UpdateBidStatus(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
 	params.Status, params.Username = queryParams.Get("status"), queryParams.Get("username")
 
-	bid, err := h.Service.UpdateBidStatus(bidId, params)
+	bid, err := h.service(r).UpdateBidStatus(bidId, params)
 	if err != nil {
 		jsonRespond(w, http.StatusInternalServerError, "bid status can not be updated")
 		return
@@ -371,7 +579,7 @@ func (h *Handler) EditBid(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	editedBid, err := h.Service.EditBid(bidId, params, body)
+	editedBid, err := h.service(r).EditBid(bidId, params, body)
 	if err != nil && err.Error() != "no updates provided" {
 		jsonRespond(w, http.StatusInternalServerError, "bid can not be edited")
 		return
@@ -384,6 +592,47 @@ func (h *Handler) EditBid(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) SubmitBidDecision(w http.ResponseWriter, r *http.Request) {
+	var params model.SubmitBidDecisionParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	queryParams := r.URL.Query()
+	params.Decision, params.Username = queryParams.Get("decision"), queryParams.Get("username")
+	params.LotId = queryParams.Get("lotId")
+	params.Rationale = queryParams.Get("rationale")
+
+	var body model.SubmitBidDecisionJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err == nil && body.Rationale != "" {
+		params.Rationale = body.Rationale
+	}
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+	if params.LotId != "" && !IsValidUUID(params.LotId) {
+		jsonRespond(w, http.StatusBadRequest, "lot id is invalid")
+		return
+	}
+	if params.Decision != "Accepted" && params.Decision != "Rejected" {
+		jsonRespond(w, http.StatusBadRequest, "decision must be Accepted or Rejected")
+		return
+	}
+
+	bid, err := h.service(r).SubmitBidDecision(bidId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "bid decision can not be submitted")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(bid); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a bid with decision")
+		return
+	}
+}
+
 func jsonRespond(w http.ResponseWriter, statusCode int, data string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) WatchTender(w http.ResponseWriter, r *http.Request) {
+	var params model.WatchTenderParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	tender, err := h.service(r).WatchTender(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "tender can not be watched")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(tender); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a watched tender")
+		return
+	}
+}
+
+func (h *Handler) UnwatchTender(w http.ResponseWriter, r *http.Request) {
+	var params model.UnwatchTenderParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	tender, err := h.service(r).UnwatchTender(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "tender can not be unwatched")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(tender); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode an unwatched tender")
+		return
+	}
+}
+
+func (h *Handler) GetWatchedTenders(w http.ResponseWriter, r *http.Request) {
+	var params model.GetWatchedTendersParams
+	queryParams := r.URL.Query()
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+
+	tenders, err := h.service(r).GetWatchedTenders(params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get watched tenders from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(tenders); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of watched tenders")
+		return
+	}
+}
+
+// CreateSavedSearch принимает те же параметры фильтрации, что и GET /api/tenders. Статус и сортировка
+// не сохраняются: оповещения приходят только об опубликованных тендерах.
+func (h *Handler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	var params model.CreateSavedSearchParams
+	queryParams := r.URL.Query()
+
+	if queryParams.Has("status") || queryParams.Has("sort") {
+		jsonRespond(w, http.StatusBadRequest, "status and sort are not supported in saved searches")
+		return
+	}
+
+	var err error
+	params.Username = queryParams.Get("username")
+	params.Name = strings.TrimSpace(queryParams.Get("name"))
+	params.Search.ServiceType = queryParams["service_type"]
+	params.Search.Query = strings.TrimSpace(queryParams.Get("q"))
+	if params.Search.Filter, err = parseListFilter(queryParams, tenderSortFields); err != nil {
+		jsonRespond(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	search, err := h.service(r).CreateSavedSearch(params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "saved search can not be created")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(search); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a saved search")
+		return
+	}
+}
+
+func (h *Handler) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	var params model.GetSavedSearchesParams
+	queryParams := r.URL.Query()
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+
+	searches, err := h.service(r).GetSavedSearches(params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get saved searches from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(searches); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of saved searches")
+		return
+	}
+}
+
+func (h *Handler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	var params model.DeleteSavedSearchParams
+	vars := mux.Vars(r)
+	searchId := vars["searchId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(searchId) {
+		jsonRespond(w, http.StatusBadRequest, "search id is invalid")
+		return
+	}
+
+	search, err := h.service(r).DeleteSavedSearch(searchId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "saved search can not be deleted")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(search); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a deleted saved search")
+		return
+	}
+}
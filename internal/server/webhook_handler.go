@@ -0,0 +1,181 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var body model.CreateWebhookJSONBody
+	var params model.CreateWebhookParams
+	organizationId := mux.Vars(r)["organizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a webhook body")
+		return
+	}
+
+	webhook, err := h.service(r).CreateWebhook(organizationId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "webhook can not be created")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(webhook); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a webhook")
+		return
+	}
+}
+
+func (h *Handler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	var params model.GetWebhooksParams
+	organizationId := mux.Vars(r)["organizationId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id is invalid")
+		return
+	}
+
+	webhooks, err := h.service(r).GetWebhooks(organizationId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get webhooks from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(webhooks); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode webhooks")
+		return
+	}
+}
+
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	var params model.DeleteWebhookParams
+	vars := mux.Vars(r)
+	organizationId := vars["organizationId"]
+	webhookId := vars["webhookId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(organizationId) || !IsValidUUID(webhookId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id or webhook id is invalid")
+		return
+	}
+
+	webhook, err := h.service(r).DeleteWebhook(organizationId, webhookId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "webhook can not be deleted")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(webhook); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a webhook")
+		return
+	}
+}
+
+func (h *Handler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	var params model.GetWebhookDeliveriesParams
+	vars := mux.Vars(r)
+	organizationId := vars["organizationId"]
+	webhookId := vars["webhookId"]
+	queryParams := r.URL.Query()
+
+	if !IsValidUUID(organizationId) || !IsValidUUID(webhookId) {
+		jsonRespond(w, http.StatusBadRequest, "organization id or webhook id is invalid")
+		return
+	}
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+	params.Status = queryParams.Get("status")
+
+	deliveries, err := h.service(r).GetWebhookDeliveries(organizationId, webhookId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get webhook deliveries from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(deliveries); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode webhook deliveries")
+		return
+	}
+}
+
+func (h *Handler) GetWebhookDeliveryAttempts(w http.ResponseWriter, r *http.Request) {
+	var params model.GetWebhookDeliveryAttemptsParams
+	deliveryId := mux.Vars(r)["deliveryId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(deliveryId) {
+		jsonRespond(w, http.StatusBadRequest, "delivery id is invalid")
+		return
+	}
+
+	attempts, err := h.service(r).GetWebhookDeliveryAttempts(deliveryId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get delivery attempts from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(attempts); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode delivery attempts")
+		return
+	}
+}
+
+func (h *Handler) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	var params model.ReplayWebhookDeliveryParams
+	deliveryId := mux.Vars(r)["deliveryId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(deliveryId) {
+		jsonRespond(w, http.StatusBadRequest, "delivery id is invalid")
+		return
+	}
+
+	delivery, err := h.service(r).ReplayWebhookDelivery(deliveryId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "webhook delivery can not be replayed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(delivery); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a webhook delivery")
+		return
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/instinctG/tender/internal/model"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) WithdrawBid(w http.ResponseWriter, r *http.Request) {
+	var body model.WithdrawBidJSONBody
+	var params model.WithdrawBidParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		jsonRespond(w, http.StatusBadRequest, "error in decoding a withdrawal body")
+		return
+	}
+
+	bid, err := h.service(r).WithdrawBid(bidId, params, body)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "bid can not be withdrawn")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(bid); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a withdrawn bid")
+		return
+	}
+}
+
+func (h *Handler) ResubmitBid(w http.ResponseWriter, r *http.Request) {
+	var params model.ResubmitBidParams
+	vars := mux.Vars(r)
+	bidId := vars["bidId"]
+	params.Username = r.URL.Query().Get("username")
+
+	if !IsValidUUID(bidId) {
+		jsonRespond(w, http.StatusBadRequest, "bid id is invalid")
+		return
+	}
+
+	bid, err := h.service(r).ResubmitBid(bidId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "bid can not be resubmitted")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(bid); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a resubmitted bid")
+		return
+	}
+}
+
+func (h *Handler) GetBidWithdrawals(w http.ResponseWriter, r *http.Request) {
+	var params model.GetBidWithdrawalsParams
+	vars := mux.Vars(r)
+	tenderId := vars["tenderId"]
+	queryParams := r.URL.Query()
+
+	if !IsValidUUID(tenderId) {
+		jsonRespond(w, http.StatusBadRequest, "tender id is invalid")
+		return
+	}
+
+	limit, err := strconv.Atoi(queryParams.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(queryParams.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	params.Limit = int32(limit)
+	params.Offset = int32(offset)
+	params.Username = queryParams.Get("username")
+
+	withdrawals, err := h.service(r).GetBidWithdrawals(tenderId, params)
+	if err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot get bid withdrawals from service")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(withdrawals); err != nil {
+		jsonRespond(w, http.StatusInternalServerError, "cannot encode a list of bid withdrawals")
+		return
+	}
+}
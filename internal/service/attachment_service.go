@@ -0,0 +1,176 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/instinctG/tender/internal/model"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxAttachmentSize - предельный размер вложения, если он не задан явно.
+const DefaultMaxAttachmentSize = 20 << 20
+
+// allowedAttachmentTypes - MIME-типы, которые можно прикладывать к тендерам и предложениям.
+var allowedAttachmentTypes = map[string]bool{
+	"application/pdf":    true,
+	"application/msword": true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+	"application/vnd.ms-excel": true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": true,
+	"application/zip": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"text/plain":      true,
+	"text/csv":        true,
+}
+
+// sniffedAttachmentTypes - типы, которые надежно распознаются по содержимому, поэтому
+// заявленный клиентом тип сверяется с фактическим.
+var sniffedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+}
+
+// attachmentContentType определяет тип вложения по заявленному клиентом и первым байтам файла.
+func attachmentContentType(declared string, head []byte) (string, error) {
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+
+	contentType := detected
+	if declared != "" {
+		mediaType, _, err := mime.ParseMediaType(declared)
+		if err != nil {
+			return "", errors.New("invalid content type")
+		}
+		if mediaType != "application/octet-stream" {
+			contentType = mediaType
+		}
+	}
+
+	if !allowedAttachmentTypes[contentType] {
+		return "", fmt.Errorf("content type %s is not allowed", contentType)
+	}
+	if sniffedAttachmentTypes[contentType] && detected != contentType {
+		return "", errors.New("file content does not match its content type")
+	}
+
+	return contentType, nil
+}
+
+// attachmentKey выдает новый ключ блоба для вложения объекта.
+func attachmentKey(parentType, parentId string) string {
+	return fmt.Sprintf("%ss/%s/%s", strings.ToLower(parentType), parentId, uuid.NewString())
+}
+
+// UploadAttachment проверяет права и ограничения, потоково записывает файл в блоб-хранилище,
+// считая SHA-256, и сохраняет метаданные. Если метаданные сохранить не удалось, файл удаляется.
+func (s *Service) UploadAttachment(parentType, parentId string, params model.UploadAttachmentParams,
+	fileName, contentType string, size int64, content io.Reader) (*model.Attachment, error) {
+	if s.Blobs == nil {
+		return nil, errors.New("attachment storage is not configured")
+	}
+
+	maxSize := s.MaxAttachmentSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxAttachmentSize
+	}
+	if size <= 0 {
+		return nil, errors.New("file is empty")
+	}
+	if size > maxSize {
+		return nil, fmt.Errorf("file exceeds %d bytes", maxSize)
+	}
+
+	fileName = filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	if fileName == "." || fileName == "/" || len(fileName) > 255 {
+		return nil, errors.New("invalid file name")
+	}
+
+	if err := s.Store.CheckAttachmentUpload(parentType, parentId, params); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType, err = attachmentContentType(contentType, head)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := model.Attachment{
+		ParentType:  parentType,
+		ParentId:    parentId,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  attachmentKey(parentType, parentId),
+	}
+
+	hash := sha256.New()
+	body := io.TeeReader(io.LimitReader(io.MultiReader(bytes.NewReader(head), content), size), hash)
+
+	ctx := context.Background()
+	if err = s.Blobs.Put(ctx, attachment.StorageKey, body, size, contentType); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	created, err := s.Store.CreateAttachment(attachment, params)
+	if err != nil {
+		fmt.Println(err)
+		if err := s.Blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			fmt.Println(err)
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) GetAttachments(parentType, parentId string, params model.GetAttachmentsParams) ([]*model.Attachment, error) {
+	attachments, err := s.Store.GetAttachments(parentType, parentId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// DownloadAttachment возвращает метаданные вложения и поток с его содержимым.
+// Поток закрывает вызывающий.
+func (s *Service) DownloadAttachment(attachmentId string, params model.GetAttachmentsParams) (*model.Attachment, io.ReadCloser, error) {
+	if s.Blobs == nil {
+		return nil, nil, errors.New("attachment storage is not configured")
+	}
+
+	attachment, err := s.Store.GetAttachment(attachmentId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, nil, err
+	}
+
+	content, err := s.Blobs.Get(context.Background(), attachment.StorageKey)
+	if err != nil {
+		fmt.Println(err)
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"time"
+)
+
+func (s *Service) ConfigureAuction(tenderId string, params model.ConfigureAuctionParams, body model.ConfigureAuctionJSONBody) (*model.Auction, error) {
+	if !body.EndsAt.After(body.StartsAt) {
+		return nil, errors.New("auction must end after it starts")
+	}
+	if !body.StartsAt.After(time.Now()) {
+		return nil, errors.New("auction must start in the future")
+	}
+	if body.MinDecrement.Amount <= 0 {
+		return nil, errors.New("minimum decrement must be positive")
+	}
+	if err := validateMoney(&body.MinDecrement); err != nil {
+		return nil, err
+	}
+	if body.ExtensionWindowSeconds < 0 || body.ExtensionSeconds < 0 {
+		return nil, errors.New("anti-sniping settings must not be negative")
+	}
+
+	auction, err := s.Store.ConfigureAuction(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+func (s *Service) GetAuction(tenderId string) (*model.Auction, error) {
+	auction, err := s.Store.GetAuction(tenderId)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+func (s *Service) PlaceAuctionOffer(tenderId string, params model.PlaceAuctionOfferParams, body model.PlaceAuctionOfferJSONBody) (*model.Auction, error) {
+	if body.Amount <= 0 {
+		return nil, errors.New("offer amount must be positive")
+	}
+
+	auction, err := s.Store.PlaceAuctionOffer(tenderId, params, body, applyAuctionOffer)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+func (s *Service) GetAuctionResults(tenderId string, params model.GetAuctionResultsParams) ([]*model.AuctionResult, error) {
+	results, err := s.Store.GetAuctionResults(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// applyAuctionOffer проверяет ставку по правилам реверсивного аукциона и обновляет состояние торгов.
+// Ставка должна быть ниже текущей лучшей цены минимум на шаг; ставка, пришедшая в окно
+// антиснайпинга перед концом торгов, продлевает их.
+func applyAuctionOffer(auction *model.Auction, amount int64, now time.Time) error {
+	if now.Before(auction.StartsAt) {
+		return errors.New("auction has not started yet")
+	}
+	if !now.Before(auction.EndsAt) {
+		return errors.New("auction is finished")
+	}
+
+	if auction.BestPrice != nil {
+		maxAllowed := auction.BestPrice.Amount - auction.MinDecrement.Amount
+		if amount > maxAllowed {
+			return fmt.Errorf("offer must not exceed %d", maxAllowed)
+		}
+	}
+
+	window := time.Duration(auction.ExtensionWindowSeconds) * time.Second
+	if auction.EndsAt.Sub(now) <= window {
+		extended := now.Add(time.Duration(auction.ExtensionSeconds) * time.Second)
+		if extended.After(auction.EndsAt) {
+			auction.EndsAt = extended
+		}
+	}
+
+	auction.BestPrice = &model.Money{Amount: amount, Currency: auction.MinDecrement.Currency}
+	auction.Status = "Live"
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+// WithStore возвращает копию сервиса, работающую с хранилищем store. Так к вызовам одного запроса
+// подключается хранилище, которое помечает записи аудита идентификатором этого запроса.
+func (s *Service) WithStore(store Store) *Service {
+	scoped := *s
+	scoped.Store = store
+	return &scoped
+}
+
+func (s *Service) GetAuditLog(params model.GetAuditLogParams) ([]*model.AuditEntry, error) {
+	if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	entries, err := s.Store.GetAuditLog(params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return entries, nil
+}
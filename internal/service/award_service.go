@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/instinctG/tender/internal/pdf"
+)
+
+func (s *Service) GetOrganizationAwards(organizationId string, params model.GetOrganizationAwardsParams) ([]*model.TenderAward, error) {
+	awards, err := s.Store.GetOrganizationAwards(organizationId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return awards, nil
+}
+
+func (s *Service) GetTenderAward(awardId string, params model.GetTenderAwardParams) (*model.TenderAward, error) {
+	award, err := s.Store.GetTenderAward(awardId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return award, nil
+}
+
+// TenderAwardPDF формирует PDF-сводку присуждения.
+func (s *Service) TenderAwardPDF(awardId string, params model.GetTenderAwardParams) (*model.TenderAward, []byte, error) {
+	award, err := s.GetTenderAward(awardId, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc := pdf.New("Contract award summary")
+	doc.Field("Award", award.Id)
+	doc.Field("Award date", award.AwardedAt.Format("2006-01-02 15:04 MST"))
+	doc.Blank()
+	doc.Field("Tender", award.TenderName)
+	doc.Field("Tender id", award.TenderId)
+	if award.LotId != "" {
+		doc.Field("Lot", award.LotName)
+		doc.Field("Lot id", award.LotId)
+	}
+	doc.Blank()
+	doc.Field("Buyer", award.BuyerOrganizationName)
+	doc.Field("Buyer id", award.BuyerOrganizationId)
+	doc.Field("Supplier", fmt.Sprintf("%s (%s)", award.SupplierName, award.SupplierType))
+	doc.Field("Supplier id", award.SupplierId)
+	doc.Blank()
+	doc.Field("Winning bid", award.BidName)
+	doc.Field("Bid id", award.BidId)
+	doc.Field("Bid version", fmt.Sprint(award.BidVersion))
+	doc.Field("Agreed price", formatMoney(award.Price))
+	if award.Rationale != "" {
+		doc.Blank()
+		doc.Field("Rationale", award.Rationale)
+	}
+	if award.CanceledAt != nil {
+		doc.Blank()
+		doc.Field("Canceled", award.CanceledAt.Format("2006-01-02 15:04 MST"))
+		doc.Field("Cancel reason", award.CancelReason)
+	}
+	if award.DeliveredAt != nil {
+		doc.Blank()
+		doc.Field("Delivered", award.DeliveredAt.Format("2006-01-02 15:04 MST"))
+		doc.Field("Delivered on time", fmt.Sprint(*award.DeliveredOnTime))
+	}
+
+	return award, doc.Bytes(), nil
+}
+
+// formatMoney выводит сумму из минорных единиц с двумя знаками после запятой.
+func formatMoney(m *model.Money) string {
+	if m == nil {
+		return "not specified"
+	}
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/100, amount%100, m.Currency)
+}
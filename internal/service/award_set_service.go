@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"math"
+)
+
+// validateAwardShares проверяет доли набора присуждений: проценты с точностью до сотых в сумме дают 100,
+// количества - целые и в сумме дают totalQuantity. Для многолотового тендера суммы считаются по каждому лоту.
+func validateAwardShares(body model.SetTenderAwardSetJSONBody) error {
+	if len(body.Shares) == 0 {
+		return errors.New("award set must contain at least one share")
+	}
+
+	var unit, total int64
+	switch body.Kind {
+	case "Percentage":
+		unit, total = 100, 100*100
+	case "Quantity":
+		if body.TotalQuantity <= 0 {
+			return errors.New("total quantity must be positive")
+		}
+		unit, total = 1, body.TotalQuantity
+	default:
+		return errors.New("award share kind must be Percentage or Quantity")
+	}
+
+	sums := make(map[string]int64)
+	seen := make(map[string]bool)
+	for _, share := range body.Shares {
+		key := share.LotId + "/" + share.BidId
+		if seen[key] {
+			return errors.New("award set contains duplicate shares")
+		}
+		seen[key] = true
+
+		scaled := math.Round(share.Share * float64(unit))
+		if share.Share <= 0 || math.Abs(scaled-share.Share*float64(unit)) > 1e-6 {
+			return fmt.Errorf("share of bid %s is invalid", share.BidId)
+		}
+		sums[share.LotId] += int64(scaled)
+	}
+
+	for lotId, sum := range sums {
+		if sum != total {
+			if lotId == "" {
+				return errors.New("award shares must add up to the total")
+			}
+			return fmt.Errorf("award shares of lot %s must add up to the total", lotId)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) SetTenderAwardSet(tenderId string, params model.SetTenderAwardSetParams,
+	body model.SetTenderAwardSetJSONBody) (*model.TenderAwardSet, error) {
+	if err := validateAwardShares(body); err != nil {
+		return nil, err
+	}
+
+	awardSet, err := s.Store.SetTenderAwardSet(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return awardSet, nil
+}
+
+func (s *Service) GetTenderAwardSet(tenderId string, params model.GetTenderAwardSetParams) (*model.TenderAwardSet, error) {
+	awardSet, err := s.Store.GetTenderAwardSet(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return awardSet, nil
+}
+
+func (s *Service) FinalizeTenderAwardSet(tenderId string, params model.FinalizeTenderAwardSetParams) (*model.TenderAwardSet, error) {
+	if params.Rationale == "" {
+		return nil, errors.New("award rationale is required")
+	}
+
+	awardSet, err := s.Store.FinalizeTenderAwardSet(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return awardSet, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestValidateAwardShares(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    model.SetTenderAwardSetJSONBody
+		wantErr bool
+	}{
+		{
+			name: "percentages add up to 100 in hundredths",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+				{BidId: "a", Share: 33.33}, {BidId: "b", Share: 33.33}, {BidId: "c", Share: 33.34},
+			}},
+		},
+		{
+			name: "percentages short by a hundredth",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+				{BidId: "a", Share: 33.33}, {BidId: "b", Share: 33.33}, {BidId: "c", Share: 33.33},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "percentage finer than a hundredth",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+				{BidId: "a", Share: 50.005}, {BidId: "b", Share: 49.995},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "quantities add up to the total",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Quantity", TotalQuantity: 10, Shares: []model.AwardShare{
+				{BidId: "a", Share: 7}, {BidId: "b", Share: 3},
+			}},
+		},
+		{
+			name: "fractional quantity",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Quantity", TotalQuantity: 10, Shares: []model.AwardShare{
+				{BidId: "a", Share: 6.5}, {BidId: "b", Share: 3.5},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "quantity without total",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Quantity", Shares: []model.AwardShare{
+				{BidId: "a", Share: 1},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "non-positive share",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+				{BidId: "a", Share: 100}, {BidId: "b", Share: 0},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate lot and bid pair",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+				{LotId: "l1", BidId: "a", Share: 50}, {LotId: "l1", BidId: "a", Share: 50},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "same bid on different lots",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+				{LotId: "l1", BidId: "a", Share: 100}, {LotId: "l2", BidId: "a", Share: 100},
+			}},
+		},
+		{
+			name: "one lot of a multi-lot set does not add up",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+				{LotId: "l1", BidId: "a", Share: 60}, {LotId: "l1", BidId: "b", Share: 40},
+				{LotId: "l2", BidId: "a", Share: 60},
+			}},
+			wantErr: true,
+		},
+		{
+			// Покрытие всех открытых лотов проверяет хранилище: сервис не знает лотов тендера.
+			name: "multi-lot set not covering every lot",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Percentage", Shares: []model.AwardShare{
+				{LotId: "l1", BidId: "a", Share: 60}, {LotId: "l1", BidId: "b", Share: 40},
+			}},
+		},
+		{
+			name:    "empty set",
+			body:    model.SetTenderAwardSetJSONBody{Kind: "Percentage"},
+			wantErr: true,
+		},
+		{
+			name: "unknown kind",
+			body: model.SetTenderAwardSetJSONBody{Kind: "Weight", Shares: []model.AwardShare{
+				{BidId: "a", Share: 100},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAwardShares(tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateAwardShares() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
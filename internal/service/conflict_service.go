@@ -0,0 +1,46 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+func (s *Service) BlockOrganization(organizationId string, params model.BlockOrganizationParams,
+	body model.BlockOrganizationJSONBody) (*model.BlockedOrganization, error) {
+	if body.OrganizationId == "" {
+		return nil, errors.New("organization id is required")
+	}
+	if body.OrganizationId == organizationId {
+		return nil, errors.New("organization cannot block itself")
+	}
+
+	blockedOrganization, err := s.Store.BlockOrganization(organizationId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return blockedOrganization, nil
+}
+
+func (s *Service) GetOrganizationBlocklist(organizationId string, params model.GetOrganizationBlocklistParams) ([]*model.BlockedOrganization, error) {
+	blocklist, err := s.Store.GetOrganizationBlocklist(organizationId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return blocklist, nil
+}
+
+func (s *Service) UnblockOrganization(organizationId, blockedOrganizationId string,
+	params model.UnblockOrganizationParams) (*model.BlockedOrganization, error) {
+	blockedOrganization, err := s.Store.UnblockOrganization(organizationId, blockedOrganizationId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return blockedOrganization, nil
+}
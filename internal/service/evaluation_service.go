@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"math"
+	"sort"
+)
+
+func validateCriterion(body model.CreateEvaluationCriterionJSONBody) error {
+	if body.Name == "" {
+		return errors.New("criterion name is required")
+	}
+	switch body.Kind {
+	case "Price", "DeliveryTime", "Experience", "Custom":
+	default:
+		return errors.New("criterion kind must be one of Price, DeliveryTime, Experience, Custom")
+	}
+	if body.Weight <= 0 {
+		return errors.New("criterion weight must be positive")
+	}
+	return nil
+}
+
+func (s *Service) CreateEvaluationCriterion(tenderId string, params model.CreateEvaluationCriterionParams,
+	body model.CreateEvaluationCriterionJSONBody) (*model.EvaluationCriterion, error) {
+	if err := validateCriterion(body); err != nil {
+		return nil, err
+	}
+
+	criterion, err := s.Store.CreateEvaluationCriterion(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return criterion, nil
+}
+
+func (s *Service) GetEvaluationCriteria(tenderId string, params model.GetEvaluationCriteriaParams) ([]*model.EvaluationCriterion, error) {
+	criteria, err := s.Store.GetEvaluationCriteria(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return criteria, nil
+}
+
+func (s *Service) SubmitBidScores(bidId string, params model.SubmitBidScoresParams, body []model.BidScoreJSONBody) ([]*model.BidScore, error) {
+	if len(body) == 0 {
+		return nil, errors.New("no scores provided")
+	}
+	for _, item := range body {
+		if item.Score < 0 || item.Score > 100 {
+			return nil, errors.New("score must be between 0 and 100")
+		}
+	}
+
+	scores, err := s.Store.SubmitBidScores(bidId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+func (s *Service) GetTenderEvaluation(tenderId string, params model.GetTenderEvaluationParams) (*model.TenderEvaluation, error) {
+	evaluation, err := s.Store.GetTenderEvaluation(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	rankEvaluation(evaluation)
+	return evaluation, nil
+}
+
+// rankEvaluation считает итоговый балл каждого предложения как средневзвешенное средних оценок
+// по критериям (критерий без оценок дает 0) и расставляет места по убыванию балла.
+func rankEvaluation(evaluation *model.TenderEvaluation) {
+	for _, bid := range evaluation.Bids {
+		var weighted float64
+		var totalWeight int32
+		bid.Complete = len(bid.Criteria) > 0
+
+		for _, criterion := range bid.Criteria {
+			weighted += float64(criterion.Weight) * criterion.AverageScore
+			totalWeight += criterion.Weight
+			if criterion.Evaluations == 0 {
+				bid.Complete = false
+			}
+		}
+
+		if totalWeight > 0 {
+			bid.TotalScore = math.Round(weighted/float64(totalWeight)*100) / 100
+		}
+	}
+
+	sort.SliceStable(evaluation.Bids, func(i, j int) bool {
+		return evaluation.Bids[i].TotalScore > evaluation.Bids[j].TotalScore
+	})
+
+	for i, bid := range evaluation.Bids {
+		bid.Rank = int32(i + 1)
+		if i > 0 && bid.TotalScore == evaluation.Bids[i-1].TotalScore {
+			bid.Rank = evaluation.Bids[i-1].Rank
+		}
+	}
+}
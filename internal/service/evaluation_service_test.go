@@ -0,0 +1,42 @@
+package service
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestRankEvaluationWeightsCriteria(t *testing.T) {
+	scores := func(price, experience float64, experienceEvaluations int32) []model.CriterionScore {
+		return []model.CriterionScore{
+			{CriterionId: "price", Weight: 3, AverageScore: price, Evaluations: 1},
+			{CriterionId: "experience", Weight: 1, AverageScore: experience, Evaluations: experienceEvaluations},
+		}
+	}
+	evaluation := &model.TenderEvaluation{Bids: []*model.BidEvaluation{
+		{BidId: "partial", Criteria: scores(90, 0, 0)},
+		{BidId: "first", Criteria: scores(80, 40, 1)},
+		{BidId: "tied", Criteria: scores(60, 100, 2)},
+		{BidId: "unscored"},
+	}}
+
+	rankEvaluation(evaluation)
+
+	want := []struct {
+		bidId    string
+		score    float64
+		rank     int32
+		complete bool
+	}{
+		{"first", 70, 1, true},
+		{"tied", 70, 1, true},
+		{"partial", 67.5, 3, false},
+		{"unscored", 0, 4, false},
+	}
+	for i, expected := range want {
+		bid := evaluation.Bids[i]
+		if bid.BidId != expected.bidId || bid.TotalScore != expected.score || bid.Rank != expected.rank ||
+			bid.Complete != expected.complete {
+			t.Fatalf("place %d = %+v, want %+v", i+1, bid, expected)
+		}
+	}
+}
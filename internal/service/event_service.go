@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+	"time"
+)
+
+// maxTenderEventsBatch - сколько событий тендера отдается за один запрос к базе.
+const maxTenderEventsBatch = 100
+
+// listenRetryInterval - пауза перед повторной подпиской на события после обрыва соединения.
+const listenRetryInterval = 5 * time.Second
+
+func (s *Service) GetTenderEvents(tenderId string, params model.GetTenderEventsParams) ([]*model.OutboxEvent, error) {
+	if params.Username == "" {
+		return nil, errors.New("username is required")
+	}
+	if params.LastEventId < 0 {
+		return nil, errors.New("last event id must not be negative")
+	}
+	if params.Limit <= 0 || params.Limit > maxTenderEventsBatch {
+		params.Limit = maxTenderEventsBatch
+	}
+
+	events, err := s.Store.GetTenderEvents(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// SubscribeEvents подписывает на события outbox всех реплик. Событие в канале - только сигнал:
+// что из него можно показать пользователю, решает GetTenderEvents.
+func (s *Service) SubscribeEvents(buffer int) (<-chan *model.OutboxEvent, func()) {
+	return s.Events.Subscribe(buffer)
+}
+
+// StartEventListener передает в Events события, опубликованные реле любой реплики, и переподписывается
+// после обрыва соединения с базой.
+func (s *Service) StartEventListener() {
+	go func() {
+		ctx := context.Background()
+		for {
+			err := s.Store.ListenOutboxEvents(ctx, func(event *model.OutboxEvent) {
+				if err := s.Events.Publish(ctx, event); err != nil {
+					log.Printf("failed to publish outbox event %s to the bus: %v", event.EventId, err)
+				}
+			})
+			log.Printf("outbox event listener stopped: %v", err)
+			time.Sleep(listenRetryInterval)
+		}
+	}()
+}
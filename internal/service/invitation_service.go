@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+// validateVisibility проверяет режим видимости тендера. Пустое значение означает Public
+// при создании и отсутствие изменений при редактировании.
+func validateVisibility(visibility string) error {
+	switch visibility {
+	case "", "Public", "InviteOnly":
+		return nil
+	default:
+		return errors.New("visibility must be Public or InviteOnly")
+	}
+}
+
+func (s *Service) CreateTenderInvitation(tenderId string, params model.CreateTenderInvitationParams,
+	body model.CreateTenderInvitationJSONBody) (*model.TenderInvitation, error) {
+	if body.OrganizationId == "" {
+		return nil, errors.New("organization id is required")
+	}
+
+	invitation, err := s.Store.CreateTenderInvitation(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (s *Service) GetTenderInvitations(tenderId string, params model.GetTenderInvitationsParams) ([]*model.TenderInvitation, error) {
+	invitations, err := s.Store.GetTenderInvitations(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+func (s *Service) DeleteTenderInvitation(tenderId, organizationId string, params model.DeleteTenderInvitationParams) (*model.TenderInvitation, error) {
+	invitation, err := s.Store.DeleteTenderInvitation(tenderId, organizationId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (s *Service) GetInvitedTenders(params model.GetInvitedTendersParams) ([]*model.Tender, error) {
+	tenders, err := s.Store.GetInvitedTenders(params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return tenders, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+func validateLot(body model.CreateTenderLotJSONBody) error {
+	if body.Name == "" {
+		return errors.New("lot name is required")
+	}
+	return validateMoney(body.Budget)
+}
+
+func (s *Service) CreateTenderLot(tenderId string, params model.CreateTenderLotParams, body model.CreateTenderLotJSONBody) (*model.TenderLot, error) {
+	if err := validateLot(body); err != nil {
+		return nil, err
+	}
+
+	lot, err := s.Store.CreateTenderLot(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return lot, nil
+}
+
+func (s *Service) GetTenderLots(tenderId string, params model.GetTenderLotsParams) ([]*model.TenderLot, error) {
+	lots, err := s.Store.GetTenderLots(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+func (s *Service) CancelTenderLot(tenderId, lotId string, params model.CancelTenderLotParams) (*model.TenderLot, error) {
+	lot, err := s.Store.CancelTenderLot(tenderId, lotId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return lot, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+func (s *Service) CreateCounterOffer(bidId string, params model.CreateCounterOfferParams,
+	body model.CreateCounterOfferJSONBody) (*model.BidNegotiationRound, error) {
+	if body.Price == nil && body.Terms == "" {
+		return nil, errors.New("counter-offer must change the price or the terms")
+	}
+	if err := validateMoney(body.Price); err != nil {
+		return nil, err
+	}
+
+	round, err := s.Store.CreateCounterOffer(bidId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return round, nil
+}
+
+func (s *Service) AcceptCounterOffer(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error) {
+	round, err := s.Store.AcceptCounterOffer(bidId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return round, nil
+}
+
+func (s *Service) DeclineCounterOffer(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error) {
+	round, err := s.Store.DeclineCounterOffer(bidId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return round, nil
+}
+
+func (s *Service) GetBidNegotiation(bidId string, params model.GetBidNegotiationParams) ([]*model.BidNegotiationRound, error) {
+	rounds, err := s.Store.GetBidNegotiation(bidId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return rounds, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"slices"
+)
+
+// notificationKinds - виды уведомлений, которые пользователь может отключить в настройках.
+var notificationKinds = []string{
+	"BidSubmitted",
+	"BidWithdrawn",
+	"BidDecision",
+	"BidFeedback",
+	"BidCounterOffer",
+	"CounterOfferAccepted",
+	"CounterOfferDeclined",
+	"TenderStatusChanged",
+	"TenderCanceled",
+	"BafoOpened",
+	"TenderAwarded",
+	"AwardCanceled",
+	"WatchedTenderChanged",
+	"SavedSearchMatch",
+}
+
+func (s *Service) GetNotifications(params model.GetNotificationsParams) (*model.NotificationInbox, error) {
+	inbox, err := s.Store.GetNotifications(params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return inbox, nil
+}
+
+func (s *Service) MarkNotificationsRead(params model.MarkNotificationsReadParams,
+	body model.MarkNotificationsReadJSONBody) ([]*model.Notification, error) {
+	if !body.All && len(body.Ids) == 0 {
+		return nil, errors.New("notification ids or all are required")
+	}
+
+	notifications, err := s.Store.MarkNotificationsRead(params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// GetNotificationPreferences возвращает настройки по всем видам уведомлений, включая не измененные пользователем.
+func (s *Service) GetNotificationPreferences(params model.GetNotificationPreferencesParams) ([]*model.NotificationPreference, error) {
+	stored, err := s.Store.GetNotificationPreferences(params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	enabled := make(map[string]bool, len(stored))
+	for _, preference := range stored {
+		enabled[preference.Kind] = preference.Enabled
+	}
+
+	preferences := make([]*model.NotificationPreference, 0, len(notificationKinds))
+	for _, kind := range notificationKinds {
+		preference := &model.NotificationPreference{Kind: kind, Enabled: true}
+		if value, ok := enabled[kind]; ok {
+			preference.Enabled = value
+		}
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, nil
+}
+
+func (s *Service) SetNotificationPreferences(params model.SetNotificationPreferencesParams,
+	body model.SetNotificationPreferencesJSONBody) ([]*model.NotificationPreference, error) {
+	if len(body.Preferences) == 0 {
+		return nil, errors.New("no preferences to set")
+	}
+	for _, preference := range body.Preferences {
+		if !slices.Contains(notificationKinds, preference.Kind) {
+			return nil, fmt.Errorf("unknown notification kind %q", preference.Kind)
+		}
+	}
+
+	if _, err := s.Store.SetNotificationPreferences(params, body); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return s.GetNotificationPreferences(model.GetNotificationPreferencesParams{Username: params.Username})
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/instinctG/tender/internal/outbox"
+	"log"
+	"slices"
+	"time"
+)
+
+// outboxBatchSize - сколько событий реле передает приемникам в одной транзакции.
+const outboxBatchSize = 100
+
+// outboxEventData - поля данных событий тендеров и предложений, которые нужны приемникам сервиса.
+type outboxEventData struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Decision    string `json:"decision"`
+	LotId       string `json:"lotId"`
+	LotDecision string `json:"lotDecision"`
+	Reason      string `json:"reason"`
+	Feedback    string `json:"feedback"`
+	Rating      int32  `json:"rating"`
+}
+
+// notificationSink превращает события outbox в уведомления пользователей.
+type notificationSink struct {
+	store Store
+}
+
+func (notificationSink) Name() string {
+	return "notifications"
+}
+
+func (n notificationSink) Publish(_ context.Context, event *model.OutboxEvent) error {
+	var data outboxEventData
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return err
+	}
+
+	notification := model.NotificationEvent{TenderId: event.TenderId, BidId: event.BidId}
+	switch event.Type {
+	case "bid.submitted":
+		notification.Kind, notification.Audience = "BidSubmitted", "TenderResponsibles"
+		notification.Payload = map[string]string{"bidName": data.Name}
+	case "bid.withdrawn":
+		notification.Kind, notification.Audience = "BidWithdrawn", "TenderResponsibles"
+		notification.Payload = map[string]string{"bidName": data.Name, "reason": data.Reason}
+	case "bid.decided":
+		decision := data.Decision
+		if data.LotId != "" {
+			decision = data.LotDecision
+		}
+		notification.Kind, notification.Audience = "BidDecision", "BidAuthors"
+		notification.Payload = map[string]string{"bidName": data.Name, "decision": decision, "lotId": data.LotId}
+	case "bid.reviewed":
+		notification.Kind, notification.Audience = "BidFeedback", "BidAuthors"
+		notification.Payload = map[string]any{"bidName": data.Name, "feedback": data.Feedback, "rating": data.Rating}
+	case "tender.published", "tender.status_changed":
+		notification.Kind, notification.Audience = "TenderStatusChanged", "TenderBidders"
+		notification.Payload = map[string]string{"tenderName": data.Name, "status": data.Status}
+	default:
+		return nil
+	}
+
+	return n.store.Notify(notification)
+}
+
+// webhookSink ставит события outbox в очередь доставки вебхуков. Идентификатор доставки события - EventId,
+// поэтому повторная передача того же события не создает второй доставки.
+type webhookSink struct {
+	store Store
+}
+
+func (webhookSink) Name() string {
+	return "webhooks"
+}
+
+func (w webhookSink) Publish(_ context.Context, event *model.OutboxEvent) error {
+	if !slices.Contains(webhookEventTypes, event.Type) {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return w.store.EnqueueWebhookEvent(model.WebhookEvent{
+		Id:       event.EventId,
+		Type:     event.Type,
+		TenderId: event.TenderId,
+		BidId:    event.BidId,
+		Payload:  payload,
+	})
+}
+
+// publishOutboxEvent передает событие приемникам, которые еще не получили его, и отмечает успешные
+// в event.PublishedSinks. На первой ошибке передача прерывается до следующего прохода реле.
+func (s *Service) publishOutboxEvent(event *model.OutboxEvent) error {
+	ctx := context.Background()
+	for _, sink := range s.Sinks {
+		if slices.Contains(event.PublishedSinks, sink.Name()) {
+			continue
+		}
+		if err := sink.Publish(ctx, event); err != nil {
+			return fmt.Errorf("%s sink: %w", sink.Name(), err)
+		}
+		event.PublishedSinks = append(event.PublishedSinks, sink.Name())
+	}
+
+	return nil
+}
+
+// RelayOutbox передает приемникам все неопубликованные события outbox и возвращает их число.
+func (s *Service) RelayOutbox() (int, error) {
+	relayed := 0
+	for {
+		published, err := s.Store.RelayOutboxEvents(outboxBatchSize, outbox.MaxAttempts, s.publishOutboxEvent)
+		relayed += published
+		if err != nil || published < outboxBatchSize {
+			return relayed, err
+		}
+	}
+}
+
+// StartOutboxRelay периодически передает события outbox приемникам. Несколько реплик могут работать
+// одновременно: событие, взятое одним реле, остальные пропускают.
+func (s *Service) StartOutboxRelay(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := s.RelayOutbox(); err != nil {
+				log.Printf("failed to relay outbox events: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/instinctG/tender/internal/outbox"
+	"slices"
+	"testing"
+)
+
+// recordingSink запоминает полученные события и возвращает err, если он задан.
+type recordingSink struct {
+	name   string
+	err    error
+	events []string
+}
+
+func (r *recordingSink) Name() string {
+	return r.name
+}
+
+func (r *recordingSink) Publish(_ context.Context, event *model.OutboxEvent) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, event.EventId)
+	return nil
+}
+
+func TestPublishOutboxEventSkipsPublishedSinks(t *testing.T) {
+	notifications := &recordingSink{name: "notifications"}
+	webhooks := &recordingSink{name: "webhooks", err: errors.New("queue is unavailable")}
+	logSink := &recordingSink{name: "log"}
+	s := &Service{Sinks: []outbox.Sink{notifications, webhooks, logSink}}
+	event := &model.OutboxEvent{EventId: "event-1", Type: "bid.edited"}
+
+	if err := s.publishOutboxEvent(event); err == nil {
+		t.Fatal("sink error was not reported")
+	}
+	if !slices.Equal(event.PublishedSinks, []string{"notifications"}) || len(logSink.events) != 0 {
+		t.Fatalf("published sinks = %v, later sinks got %v", event.PublishedSinks, logSink.events)
+	}
+
+	webhooks.err = nil
+	if err := s.publishOutboxEvent(event); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(notifications.events) != 1 || len(webhooks.events) != 1 || len(logSink.events) != 1 {
+		t.Fatalf("deliveries after retry: notifications %v, webhooks %v, log %v",
+			notifications.events, webhooks.events, logSink.events)
+	}
+}
+
+// fakeWebhookQueue запоминает события, поставленные в очередь доставки вебхуков.
+type fakeWebhookQueue struct {
+	Store
+	events []model.WebhookEvent
+}
+
+func (f *fakeWebhookQueue) EnqueueWebhookEvent(event model.WebhookEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func TestWebhookSinkEnqueuesSubscribableEvents(t *testing.T) {
+	store := &fakeWebhookQueue{}
+	sink := webhookSink{store: store}
+
+	for _, eventType := range append(slices.Clone(webhookEventTypes), "question.answered") {
+		event := &model.OutboxEvent{EventId: eventType, Type: eventType, TenderId: "tender", BidId: "bid"}
+		if err := sink.Publish(context.Background(), event); err != nil {
+			t.Fatalf("publish %s: %v", eventType, err)
+		}
+	}
+
+	if len(store.events) != len(webhookEventTypes) {
+		t.Fatalf("enqueued %d events, want %d", len(store.events), len(webhookEventTypes))
+	}
+	for _, event := range store.events {
+		if event.Type == "question.answered" {
+			t.Fatal("event without a webhook subscription was enqueued")
+		}
+	}
+}
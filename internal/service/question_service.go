@@ -0,0 +1,47 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+func (s *Service) CreateTenderQuestion(tenderId string, params model.CreateTenderQuestionParams,
+	body model.CreateTenderQuestionJSONBody) (*model.TenderQuestion, error) {
+	if body.Question == "" {
+		return nil, errors.New("question is required")
+	}
+
+	question, err := s.Store.CreateTenderQuestion(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return question, nil
+}
+
+func (s *Service) AnswerTenderQuestion(tenderId, questionId string, params model.AnswerTenderQuestionParams,
+	body model.AnswerTenderQuestionJSONBody) (*model.TenderQuestion, error) {
+	if body.Answer == "" {
+		return nil, errors.New("answer is required")
+	}
+
+	question, err := s.Store.AnswerTenderQuestion(tenderId, questionId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return question, nil
+}
+
+func (s *Service) GetTenderQuestions(tenderId string, params model.GetTenderQuestionsParams) ([]*model.TenderQuestion, error) {
+	questions, err := s.Store.GetTenderQuestions(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return questions, nil
+}
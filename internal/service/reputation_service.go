@@ -0,0 +1,47 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+func (s *Service) GetOrganizationReputation(organizationId string, params model.GetOrganizationReputationParams) (*model.SupplierReputation, error) {
+	reputation, err := s.Store.GetOrganizationReputation(organizationId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return reputation, nil
+}
+
+func (s *Service) CancelTenderAward(awardId string, params model.CancelTenderAwardParams,
+	body model.CancelTenderAwardJSONBody) (*model.TenderAward, error) {
+	if body.Reason == "" || len([]rune(body.Reason)) > 1000 {
+		return nil, errors.New("cancel reason must be between 1 and 1000 characters")
+	}
+
+	award, err := s.Store.CancelTenderAward(awardId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return award, nil
+}
+
+func (s *Service) RecordAwardDelivery(awardId string, params model.RecordAwardDeliveryParams,
+	body model.RecordAwardDeliveryJSONBody) (*model.TenderAward, error) {
+	if body.OnTime == nil {
+		return nil, errors.New("onTime is required")
+	}
+
+	award, err := s.Store.RecordAwardDelivery(awardId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return award, nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"log"
+	"time"
+)
+
+func (s *Service) RevealTenderBids(tenderId string, params model.RevealTenderBidsParams) (*model.Tender, error) {
+	tender, err := s.Store.RevealTenderBids(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+// StartRevealWorker периодически вскрывает запечатанные тендеры с истекшим сроком подачи.
+// Чтения списка предложений дополнительно вскрывают тендер сами, так что интервал влияет
+// только на то, как быстро вскрытие попадет в журнал без обращений к тендеру.
+func (s *Service) StartRevealWorker(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := s.Store.RevealDueTenders(); err != nil {
+				log.Printf("failed to reveal sealed tenders: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"time"
+)
+
+func (s *Service) SetTenderShortlist(tenderId string, params model.SetTenderShortlistParams,
+	body model.SetTenderShortlistJSONBody) (*model.TenderShortlist, error) {
+	if len(body.BidIds) == 0 {
+		return nil, errors.New("shortlist must contain at least one bid")
+	}
+	seen := make(map[string]bool, len(body.BidIds))
+	for _, bidId := range body.BidIds {
+		if seen[bidId] {
+			return nil, errors.New("shortlist contains duplicate bids")
+		}
+		seen[bidId] = true
+	}
+
+	shortlist, err := s.Store.SetTenderShortlist(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return shortlist, nil
+}
+
+func (s *Service) GetTenderShortlist(tenderId string, params model.GetTenderShortlistParams) (*model.TenderShortlist, error) {
+	shortlist, err := s.Store.GetTenderShortlist(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return shortlist, nil
+}
+
+func (s *Service) OpenBafoRound(tenderId string, params model.OpenBafoRoundParams, body model.OpenBafoRoundJSONBody) (*model.Tender, error) {
+	if !body.Deadline.After(time.Now()) {
+		return nil, errors.New("best and final offer deadline must be in the future")
+	}
+
+	tender, err := s.Store.OpenBafoRound(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+func (s *Service) SubmitBafo(bidId string, params model.SubmitBafoParams, body model.SubmitBafoJSONBody) (*model.Bid, error) {
+	if body.Description == "" && body.Price == nil {
+		return nil, errors.New("best and final offer must change the price or the description")
+	}
+	if err := validateMoney(body.Price); err != nil {
+		return nil, err
+	}
+
+	bid, err := s.Store.SubmitBafo(bidId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+func (s *Service) GetBidVersions(bidId string, params model.GetBidVersionsParams) ([]*model.BidVersion, error) {
+	versions, err := s.Store.GetBidVersions(bidId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+// validateTemplateContent проверяет содержимое шаблона так же, как проверяются тендер, лоты и критерии при создании.
+func validateTemplateContent(content *model.TenderTemplateContent) error {
+	if content == nil {
+		return errors.New("template content or source tender is required")
+	}
+	if err := validateMoney(content.Budget); err != nil {
+		return err
+	}
+	if content.StrictBudget && content.Budget == nil {
+		return errors.New("strict budget requires a budget")
+	}
+	if err := validateVisibility(content.Visibility); err != nil {
+		return err
+	}
+	for _, lot := range content.Lots {
+		if err := validateLot(lot); err != nil {
+			return err
+		}
+	}
+	for _, criterion := range content.Criteria {
+		if err := validateCriterion(criterion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CloneTender копирует тендер с лотами и критериями, а затем - его вложения.
+func (s *Service) CloneTender(tenderId string, params model.CloneTenderParams) (*model.Tender, error) {
+	tender, err := s.Store.CloneTender(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	if err = s.copyTenderAttachments(tenderId, tender.Id, params.Username); err != nil {
+		fmt.Println(err)
+		return nil, fmt.Errorf("tender %s cloned without attachments: %w", tender.Id, err)
+	}
+
+	return tender, nil
+}
+
+// copyTenderAttachments копирует содержимое и метаданные вложений тендера в другой тендер.
+func (s *Service) copyTenderAttachments(sourceTenderId, tenderId, username string) error {
+	attachments, err := s.Store.GetAttachments("Tender", sourceTenderId, model.GetAttachmentsParams{Username: username})
+	if err != nil {
+		return err
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+	if s.Blobs == nil {
+		return errors.New("attachment storage is not configured")
+	}
+
+	ctx := context.Background()
+	for _, source := range attachments {
+		attachment := *source
+		attachment.ParentId = tenderId
+		attachment.StorageKey = attachmentKey("Tender", tenderId)
+
+		content, err := s.Blobs.Get(ctx, source.StorageKey)
+		if err != nil {
+			return err
+		}
+		err = s.Blobs.Put(ctx, attachment.StorageKey, content, attachment.Size, attachment.ContentType)
+		content.Close()
+		if err != nil {
+			return err
+		}
+
+		if _, err = s.Store.CreateAttachment(attachment, model.UploadAttachmentParams{Username: username}); err != nil {
+			if err := s.Blobs.Delete(ctx, attachment.StorageKey); err != nil {
+				fmt.Println(err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) CreateTenderTemplate(organizationId string, params model.CreateTenderTemplateParams,
+	body model.CreateTenderTemplateJSONBody) (*model.TenderTemplate, error) {
+	if body.Name == "" {
+		return nil, errors.New("template name is required")
+	}
+	if body.SourceTenderId == "" {
+		if err := validateTemplateContent(body.Tender); err != nil {
+			return nil, err
+		}
+	}
+
+	template, err := s.Store.CreateTenderTemplate(organizationId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *Service) GetTenderTemplates(organizationId string, params model.GetTenderTemplatesParams) ([]*model.TenderTemplate, error) {
+	templates, err := s.Store.GetTenderTemplates(organizationId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// InstantiateTenderTemplate создает тендер по шаблону, подставляя заданные в body поля вместо полей шаблона.
+func (s *Service) InstantiateTenderTemplate(templateId string, params model.InstantiateTenderTemplateParams,
+	body model.InstantiateTenderTemplateJSONBody) (*model.Tender, error) {
+	template, err := s.Store.GetTenderTemplate(templateId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	content := template.Tender
+	tender := model.CreateTenderJSONBody{
+		CreatorUsername:    params.Username,
+		Description:        content.Description,
+		Name:               content.Name,
+		OrganizationId:     template.OrganizationId,
+		ServiceType:        content.ServiceType,
+		Budget:             content.Budget,
+		StrictBudget:       content.StrictBudget,
+		Sealed:             content.Sealed,
+		SubmissionDeadline: body.SubmissionDeadline,
+		Visibility:         content.Visibility,
+	}
+	if body.Name != "" {
+		tender.Name = body.Name
+	}
+	if body.Description != "" {
+		tender.Description = body.Description
+	}
+	if body.ServiceType != "" {
+		tender.ServiceType = body.ServiceType
+	}
+	if body.Budget != nil {
+		tender.Budget = body.Budget
+	}
+	if body.StrictBudget != nil {
+		tender.StrictBudget = *body.StrictBudget
+	}
+	if body.Sealed != nil {
+		tender.Sealed = *body.Sealed
+	}
+	if body.Visibility != "" {
+		tender.Visibility = body.Visibility
+	}
+
+	if err = validateCreateTender(tender); err != nil {
+		return nil, err
+	}
+
+	created, err := s.Store.CreateTenderWithContent(tender, content.Lots, content.Criteria)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return created, nil
+}
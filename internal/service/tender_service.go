@@ -1,37 +1,175 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/instinctG/tender/internal/model"
+	"github.com/instinctG/tender/internal/outbox"
+	"github.com/instinctG/tender/internal/storage"
+	"github.com/instinctG/tender/internal/webhook"
+	"regexp"
+	"strings"
+	"time"
 )
 
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validateMoney проверяет, что сумма неотрицательна, а валюта задана кодом ISO 4217.
+func validateMoney(m *model.Money) error {
+	if m == nil {
+		return nil
+	}
+	if m.Amount < 0 {
+		return errors.New("amount must not be negative")
+	}
+	if !currencyCode.MatchString(m.Currency) {
+		return errors.New("currency must be an ISO 4217 code")
+	}
+	return nil
+}
+
 type Store interface {
 	GetUserBids(params model.GetUserBidsParams) ([]*model.Bid, error)
 	CreateBid(params model.CreateBidJSONBody) (*model.Bid, error)
 	EditBid(bidId string, params model.EditBidParams, body model.EditBidJSONBody) (*model.Bid, error)
-	SubmitBidFeedback(bidId string, params model.SubmitBidFeedbackParams) *model.Bid
+	SubmitBidFeedback(bidId string, params model.SubmitBidFeedbackParams) (*model.Bid, error)
 	RollbackBid(bidId string, version int32, params model.RollbackBidParams) *model.Bid
 	GetBidStatus(bidId string, params model.GetBidStatusParams) (string, error)
 	UpdateBidStatus(bidId string, params model.UpdateBidStatusParams) (*model.Bid, error)
 	SubmitBidDecision(bidId string, params model.SubmitBidDecisionParams) (*model.Bid, error)
 	GetBidsForTender(tenderId string, params model.GetBidsForTenderParams) ([]*model.Bid, error)
-	GetBidReviews(tenderId string, params model.GetBidReviewsParams) []*model.BidReview
+	GetBidReviews(tenderId string, params model.GetBidReviewsParams) ([]*model.BidReview, error)
 	GetTenders(params model.GetTendersParams) ([]*model.Tender, error) //todo : доделать
+	GetTender(tenderId string, params model.GetTenderParams) (*model.Tender, error)
 	GetUserTenders(params model.GetUserTendersParams) ([]*model.Tender, error)
 	CreateTender(params model.CreateTenderJSONBody) (*model.Tender, error)
 	EditTender(tenderId string, par model.EditTenderParams, params model.EditTenderJSONBody) (*model.Tender, error)
 	RollbackTender(tenderId string, version int32, params model.RollbackTenderParams) (*model.Tender, error)
 	GetTenderStatus(tenderId string, params model.GetTenderStatusParams) (string, error)
 	UpdateTenderStatus(tenderId string, params model.UpdateTenderStatusParams) (*model.Tender, error)
+	CancelTender(tenderId string, params model.CancelTenderParams, body model.CancelTenderJSONBody) (*model.Tender, error)
+	CreateTenderLot(tenderId string, params model.CreateTenderLotParams, body model.CreateTenderLotJSONBody) (*model.TenderLot, error)
+	GetTenderLots(tenderId string, params model.GetTenderLotsParams) ([]*model.TenderLot, error)
+	CancelTenderLot(tenderId, lotId string, params model.CancelTenderLotParams) (*model.TenderLot, error)
+	RevealTenderBids(tenderId string, params model.RevealTenderBidsParams) (*model.Tender, error)
+	RevealDueTenders() (int, error)
+	ConfigureAuction(tenderId string, params model.ConfigureAuctionParams, body model.ConfigureAuctionJSONBody) (*model.Auction, error)
+	GetAuction(tenderId string) (*model.Auction, error)
+	PlaceAuctionOffer(tenderId string, params model.PlaceAuctionOfferParams, body model.PlaceAuctionOfferJSONBody,
+		apply func(auction *model.Auction, amount int64, now time.Time) error) (*model.Auction, error)
+	GetAuctionResults(tenderId string, params model.GetAuctionResultsParams) ([]*model.AuctionResult, error)
+	CreateEvaluationCriterion(tenderId string, params model.CreateEvaluationCriterionParams,
+		body model.CreateEvaluationCriterionJSONBody) (*model.EvaluationCriterion, error)
+	GetEvaluationCriteria(tenderId string, params model.GetEvaluationCriteriaParams) ([]*model.EvaluationCriterion, error)
+	SubmitBidScores(bidId string, params model.SubmitBidScoresParams, body []model.BidScoreJSONBody) ([]*model.BidScore, error)
+	GetTenderEvaluation(tenderId string, params model.GetTenderEvaluationParams) (*model.TenderEvaluation, error)
+	CreateTenderQuestion(tenderId string, params model.CreateTenderQuestionParams,
+		body model.CreateTenderQuestionJSONBody) (*model.TenderQuestion, error)
+	AnswerTenderQuestion(tenderId, questionId string, params model.AnswerTenderQuestionParams,
+		body model.AnswerTenderQuestionJSONBody) (*model.TenderQuestion, error)
+	GetTenderQuestions(tenderId string, params model.GetTenderQuestionsParams) ([]*model.TenderQuestion, error)
+	CheckAttachmentUpload(parentType, parentId string, params model.UploadAttachmentParams) error
+	CreateAttachment(attachment model.Attachment, params model.UploadAttachmentParams) (*model.Attachment, error)
+	GetAttachments(parentType, parentId string, params model.GetAttachmentsParams) ([]*model.Attachment, error)
+	GetAttachment(attachmentId string, params model.GetAttachmentsParams) (*model.Attachment, error)
+	CreateTenderInvitation(tenderId string, params model.CreateTenderInvitationParams,
+		body model.CreateTenderInvitationJSONBody) (*model.TenderInvitation, error)
+	GetTenderInvitations(tenderId string, params model.GetTenderInvitationsParams) ([]*model.TenderInvitation, error)
+	DeleteTenderInvitation(tenderId, organizationId string, params model.DeleteTenderInvitationParams) (*model.TenderInvitation, error)
+	GetInvitedTenders(params model.GetInvitedTendersParams) ([]*model.Tender, error)
+	CloneTender(tenderId string, params model.CloneTenderParams) (*model.Tender, error)
+	CreateTenderTemplate(organizationId string, params model.CreateTenderTemplateParams,
+		body model.CreateTenderTemplateJSONBody) (*model.TenderTemplate, error)
+	GetTenderTemplates(organizationId string, params model.GetTenderTemplatesParams) ([]*model.TenderTemplate, error)
+	GetTenderTemplate(templateId string, params model.InstantiateTenderTemplateParams) (*model.TenderTemplate, error)
+	CreateTenderWithContent(params model.CreateTenderJSONBody, lots []model.CreateTenderLotJSONBody,
+		criteria []model.CreateEvaluationCriterionJSONBody) (*model.Tender, error)
+	WithdrawBid(bidId string, params model.WithdrawBidParams, body model.WithdrawBidJSONBody) (*model.Bid, error)
+	ResubmitBid(bidId string, params model.ResubmitBidParams) (*model.Bid, error)
+	GetBidWithdrawals(tenderId string, params model.GetBidWithdrawalsParams) ([]*model.BidWithdrawal, error)
+	BlockOrganization(organizationId string, params model.BlockOrganizationParams,
+		body model.BlockOrganizationJSONBody) (*model.BlockedOrganization, error)
+	GetOrganizationBlocklist(organizationId string, params model.GetOrganizationBlocklistParams) ([]*model.BlockedOrganization, error)
+	UnblockOrganization(organizationId, blockedOrganizationId string,
+		params model.UnblockOrganizationParams) (*model.BlockedOrganization, error)
+	CreateCounterOffer(bidId string, params model.CreateCounterOfferParams,
+		body model.CreateCounterOfferJSONBody) (*model.BidNegotiationRound, error)
+	AcceptCounterOffer(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error)
+	DeclineCounterOffer(bidId string, params model.RespondCounterOfferParams) (*model.BidNegotiationRound, error)
+	GetBidNegotiation(bidId string, params model.GetBidNegotiationParams) ([]*model.BidNegotiationRound, error)
+	SetTenderShortlist(tenderId string, params model.SetTenderShortlistParams,
+		body model.SetTenderShortlistJSONBody) (*model.TenderShortlist, error)
+	GetTenderShortlist(tenderId string, params model.GetTenderShortlistParams) (*model.TenderShortlist, error)
+	OpenBafoRound(tenderId string, params model.OpenBafoRoundParams, body model.OpenBafoRoundJSONBody) (*model.Tender, error)
+	SubmitBafo(bidId string, params model.SubmitBafoParams, body model.SubmitBafoJSONBody) (*model.Bid, error)
+	GetBidVersions(bidId string, params model.GetBidVersionsParams) ([]*model.BidVersion, error)
+	GetOrganizationAwards(organizationId string, params model.GetOrganizationAwardsParams) ([]*model.TenderAward, error)
+	GetTenderAward(awardId string, params model.GetTenderAwardParams) (*model.TenderAward, error)
+	SetTenderAwardSet(tenderId string, params model.SetTenderAwardSetParams,
+		body model.SetTenderAwardSetJSONBody) (*model.TenderAwardSet, error)
+	GetTenderAwardSet(tenderId string, params model.GetTenderAwardSetParams) (*model.TenderAwardSet, error)
+	FinalizeTenderAwardSet(tenderId string, params model.FinalizeTenderAwardSetParams) (*model.TenderAwardSet, error)
+	GetOrganizationReputation(organizationId string, params model.GetOrganizationReputationParams) (*model.SupplierReputation, error)
+	CancelTenderAward(awardId string, params model.CancelTenderAwardParams,
+		body model.CancelTenderAwardJSONBody) (*model.TenderAward, error)
+	RecordAwardDelivery(awardId string, params model.RecordAwardDeliveryParams,
+		body model.RecordAwardDeliveryJSONBody) (*model.TenderAward, error)
+	WatchTender(tenderId string, params model.WatchTenderParams) (*model.Tender, error)
+	UnwatchTender(tenderId string, params model.UnwatchTenderParams) (*model.Tender, error)
+	GetWatchedTenders(params model.GetWatchedTendersParams) ([]*model.Tender, error)
+	CreateSavedSearch(params model.CreateSavedSearchParams) (*model.SavedSearch, error)
+	GetSavedSearches(params model.GetSavedSearchesParams) ([]*model.SavedSearch, error)
+	DeleteSavedSearch(searchId string, params model.DeleteSavedSearchParams) (*model.SavedSearch, error)
+	Notify(event model.NotificationEvent) error
+	GetNotifications(params model.GetNotificationsParams) (*model.NotificationInbox, error)
+	MarkNotificationsRead(params model.MarkNotificationsReadParams,
+		body model.MarkNotificationsReadJSONBody) ([]*model.Notification, error)
+	GetNotificationPreferences(params model.GetNotificationPreferencesParams) ([]*model.NotificationPreference, error)
+	SetNotificationPreferences(params model.SetNotificationPreferencesParams,
+		body model.SetNotificationPreferencesJSONBody) ([]*model.NotificationPreference, error)
+	CreateWebhook(organizationId string, params model.CreateWebhookParams,
+		body model.CreateWebhookJSONBody, secret string) (*model.Webhook, error)
+	GetWebhooks(organizationId string, params model.GetWebhooksParams) ([]*model.Webhook, error)
+	DeleteWebhook(organizationId, webhookId string, params model.DeleteWebhookParams) (*model.Webhook, error)
+	GetWebhookDeliveries(organizationId, webhookId string,
+		params model.GetWebhookDeliveriesParams) ([]*model.WebhookDelivery, error)
+	GetWebhookDeliveryAttempts(deliveryId string,
+		params model.GetWebhookDeliveryAttemptsParams) ([]*model.WebhookDeliveryAttempt, error)
+	ReplayWebhookDelivery(deliveryId string, params model.ReplayWebhookDeliveryParams) (*model.WebhookDelivery, error)
+	EnqueueWebhookEvent(event model.WebhookEvent) error
+	ClaimWebhookDeliveries(limit int) ([]*model.PendingWebhookDelivery, error)
+	RecordWebhookAttempt(result model.WebhookAttemptResult) error
+	RelayOutboxEvents(limit, maxAttempts int, publish func(event *model.OutboxEvent) error) (int, error)
+	ListenOutboxEvents(ctx context.Context, handle func(event *model.OutboxEvent)) error
+	GetTenderEvents(tenderId string, params model.GetTenderEventsParams) ([]*model.OutboxEvent, error)
+	GetAuditLog(params model.GetAuditLogParams) ([]*model.AuditEntry, error)
 }
 
 type Service struct {
 	Store Store
+	Blobs storage.BlobStorage
+	// Webhooks отправляет доставки вебхуков. Если он nil, события копятся в очереди без отправки.
+	Webhooks *webhook.Sender
+	// Sinks - приемники событий outbox, по умолчанию уведомления и вебхуки.
+	Sinks []outbox.Sink
+	// Events - шина событий outbox внутри процесса. В нее попадают события, опубликованные любой
+	// репликой, если запущен StartEventListener.
+	Events *outbox.Bus
+	// MaxAttachmentSize - предельный размер вложения в байтах, по умолчанию DefaultMaxAttachmentSize.
+	MaxAttachmentSize int64
 }
 
 // NewService создает новый экземпляр Service.
-func NewService(store Store) *Service {
-	return &Service{Store: store}
+func NewService(store Store, blobs storage.BlobStorage) *Service {
+	return &Service{
+		Store:             store,
+		Blobs:             blobs,
+		Webhooks:          webhook.NewSender(),
+		Sinks:             []outbox.Sink{notificationSink{store: store}, webhookSink{store: store}},
+		Events:            outbox.NewBus(),
+		MaxAttachmentSize: DefaultMaxAttachmentSize,
+	}
 }
 
 func (s *Service) GetUserBids(params model.GetUserBidsParams) ([]*model.Bid, error) {
@@ -45,6 +183,10 @@ func (s *Service) GetUserBids(params model.GetUserBidsParams) ([]*model.Bid, err
 }
 
 func (s *Service) CreateBid(params model.CreateBidJSONBody) (*model.Bid, error) {
+	if err := validateMoney(params.Price); err != nil {
+		return nil, err
+	}
+
 	bid, err := s.Store.CreateBid(params)
 	if err != nil {
 		fmt.Println(err)
@@ -55,6 +197,10 @@ func (s *Service) CreateBid(params model.CreateBidJSONBody) (*model.Bid, error)
 }
 
 func (s *Service) EditBid(bidId string, params model.EditBidParams, body model.EditBidJSONBody) (*model.Bid, error) {
+	if err := validateMoney(body.Price); err != nil {
+		return nil, err
+	}
+
 	editedBid, err := s.Store.EditBid(bidId, params, body)
 	if err != nil {
 		fmt.Println(err)
@@ -64,8 +210,21 @@ func (s *Service) EditBid(bidId string, params model.EditBidParams, body model.E
 	return editedBid, nil
 }
 
-func (s *Service) SubmitBidFeedback(bidId string, params model.SubmitBidFeedbackParams) *model.Bid {
-	return s.Store.SubmitBidFeedback(bidId, params)
+func (s *Service) SubmitBidFeedback(bidId string, params model.SubmitBidFeedbackParams) (*model.Bid, error) {
+	if params.BidFeedback == "" || len([]rune(params.BidFeedback)) > 1000 {
+		return nil, errors.New("feedback must be between 1 and 1000 characters")
+	}
+	if params.Rating != nil && (*params.Rating < 1 || *params.Rating > 5) {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+
+	bid, err := s.Store.SubmitBidFeedback(bidId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return bid, nil
 }
 
 func (s *Service) RollbackBid(bidId string, version int32, params model.RollbackBidParams) *model.Bid {
@@ -82,6 +241,13 @@ func (s *Service) GetBidStatus(bidId string, params model.GetBidStatusParams) (s
 }
 
 func (s *Service) UpdateBidStatus(bidId string, params model.UpdateBidStatusParams) (*model.Bid, error) {
+	if params.Status == "Void" {
+		return nil, errors.New("bids become void only when their tender is canceled")
+	}
+	if params.Status == "Canceled" {
+		return s.WithdrawBid(bidId, model.WithdrawBidParams{Username: params.Username}, model.WithdrawBidJSONBody{})
+	}
+
 	bid, err := s.Store.UpdateBidStatus(bidId, params)
 	if err != nil {
 		fmt.Println(err)
@@ -92,7 +258,20 @@ func (s *Service) UpdateBidStatus(bidId string, params model.UpdateBidStatusPara
 }
 
 func (s *Service) SubmitBidDecision(bidId string, params model.SubmitBidDecisionParams) (*model.Bid, error) {
-	return s.Store.SubmitBidDecision(bidId, params)
+	if params.Decision != "Accepted" && params.Decision != "Rejected" {
+		return nil, errors.New("decision must be Accepted or Rejected")
+	}
+	if params.Decision == "Accepted" && params.Rationale == "" {
+		return nil, errors.New("award rationale is required")
+	}
+
+	bid, err := s.Store.SubmitBidDecision(bidId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return bid, nil
 }
 
 func (s *Service) GetBidsForTender(tenderId string, params model.GetBidsForTenderParams) ([]*model.Bid, error) {
@@ -105,11 +284,21 @@ func (s *Service) GetBidsForTender(tenderId string, params model.GetBidsForTende
 	return bids, nil
 }
 
-func (s *Service) GetBidReviews(tenderId string, params model.GetBidReviewsParams) []*model.BidReview {
-	return s.Store.GetBidReviews(tenderId, params)
+func (s *Service) GetBidReviews(tenderId string, params model.GetBidReviewsParams) ([]*model.BidReview, error) {
+	reviews, err := s.Store.GetBidReviews(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return reviews, nil
 }
 
 func (s *Service) GetTenders(params model.GetTendersParams) ([]*model.Tender, error) {
+	if len([]rune(params.Query)) > 200 {
+		return nil, errors.New("search query is too long")
+	}
+
 	tenders, err := s.Store.GetTenders(params)
 	if err != nil {
 		fmt.Println(err)
@@ -119,6 +308,16 @@ func (s *Service) GetTenders(params model.GetTendersParams) ([]*model.Tender, er
 	return tenders, nil
 }
 
+func (s *Service) GetTender(tenderId string, params model.GetTenderParams) (*model.Tender, error) {
+	tender, err := s.Store.GetTender(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return tender, nil
+}
+
 func (s *Service) GetUserTenders(params model.GetUserTendersParams) ([]*model.Tender, error) {
 	tenders, err := s.Store.GetUserTenders(params)
 	if err != nil {
@@ -129,7 +328,25 @@ func (s *Service) GetUserTenders(params model.GetUserTendersParams) ([]*model.Te
 	return tenders, nil
 }
 
+// validateCreateTender проверяет параметры нового тендера.
+func validateCreateTender(params model.CreateTenderJSONBody) error {
+	if err := validateMoney(params.Budget); err != nil {
+		return err
+	}
+	if params.StrictBudget && params.Budget == nil {
+		return errors.New("strict budget requires a budget")
+	}
+	if params.SubmissionDeadline != nil && !params.SubmissionDeadline.After(time.Now()) {
+		return errors.New("submission deadline must be in the future")
+	}
+	return validateVisibility(params.Visibility)
+}
+
 func (s *Service) CreateTender(params model.CreateTenderJSONBody) (*model.Tender, error) {
+	if err := validateCreateTender(params); err != nil {
+		return nil, err
+	}
+
 	tender, err := s.Store.CreateTender(params)
 	if err != nil {
 		fmt.Println(err)
@@ -140,6 +357,13 @@ func (s *Service) CreateTender(params model.CreateTenderJSONBody) (*model.Tender
 }
 
 func (s *Service) EditTender(tenderId string, par model.EditTenderParams, params model.EditTenderJSONBody) (*model.Tender, error) {
+	if err := validateMoney(params.Budget); err != nil {
+		return nil, err
+	}
+	if err := validateVisibility(params.Visibility); err != nil {
+		return nil, err
+	}
+
 	editedTender, err := s.Store.EditTender(tenderId, par, params)
 	if err != nil {
 		fmt.Println(err)
@@ -153,6 +377,21 @@ func (s *Service) RollbackTender(tenderId string, version int32, params model.Ro
 	return s.Store.RollbackTender(tenderId, version, params)
 }
 
+func (s *Service) CancelTender(tenderId string, params model.CancelTenderParams, body model.CancelTenderJSONBody) (*model.Tender, error) {
+	body.Reason = strings.TrimSpace(body.Reason)
+	if body.Reason == "" {
+		return nil, errors.New("cancellation reason is required")
+	}
+
+	tender, err := s.Store.CancelTender(tenderId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return tender, nil
+}
+
 func (s *Service) GetTenderStatus(tenderId string, params model.GetTenderStatusParams) (string, error) {
 	status, err := s.Store.GetTenderStatus(tenderId, params)
 	if err != nil {
@@ -164,10 +403,15 @@ func (s *Service) GetTenderStatus(tenderId string, params model.GetTenderStatusP
 }
 
 func (s *Service) UpdateTenderStatus(tenderId string, params model.UpdateTenderStatusParams) (*model.Tender, error) {
+	if params.Status == "Canceled" {
+		return nil, errors.New("tenders are canceled with a reason via the cancel endpoint")
+	}
+
 	tender, err := s.Store.UpdateTenderStatus(tenderId, params)
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
 	}
+
 	return tender, nil
 }
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"github.com/instinctG/tender/internal/model"
+	"testing"
+)
+
+func TestSubmitBidFeedbackRejectsRatingOutOfRange(t *testing.T) {
+	s := &Service{}
+	for _, rating := range []int32{0, -1, 6} {
+		rating := rating
+		_, err := s.SubmitBidFeedback("bid", model.SubmitBidFeedbackParams{
+			BidFeedback: "Feedback",
+			Username:    "creator",
+			Rating:      &rating,
+		})
+		if err == nil {
+			t.Fatalf("rating %d was accepted", rating)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+)
+
+func (s *Service) WatchTender(tenderId string, params model.WatchTenderParams) (*model.Tender, error) {
+	tender, err := s.Store.WatchTender(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+func (s *Service) UnwatchTender(tenderId string, params model.UnwatchTenderParams) (*model.Tender, error) {
+	tender, err := s.Store.UnwatchTender(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+func (s *Service) GetWatchedTenders(params model.GetWatchedTendersParams) ([]*model.Tender, error) {
+	tenders, err := s.Store.GetWatchedTenders(params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return tenders, nil
+}
+
+// CreateSavedSearch сохраняет поиск с теми же ограничениями, что и GET /api/tenders.
+// Поиск без единого условия совпадал бы с каждым тендером, поэтому он не принимается.
+func (s *Service) CreateSavedSearch(params model.CreateSavedSearchParams) (*model.SavedSearch, error) {
+	if params.Name == "" || len([]rune(params.Name)) > 100 {
+		return nil, errors.New("search name must be between 1 and 100 characters")
+	}
+
+	search := params.Search
+	if len([]rune(search.Query)) > 200 {
+		return nil, errors.New("search query is too long")
+	}
+	for _, serviceType := range search.ServiceType {
+		switch serviceType {
+		case "Construction", "Delivery", "Manufacture":
+		default:
+			return nil, fmt.Errorf("unknown service type %q", serviceType)
+		}
+	}
+	filter := search.Filter
+	if len(search.ServiceType) == 0 && search.Query == "" && filter.OrganizationId == "" && filter.CreatedFrom == nil &&
+		filter.CreatedTo == nil && filter.AmountMin == nil && filter.AmountMax == nil && filter.Currency == "" {
+		return nil, errors.New("saved search requires at least one condition")
+	}
+
+	created, err := s.Store.CreateSavedSearch(params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) GetSavedSearches(params model.GetSavedSearchesParams) ([]*model.SavedSearch, error) {
+	searches, err := s.Store.GetSavedSearches(params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return searches, nil
+}
+
+func (s *Service) DeleteSavedSearch(searchId string, params model.DeleteSavedSearchParams) (*model.SavedSearch, error) {
+	search, err := s.Store.DeleteSavedSearch(searchId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return search, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/instinctG/tender/internal/webhook"
+	"log"
+	"slices"
+	"time"
+)
+
+// webhookEventTypes - события жизненного цикла тендеров и предложений, на которые можно подписаться.
+var webhookEventTypes = []string{
+	"tender.created",
+	"tender.edited",
+	"tender.published",
+	"tender.status_changed",
+	"tender.canceled",
+	"tender.revealed",
+	"tender.bafo_opened",
+	"tender.lot_created",
+	"tender.lot_canceled",
+	"bid.created",
+	"bid.edited",
+	"bid.submitted",
+	"bid.status_changed",
+	"bid.withdrawn",
+	"bid.decided",
+	"bid.reviewed",
+	"bid.award_canceled",
+	"bid.award_delivered",
+}
+
+// webhookBatchSize - сколько доставок воркер берет в работу за один запрос к базе.
+const webhookBatchSize = 20
+
+func (s *Service) CreateWebhook(organizationId string, params model.CreateWebhookParams,
+	body model.CreateWebhookJSONBody) (*model.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := webhook.CheckURL(ctx, body.URL); err != nil {
+		return nil, err
+	}
+	for _, eventType := range body.EventTypes {
+		if !slices.Contains(webhookEventTypes, eventType) {
+			return nil, fmt.Errorf("unknown webhook event type %q", eventType)
+		}
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.Store.CreateWebhook(organizationId, params, body, secret)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) GetWebhooks(organizationId string, params model.GetWebhooksParams) ([]*model.Webhook, error) {
+	webhooks, err := s.Store.GetWebhooks(organizationId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (s *Service) DeleteWebhook(organizationId, webhookId string, params model.DeleteWebhookParams) (*model.Webhook, error) {
+	deleted, err := s.Store.DeleteWebhook(organizationId, webhookId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+func (s *Service) GetWebhookDeliveries(organizationId, webhookId string,
+	params model.GetWebhookDeliveriesParams) ([]*model.WebhookDelivery, error) {
+	if params.Status != "" && params.Status != "Pending" && params.Status != "Delivered" && params.Status != "Dead" {
+		return nil, errors.New("status must be Pending, Delivered or Dead")
+	}
+
+	deliveries, err := s.Store.GetWebhookDeliveries(organizationId, webhookId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *Service) GetWebhookDeliveryAttempts(deliveryId string,
+	params model.GetWebhookDeliveryAttemptsParams) ([]*model.WebhookDeliveryAttempt, error) {
+	attempts, err := s.Store.GetWebhookDeliveryAttempts(deliveryId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (s *Service) ReplayWebhookDelivery(deliveryId string, params model.ReplayWebhookDeliveryParams) (*model.WebhookDelivery, error) {
+	delivery, err := s.Store.ReplayWebhookDelivery(deliveryId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// DeliverWebhooks отправляет все доставки, срок которых наступил, и возвращает число попыток.
+// Неудачная доставка откладывается с экспоненциально растущей паузой, а после webhook.MaxAttempts
+// попыток переходит в Dead и ждет ручного повтора.
+func (s *Service) DeliverWebhooks(ctx context.Context) (int, error) {
+	if s.Webhooks == nil {
+		return 0, errors.New("webhook sender is not configured")
+	}
+
+	attempted := 0
+	for {
+		deliveries, err := s.Store.ClaimWebhookDeliveries(webhookBatchSize)
+		if err != nil {
+			return attempted, err
+		}
+
+		for _, delivery := range deliveries {
+			started := time.Now()
+			statusCode, sendErr := s.Webhooks.Send(ctx, webhook.Delivery{
+				Id:        delivery.Id,
+				EventType: delivery.EventType,
+				URL:       delivery.URL,
+				Secret:    delivery.Secret,
+				Payload:   delivery.Payload,
+			})
+
+			result := model.WebhookAttemptResult{
+				DeliveryId:    delivery.Id,
+				Attempt:       delivery.Attempts + 1,
+				Status:        "Delivered",
+				StatusCode:    statusCode,
+				Duration:      time.Since(started),
+				NextAttemptAt: time.Now(),
+			}
+			if sendErr != nil {
+				result.Error = sendErr.Error()
+				result.Status = "Dead"
+				if result.Attempt < webhook.MaxAttempts {
+					result.Status = "Pending"
+					result.NextAttemptAt = time.Now().Add(webhook.Backoff(int(result.Attempt)))
+				}
+			}
+
+			if err = s.Store.RecordWebhookAttempt(result); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+
+		if len(deliveries) < webhookBatchSize {
+			return attempted, nil
+		}
+	}
+}
+
+// StartWebhookWorker периодически отправляет доставки вебхуков. Несколько реплик могут работать
+// одновременно: каждая доставка берется в работу только одним воркером.
+func (s *Service) StartWebhookWorker(interval time.Duration) {
+	if s.Webhooks == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := s.DeliverWebhooks(context.Background()); err != nil {
+				log.Printf("failed to deliver webhooks: %v", err)
+			}
+		}
+	}()
+}
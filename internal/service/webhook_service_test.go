@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"github.com/instinctG/tender/internal/model"
+	"github.com/instinctG/tender/internal/webhook"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeWebhookStore хранит одну доставку в памяти. Остальные методы Store в этих тестах не вызываются.
+type fakeWebhookStore struct {
+	Store
+	delivery model.PendingWebhookDelivery
+	status   string
+	results  []model.WebhookAttemptResult
+}
+
+func (f *fakeWebhookStore) ClaimWebhookDeliveries(limit int) ([]*model.PendingWebhookDelivery, error) {
+	if f.status != "Pending" {
+		return nil, nil
+	}
+	delivery := f.delivery
+	return []*model.PendingWebhookDelivery{&delivery}, nil
+}
+
+func (f *fakeWebhookStore) RecordWebhookAttempt(result model.WebhookAttemptResult) error {
+	f.results = append(f.results, result)
+	f.status = result.Status
+	f.delivery.Attempts = result.Attempt
+	return nil
+}
+
+func (f *fakeWebhookStore) ReplayWebhookDelivery(deliveryId string,
+	params model.ReplayWebhookDeliveryParams) (*model.WebhookDelivery, error) {
+	f.status = "Pending"
+	f.delivery.Attempts = 0
+	return &model.WebhookDelivery{Id: deliveryId, Status: f.status}, nil
+}
+
+// newWebhookTest возвращает сервис с доставкой на получателя, который проверяет подпись
+// и отвечает кодами из statuses по очереди, а затем 200.
+func newWebhookTest(t *testing.T, statuses ...int) (*Service, *fakeWebhookStore, *int) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		err := webhook.Verify("secret", r.Header.Get(webhook.HeaderTimestamp), r.Header.Get(webhook.HeaderSignature),
+			[]byte(`{"id":"event-1"}`), time.Minute, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if len(statuses) > 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	store := &fakeWebhookStore{
+		delivery: model.PendingWebhookDelivery{
+			Id:        "delivery-1",
+			EventType: "bid.submitted",
+			URL:       server.URL,
+			Secret:    "secret",
+			Payload:   []byte(`{"id":"event-1"}`),
+		},
+		status: "Pending",
+	}
+	sender := &webhook.Sender{Client: server.Client(), Now: time.Now}
+	return &Service{Store: store, Webhooks: sender}, store, &requests
+}
+
+func TestDeliverWebhooksRetriesServerErrorsWithBackoff(t *testing.T) {
+	s, store, requests := newWebhookTest(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+
+	for i := 0; i < 3; i++ {
+		started := time.Now()
+		if _, err := s.DeliverWebhooks(context.Background()); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+		result := store.results[i]
+		if i < 2 {
+			delay := webhook.Backoff(i + 1)
+			if result.Status != "Pending" || result.NextAttemptAt.Before(started.Add(delay)) ||
+				result.NextAttemptAt.After(time.Now().Add(delay)) {
+				t.Fatalf("attempt %d = %+v, want a retry in %s", i+1, result, delay)
+			}
+		} else if result.Status != "Delivered" || result.StatusCode != http.StatusOK {
+			t.Fatalf("attempt %d = %+v, want delivered", i+1, result)
+		}
+		if result.Attempt != int32(i+1) {
+			t.Fatalf("attempt number = %d, want %d", result.Attempt, i+1)
+		}
+	}
+	if *requests != 3 {
+		t.Fatalf("receiver got %d requests, want 3", *requests)
+	}
+}
+
+func TestDeliverWebhooksGivesUpAndReplays(t *testing.T) {
+	s, store, requests := newWebhookTest(t, http.StatusBadGateway)
+	store.delivery.Attempts = webhook.MaxAttempts - 1
+
+	if _, err := s.DeliverWebhooks(context.Background()); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if store.status != "Dead" {
+		t.Fatalf("status after the last attempt = %s, want Dead", store.status)
+	}
+	if attempted, _ := s.DeliverWebhooks(context.Background()); attempted != 0 {
+		t.Fatalf("dead delivery was attempted %d more times", attempted)
+	}
+
+	if _, err := s.ReplayWebhookDelivery("delivery-1", model.ReplayWebhookDeliveryParams{Username: "creator"}); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if _, err := s.DeliverWebhooks(context.Background()); err != nil {
+		t.Fatalf("deliver after replay: %v", err)
+	}
+	last := store.results[len(store.results)-1]
+	if last.Status != "Delivered" || last.Attempt != 1 || *requests != 2 {
+		t.Fatalf("attempt after replay = %+v after %d requests, want the first attempt delivered", last, *requests)
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/instinctG/tender/internal/model"
+	"unicode/utf8"
+)
+
+// maxWithdrawalReasonLength - предельная длина причины отзыва предложения в символах.
+const maxWithdrawalReasonLength = 1000
+
+func (s *Service) WithdrawBid(bidId string, params model.WithdrawBidParams, body model.WithdrawBidJSONBody) (*model.Bid, error) {
+	if utf8.RuneCountInString(body.Reason) > maxWithdrawalReasonLength {
+		return nil, errors.New("withdrawal reason is too long")
+	}
+
+	bid, err := s.Store.WithdrawBid(bidId, params, body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+func (s *Service) ResubmitBid(bidId string, params model.ResubmitBidParams) (*model.Bid, error) {
+	bid, err := s.Store.ResubmitBid(bidId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+func (s *Service) GetBidWithdrawals(tenderId string, params model.GetBidWithdrawalsParams) ([]*model.BidWithdrawal, error) {
+	withdrawals, err := s.Store.GetBidWithdrawals(tenderId, params)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return withdrawals, nil
+}
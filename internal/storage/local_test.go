@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	local, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local: %v", err)
+	}
+	ctx := context.Background()
+	key := "bids/7/price list.csv"
+
+	if err = local.Put(ctx, key, strings.NewReader("a,b\n1,2\n"), 8, "text/csv"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	body, err := local.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(got) != "a,b\n1,2\n" {
+		t.Fatalf("get returned %q, %v", got, err)
+	}
+
+	if err = local.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err = local.Get(ctx, key); err != ErrNotFound {
+		t.Fatalf("get after delete: %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalRejectsKeysOutsideDir(t *testing.T) {
+	local, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local: %v", err)
+	}
+
+	for _, key := range []string{"", "/", "../outside", "tenders/../../outside"} {
+		if err = local.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("put with key %q was accepted", key)
+		}
+	}
+}
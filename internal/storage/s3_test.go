@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "us-east-1"
+	testBucket    = "attachments"
+)
+
+// fakeS3 - заглушка S3-совместимого хранилища: проверяет подпись Signature V4 каждого запроса
+// и хранит объекты в памяти.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	content     []byte
+	contentType string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	fake := &fakeS3{t: t, objects: make(map[string]fakeObject)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+// expectedAuthorization считает заголовок Authorization для запроса так, как его считает S3.
+func expectedAuthorization(r *http.Request, secretKey string) string {
+	amzDate := r.Header.Get("X-Amz-Date")
+	date := amzDate[:8]
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+
+	canonicalRequest := r.Method + "\n" +
+		r.URL.EscapedPath() + "\n" +
+		r.URL.Query().Encode() + "\n" +
+		"host:" + r.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" +
+		payloadHash
+
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	scope := date + "/" + testRegion + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+	key := mac(mac(mac(mac([]byte("AWS4"+secretKey), date), testRegion), "s3"), "aws4_request")
+
+	return "AWS4-HMAC-SHA256 Credential=" + testAccessKey + "/" + scope +
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=" + hex.EncodeToString(mac(key, stringToSign))
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Amz-Date") == "" || r.Header.Get("Authorization") != expectedAuthorization(r, testSecretKey) {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, "/"+testBucket+"/") {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/"+testBucket+"/")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		content, err := io.ReadAll(r.Body)
+		if err != nil || int64(len(content)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = fakeObject{content: content, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.content)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3(server *httptest.Server, secretKey string) *S3 {
+	return &S3{
+		Endpoint:  server.URL,
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: secretKey,
+		Client:    server.Client(),
+	}
+}
+
+func TestS3RoundTrip(t *testing.T) {
+	fake, server := newFakeS3(t)
+	s3 := newTestS3(server, testSecretKey)
+	ctx := context.Background()
+	key := "tenders/42/отчет за май (1).pdf"
+	content := []byte("%PDF-1.4 attachment")
+
+	if err := s3.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if object := fake.objects[key]; object.contentType != "application/pdf" {
+		t.Fatalf("stored content type = %q", object.contentType)
+	}
+
+	body, err := s3.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || !bytes.Equal(got, content) {
+		t.Fatalf("get returned %q, %v", got, err)
+	}
+
+	if err = s3.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err = s3.Get(ctx, key); err != ErrNotFound {
+		t.Fatalf("get after delete: %v, want ErrNotFound", err)
+	}
+	if err = s3.Delete(ctx, key); err != nil {
+		t.Fatalf("delete of a missing object: %v", err)
+	}
+}
+
+func TestS3SignatureHeaders(t *testing.T) {
+	var captured *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = r
+	}))
+	defer server.Close()
+
+	s3 := newTestS3(server, testSecretKey)
+	s3.Now = func() time.Time { return time.Date(2024, 5, 17, 8, 30, 0, 0, time.FixedZone("MSK", 3*60*60)) }
+	if err := s3.Delete(context.Background(), "bids/1/file.txt"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if date := captured.Header.Get("X-Amz-Date"); date != "20240517T053000Z" {
+		t.Fatalf("X-Amz-Date = %q, want UTC time", date)
+	}
+	if hash := captured.Header.Get("X-Amz-Content-Sha256"); hash != "UNSIGNED-PAYLOAD" {
+		t.Fatalf("X-Amz-Content-Sha256 = %q", hash)
+	}
+	authorization := captured.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential="+testAccessKey+"/20240517/"+testRegion+"/s3/aws4_request, ") {
+		t.Fatalf("unexpected credential scope in %q", authorization)
+	}
+	if authorization != expectedAuthorization(captured, testSecretKey) {
+		t.Fatalf("signature mismatch: %q", authorization)
+	}
+}
+
+func TestS3RejectedSignature(t *testing.T) {
+	_, server := newFakeS3(t)
+	s3 := newTestS3(server, "wrong-secret")
+
+	err := s3.Put(context.Background(), "tenders/1/file.txt", strings.NewReader("data"), 4, "text/plain")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("put with a wrong secret: %v, want 403 error", err)
+	}
+}
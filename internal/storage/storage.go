@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotFound возвращается, когда объекта с таким ключом нет в хранилище.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStorage - хранилище содержимого вложений. Метаданные вложений лежат в базе данных.
+type BlobStorage interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFromEnv создает хранилище по переменной окружения STORAGE_DRIVER: local (по умолчанию) или s3.
+func NewFromEnv() (BlobStorage, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "./data/attachments"
+		}
+		return NewLocal(dir)
+	case "s3":
+		return &S3{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Заголовки, с которыми отправляется каждая доставка.
+const (
+	HeaderDelivery  = "X-Tender-Delivery"
+	HeaderEvent     = "X-Tender-Event"
+	HeaderTimestamp = "X-Tender-Timestamp"
+	HeaderSignature = "X-Tender-Signature"
+)
+
+// MaxAttempts - число неудачных попыток, после которого доставка переходит в состояние Dead.
+const MaxAttempts = 8
+
+const (
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+)
+
+// ErrNonPublicAddress возвращается для адресов подписки во внутренней сети: loopback, частных, link-local
+// и прочих непубличных. Иначе подписка позволила бы обращаться от имени сервиса к внутренним ресурсам.
+var ErrNonPublicAddress = errors.New("webhook url must resolve to a public address")
+
+// publicIP сообщает, можно ли доставлять вебхуки на адрес ip.
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// CheckURL проверяет адрес подписки при регистрации: схема http или https, а все адреса хоста
+// после разрешения имени - публичные.
+func CheckURL(ctx context.Context, rawURL string) error {
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Hostname() == "" {
+		return errors.New("webhook url must be an absolute http or https url")
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, endpoint.Hostname())
+	if err != nil {
+		return fmt.Errorf("webhook host can not be resolved: %w", err)
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return ErrNonPublicAddress
+		}
+	}
+	return nil
+}
+
+// dialPublicOnly проверяет адрес, к которому уже после разрешения имени подключается клиент доставки.
+// Так хост, сменивший адрес после регистрации подписки или при перенаправлении, не уведет запрос во внутреннюю сеть.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return ErrNonPublicAddress
+	}
+	return nil
+}
+
+// Delivery - одна доставка события на адрес подписки.
+type Delivery struct {
+	Id        string
+	EventType string
+	URL       string
+	Secret    string
+	Payload   []byte
+}
+
+// NewSecret генерирует секрет подписки для подписи доставок.
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// Sign возвращает подпись "sha256=<hex>" - HMAC-SHA256 секретом подписки от "<timestamp>.<payload>".
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись доставки на стороне получателя. Доставки с меткой времени старше
+// tolerance отклоняются, чтобы перехваченный запрос нельзя было повторить.
+func Verify(secret, timestamp, signature string, payload []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid webhook timestamp")
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return errors.New("webhook timestamp is outside the tolerance")
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, payload)), []byte(signature)) {
+		return errors.New("webhook signature mismatch")
+	}
+	return nil
+}
+
+// Backoff возвращает паузу перед следующей попыткой после attempt неудачных:
+// 30 секунд, удваиваясь с каждой попыткой, но не больше 6 часов.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := baseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+// Sender подписывает и отправляет доставки. Client можно подменить, например, клиентом httptest.Server.
+type Sender struct {
+	Client *http.Client
+	Now    func() time.Time
+}
+
+// NewSender создает Sender с ограничением времени запроса. Клиент подключается только к публичным адресам
+// и не использует прокси из окружения, иначе проверялся бы адрес прокси, а не получателя.
+func NewSender() *Sender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialPublicOnly,
+	}).DialContext
+	return &Sender{Client: &http.Client{Timeout: 10 * time.Second, Transport: transport}, Now: time.Now}
+}
+
+// Send отправляет доставку POST-запросом и возвращает код ответа. Успешной считается доставка
+// с ответом 2xx, на любой другой ответ возвращается ошибка.
+func (s *Sender) Send(ctx context.Context, d Delivery) (int, error) {
+	timestamp := s.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tender-webhooks/1")
+	req.Header.Set(HeaderDelivery, d.Id)
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, d.Payload))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook receiver responded with %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testSecret = "secret"
+
+// receiver - получатель вебхуков, который проверяет подпись каждой доставки так же, как это делает
+// подписчик, и отвечает кодами из statuses по очереди.
+type receiver struct {
+	t        *testing.T
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	r := &receiver{t: t, statuses: statuses}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.t.Errorf("read body: %v", err)
+	}
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	err = Verify(testSecret, req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderSignature), body, 5*time.Minute,
+		time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func testDelivery(url string) Delivery {
+	return Delivery{
+		Id:        "delivery-1",
+		EventType: "bid.submitted",
+		URL:       url,
+		Secret:    testSecret,
+		Payload:   []byte(`{"id":"event-1"}`),
+	}
+}
+
+func TestSendSignsDelivery(t *testing.T) {
+	r, server := newReceiver(t)
+	sender := &Sender{Client: server.Client(), Now: time.Now}
+
+	statusCode, err := sender.Send(context.Background(), testDelivery(server.URL))
+	if err != nil || statusCode != http.StatusOK {
+		t.Fatalf("send = %d, %v", statusCode, err)
+	}
+
+	req := r.requests[0]
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected request %s with content type %q", req.Method, req.Header.Get("Content-Type"))
+	}
+	if req.Header.Get(HeaderDelivery) != "delivery-1" || req.Header.Get(HeaderEvent) != "bid.submitted" {
+		t.Fatalf("unexpected delivery headers: %v", req.Header)
+	}
+	if string(r.bodies[0]) != `{"id":"event-1"}` {
+		t.Fatalf("payload = %s", r.bodies[0])
+	}
+}
+
+func TestSendRejectedByReceiverWithAnotherSecret(t *testing.T) {
+	_, server := newReceiver(t)
+	sender := &Sender{Client: server.Client(), Now: time.Now}
+	delivery := testDelivery(server.URL)
+	delivery.Secret = "another"
+
+	statusCode, err := sender.Send(context.Background(), delivery)
+	if err == nil || statusCode != http.StatusUnauthorized {
+		t.Fatalf("send with a wrong secret = %d, %v, want 401 error", statusCode, err)
+	}
+}
+
+func TestSendReportsServerErrors(t *testing.T) {
+	_, server := newReceiver(t, http.StatusServiceUnavailable)
+	sender := &Sender{Client: server.Client(), Now: time.Now}
+
+	statusCode, err := sender.Send(context.Background(), testDelivery(server.URL))
+	if err == nil || statusCode != http.StatusServiceUnavailable {
+		t.Fatalf("send to a failing receiver = %d, %v, want 503 error", statusCode, err)
+	}
+}
+
+func TestVerifyRejectsReplayedAndTamperedDeliveries(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"event-1"}`)
+	signature := Sign(testSecret, now.Unix(), payload)
+	timestamp := "1700000000"
+
+	if err := Verify(testSecret, timestamp, signature, payload, 5*time.Minute, now.Add(time.Minute)); err != nil {
+		t.Fatalf("fresh delivery rejected: %v", err)
+	}
+	if err := Verify(testSecret, timestamp, signature, payload, 5*time.Minute, now.Add(time.Hour)); err == nil {
+		t.Fatal("delivery replayed an hour later was accepted")
+	}
+	if err := Verify(testSecret, timestamp, signature, []byte(`{"id":"event-2"}`), 5*time.Minute, now); err == nil {
+		t.Fatal("tampered payload was accepted")
+	}
+	if err := Verify(testSecret, "1700000001", signature, payload, 5*time.Minute, now); err == nil {
+		t.Fatal("tampered timestamp was accepted")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	want := map[int]time.Duration{
+		0:  30 * time.Second,
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		10: 4*time.Hour + 16*time.Minute,
+		12: 6 * time.Hour,
+	}
+	for attempt, delay := range want {
+		if got := Backoff(attempt); got != delay {
+			t.Fatalf("Backoff(%d) = %s, want %s", attempt, got, delay)
+		}
+	}
+}
+
+func TestCheckURLRejectsNonPublicHosts(t *testing.T) {
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://10.1.2.3/hook",
+		"http://172.16.0.1/hook",
+		"http://192.168.0.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"ftp://93.184.216.34/hook",
+		"/hook",
+	} {
+		if err := CheckURL(context.Background(), rawURL); err == nil {
+			t.Errorf("CheckURL(%q) accepted a non-public url", rawURL)
+		}
+	}
+
+	if err := CheckURL(context.Background(), "https://93.184.216.34/hook"); err != nil {
+		t.Fatalf("public address rejected: %v", err)
+	}
+}
+
+func TestNewSenderRefusesNonPublicAddress(t *testing.T) {
+	r, server := newReceiver(t)
+
+	_, err := NewSender().Send(context.Background(), testDelivery(server.URL))
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Fatalf("send to loopback: err = %v, want ErrNonPublicAddress", err)
+	}
+	if len(r.requests) != 0 {
+		t.Fatalf("loopback receiver got %d requests", len(r.requests))
+	}
+}
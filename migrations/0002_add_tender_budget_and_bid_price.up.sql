@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Суммы хранятся в минорных единицах валюты (копейки, центы), валюта - код ISO 4217.
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS budget_amount BIGINT CHECK (budget_amount >= 0),
+    ADD COLUMN IF NOT EXISTS budget_currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS strict_budget BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT tender_budget_currency_check CHECK ((budget_amount IS NULL) = (budget_currency IS NULL));
+
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS price_amount BIGINT CHECK (price_amount >= 0),
+    ADD COLUMN IF NOT EXISTS price_currency VARCHAR(3),
+    ADD CONSTRAINT bid_price_currency_check CHECK ((price_amount IS NULL) = (price_currency IS NULL));
+
+CREATE INDEX IF NOT EXISTS bid_tender_price_idx ON bid (tender_id, price_amount);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS bid_tender_price_idx;
+
+ALTER TABLE bid
+    DROP CONSTRAINT IF EXISTS bid_price_currency_check,
+    DROP COLUMN IF EXISTS price_currency,
+    DROP COLUMN IF EXISTS price_amount;
+
+ALTER TABLE tender
+    DROP CONSTRAINT IF EXISTS tender_budget_currency_check,
+    DROP COLUMN IF EXISTS strict_budget,
+    DROP COLUMN IF EXISTS budget_currency,
+    DROP COLUMN IF EXISTS budget_amount;
+-- +goose StatementEnd
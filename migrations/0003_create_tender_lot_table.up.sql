@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE lot_status AS ENUM (
+    'Open',
+    'Awarded',
+    'Canceled'
+    );
+
+CREATE TABLE IF NOT EXISTS tender_lot (
+                                          id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                          tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                          name VARCHAR(100) NOT NULL,
+                                          description TEXT,
+                                          service_type service_type NOT NULL,
+                                          budget_amount BIGINT CHECK (budget_amount >= 0),
+                                          budget_currency VARCHAR(3),
+                                          status lot_status NOT NULL DEFAULT 'Open',
+                                          awarded_bid_id UUID REFERENCES bid(id),
+                                          created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                          CHECK ((budget_amount IS NULL) = (budget_currency IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS tender_lot_tender_idx ON tender_lot (tender_id);
+
+CREATE TABLE IF NOT EXISTS bid_lot (
+                                       bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                       lot_id UUID NOT NULL REFERENCES tender_lot(id) ON DELETE CASCADE,
+                                       decision decision,
+                                       PRIMARY KEY (bid_id, lot_id)
+);
+
+CREATE INDEX IF NOT EXISTS bid_lot_lot_idx ON bid_lot (lot_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bid_lot;
+
+DROP TABLE IF EXISTS tender_lot;
+
+DROP TYPE IF EXISTS lot_status;
+-- +goose StatementEnd
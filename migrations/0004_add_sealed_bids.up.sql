@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS sealed BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS revealed_at TIMESTAMPTZ;
+
+-- Пока тендер запечатан, описание и цена предложения хранятся только в зашифрованном виде.
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS sealed_payload BYTEA;
+
+CREATE INDEX IF NOT EXISTS tender_sealed_deadline_idx ON tender (submission_deadline)
+    WHERE sealed AND revealed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS bid_reveal_log (
+                                              id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                              tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                              reason VARCHAR(20) NOT NULL,
+                                              revealed_by VARCHAR(50) REFERENCES employee(username),
+                                              bid_count INT NOT NULL,
+                                              revealed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bid_reveal_log;
+
+DROP INDEX IF EXISTS tender_sealed_deadline_idx;
+
+ALTER TABLE bid
+    DROP COLUMN IF EXISTS sealed_payload;
+
+ALTER TABLE tender
+    DROP COLUMN IF EXISTS revealed_at,
+    DROP COLUMN IF EXISTS submission_deadline,
+    DROP COLUMN IF EXISTS sealed;
+-- +goose StatementEnd
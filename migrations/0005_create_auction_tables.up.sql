@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tender_auction (
+                                              tender_id UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+                                              starts_at TIMESTAMPTZ NOT NULL,
+                                              ends_at TIMESTAMPTZ NOT NULL,
+                                              min_decrement BIGINT NOT NULL CHECK (min_decrement > 0),
+                                              currency VARCHAR(3) NOT NULL,
+                                              extension_window_seconds INT NOT NULL DEFAULT 0 CHECK (extension_window_seconds >= 0),
+                                              extension_seconds INT NOT NULL DEFAULT 0 CHECK (extension_seconds >= 0),
+                                              best_amount BIGINT,
+                                              created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+                                              CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS auction_offer (
+                                             id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                             tender_id UUID NOT NULL REFERENCES tender_auction(tender_id) ON DELETE CASCADE,
+                                             bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                             amount BIGINT NOT NULL CHECK (amount >= 0),
+                                             created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS auction_offer_tender_idx ON auction_offer (tender_id, amount);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS auction_offer;
+
+DROP TABLE IF EXISTS tender_auction;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE criterion_kind AS ENUM (
+    'Price',
+    'DeliveryTime',
+    'Experience',
+    'Custom'
+    );
+
+CREATE TABLE IF NOT EXISTS evaluation_criterion (
+                                                    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                                    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                                    name VARCHAR(100) NOT NULL,
+                                                    kind criterion_kind NOT NULL,
+                                                    weight INT NOT NULL CHECK (weight > 0),
+                                                    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS evaluation_criterion_tender_idx ON evaluation_criterion (tender_id);
+
+CREATE TABLE IF NOT EXISTS evaluation_score (
+                                                bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                                criterion_id UUID NOT NULL REFERENCES evaluation_criterion(id) ON DELETE CASCADE,
+                                                evaluator_id UUID NOT NULL REFERENCES employee(id),
+                                                score NUMERIC(5, 2) NOT NULL CHECK (score >= 0 AND score <= 100),
+                                                comment TEXT,
+                                                updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                PRIMARY KEY (bid_id, criterion_id, evaluator_id)
+);
+
+-- Оценки замораживаются при первом решении по предложениям тендера.
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS evaluation_locked_at TIMESTAMPTZ;
+
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS decision_rationale TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bid
+    DROP COLUMN IF EXISTS decision_rationale;
+
+ALTER TABLE tender
+    DROP COLUMN IF EXISTS evaluation_locked_at;
+
+DROP TABLE IF EXISTS evaluation_score;
+
+DROP TABLE IF EXISTS evaluation_criterion;
+
+DROP TYPE IF EXISTS criterion_kind;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tender_question (
+                                               id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                               tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                               asker_id UUID NOT NULL REFERENCES employee(id),
+                                               question TEXT NOT NULL,
+                                               answer TEXT,
+                                               answered_by UUID REFERENCES employee(id),
+                                               answered_at TIMESTAMP,
+                                               is_public BOOLEAN NOT NULL DEFAULT FALSE,
+                                               created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS tender_question_tender_idx ON tender_question (tender_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tender_question;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE attachment_parent AS ENUM (
+    'Tender',
+    'Bid'
+    );
+
+CREATE TABLE IF NOT EXISTS attachment (
+                                          id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                          parent_type attachment_parent NOT NULL,
+                                          parent_id UUID NOT NULL,
+                                          file_name VARCHAR(255) NOT NULL,
+                                          content_type VARCHAR(100) NOT NULL,
+                                          size BIGINT NOT NULL CHECK (size >= 0),
+                                          sha256 CHAR(64) NOT NULL,
+                                          storage_key VARCHAR(512) NOT NULL UNIQUE,
+                                          uploaded_by UUID NOT NULL REFERENCES employee(id),
+                                          created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS attachment_parent_idx ON attachment (parent_type, parent_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS attachment;
+DROP TYPE IF EXISTS attachment_parent;
+-- +goose StatementEnd
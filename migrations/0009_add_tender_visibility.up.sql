@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE tender_visibility AS ENUM (
+    'Public',
+    'InviteOnly'
+    );
+
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS visibility tender_visibility NOT NULL DEFAULT 'Public';
+
+CREATE TABLE IF NOT EXISTS tender_invitation (
+                                                 tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                                 organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+                                                 invited_by UUID NOT NULL REFERENCES employee(id),
+                                                 created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                 PRIMARY KEY (tender_id, organization_id)
+);
+
+CREATE INDEX IF NOT EXISTS tender_invitation_organization_idx ON tender_invitation (organization_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tender_invitation;
+ALTER TABLE tender DROP COLUMN IF EXISTS visibility;
+DROP TYPE IF EXISTS tender_visibility;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tender_template (
+                                               id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                               organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+                                               name VARCHAR(100) NOT NULL,
+                                               payload JSONB NOT NULL,
+                                               created_by UUID NOT NULL REFERENCES employee(id),
+                                               created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS tender_template_organization_idx ON tender_template (organization_id, name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tender_template;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE status ADD VALUE IF NOT EXISTS 'Canceled';
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'Void';
+
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS cancel_reason TEXT,
+    ADD COLUMN IF NOT EXISTS canceled_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS notification (
+                                            id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                            recipient_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+                                            kind VARCHAR(50) NOT NULL,
+                                            tender_id UUID REFERENCES tender(id) ON DELETE CASCADE,
+                                            bid_id UUID REFERENCES bid(id) ON DELETE CASCADE,
+                                            payload JSONB NOT NULL DEFAULT '{}',
+                                            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notification_recipient_idx ON notification (recipient_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification;
+ALTER TABLE tender
+    DROP COLUMN IF EXISTS canceled_at,
+    DROP COLUMN IF EXISTS cancel_reason;
+-- +goose StatementEnd
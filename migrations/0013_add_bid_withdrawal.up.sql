@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE bid_withdrawal_action AS ENUM (
+    'Withdrawn',
+    'Resubmitted'
+    );
+
+CREATE TABLE IF NOT EXISTS bid_withdrawal (
+                                              id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                              bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                              tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                              action bid_withdrawal_action NOT NULL,
+                                              reason TEXT,
+                                              performed_by UUID NOT NULL REFERENCES employee(id),
+                                              created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS bid_withdrawal_tender_idx ON bid_withdrawal (tender_id, created_at);
+
+-- Изменение данных: уникальный индекс ниже не создастся, пока у автора несколько действующих предложений
+-- на тендер. Поэтому из уже поданных дублей действующим остается только самое новое предложение, остальные
+-- переводятся в Canceled без записи в bid_withdrawal. Число отмененных дублей выводится в NOTICE.
+DO $$
+DECLARE
+    canceled integer;
+BEGIN
+    UPDATE bid SET status = 'Canceled'
+    WHERE id IN (
+        SELECT id
+        FROM (
+            SELECT id, row_number() OVER (PARTITION BY tender_id, author_type, author_id ORDER BY created_at DESC, id) AS n
+            FROM bid
+            WHERE status IN ('Created', 'Published')
+        ) ranked
+        WHERE n > 1
+    );
+    GET DIAGNOSTICS canceled = ROW_COUNT;
+    IF canceled > 0 THEN
+        RAISE NOTICE 'bid_withdrawal: % duplicate active bids canceled', canceled;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS bid_one_active_per_author_idx ON bid (tender_id, author_type, author_id)
+    WHERE status IN ('Created', 'Published');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS bid_one_active_per_author_idx;
+DROP TABLE IF EXISTS bid_withdrawal;
+DROP TYPE IF EXISTS bid_withdrawal_action;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS organization_blocklist (
+                                                      organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+                                                      blocked_organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+                                                      reason TEXT,
+                                                      created_by UUID NOT NULL REFERENCES employee(id),
+                                                      created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                      PRIMARY KEY (organization_id, blocked_organization_id),
+                                                      CHECK (organization_id <> blocked_organization_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS organization_blocklist;
+-- +goose StatementEnd
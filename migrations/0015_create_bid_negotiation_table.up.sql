@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE negotiation_party AS ENUM (
+    'Buyer',
+    'Bidder'
+    );
+
+CREATE TYPE negotiation_status AS ENUM (
+    'Open',
+    'Accepted',
+    'Declined',
+    'Countered'
+    );
+
+CREATE TABLE IF NOT EXISTS bid_negotiation_round (
+                                                     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                                     bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                                     round INT NOT NULL,
+                                                     party negotiation_party NOT NULL,
+                                                     price_amount BIGINT CHECK (price_amount >= 0),
+                                                     price_currency VARCHAR(3),
+                                                     terms TEXT,
+                                                     status negotiation_status NOT NULL DEFAULT 'Open',
+                                                     bid_version INT,
+                                                     created_by UUID NOT NULL REFERENCES employee(id),
+                                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                     responded_at TIMESTAMP,
+                                                     UNIQUE (bid_id, round),
+                                                     CHECK ((price_amount IS NULL) = (price_currency IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS bid_negotiation_one_open_idx ON bid_negotiation_round (bid_id)
+    WHERE status = 'Open';
+
+-- Предыдущие версии предложений, замененные принятым встречным предложением, хранятся для аудита.
+CREATE TABLE IF NOT EXISTS bid_version (
+                                           bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                           version INT NOT NULL,
+                                           name VARCHAR(100) NOT NULL,
+                                           description TEXT,
+                                           price_amount BIGINT,
+                                           price_currency VARCHAR(3),
+                                           recorded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                           PRIMARY KEY (bid_id, version)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bid_version;
+DROP TABLE IF EXISTS bid_negotiation_round;
+DROP TYPE IF EXISTS negotiation_status;
+DROP TYPE IF EXISTS negotiation_party;
+-- +goose StatementEnd
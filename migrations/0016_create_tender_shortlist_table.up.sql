@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Раунд BAFO открыт, пока не наступил bafo_deadline, после этого считается закрытым.
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS bafo_opened_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS bafo_deadline TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS tender_shortlist (
+                                                tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                                bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                                created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                bafo_submitted_at TIMESTAMP,
+                                                bafo_version INT,
+                                                PRIMARY KEY (tender_id, bid_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tender_shortlist;
+ALTER TABLE tender
+    DROP COLUMN IF EXISTS bafo_deadline,
+    DROP COLUMN IF EXISTS bafo_opened_at;
+-- +goose StatementEnd
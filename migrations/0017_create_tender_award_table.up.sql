@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Запись о присуждении создается при принятии предложения и фиксирует версию и цену предложения на этот момент.
+CREATE TABLE IF NOT EXISTS tender_award (
+                                            id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                            tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                            lot_id UUID REFERENCES tender_lot(id) ON DELETE CASCADE,
+                                            bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                            bid_version INT NOT NULL,
+                                            price_amount BIGINT,
+                                            price_currency VARCHAR(3),
+                                            buyer_organization_id UUID NOT NULL REFERENCES organization(id),
+                                            supplier_type author_type NOT NULL,
+                                            supplier_id UUID NOT NULL,
+                                            rationale TEXT,
+                                            awarded_by UUID NOT NULL REFERENCES employee(id),
+                                            awarded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tender_award_tender_lot_idx
+    ON tender_award (tender_id, COALESCE(lot_id, '00000000-0000-0000-0000-000000000000'));
+CREATE INDEX IF NOT EXISTS tender_award_buyer_idx ON tender_award (buyer_organization_id, awarded_at);
+CREATE INDEX IF NOT EXISTS tender_award_supplier_idx ON tender_award (supplier_id, awarded_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tender_award;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE award_share_kind AS ENUM (
+    'Percentage',
+    'Quantity'
+    );
+
+-- Набор присуждений собирается черновиком и вступает в силу только после финализации владельцем тендера.
+CREATE TABLE IF NOT EXISTS tender_award_set (
+                                                tender_id UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+                                                kind award_share_kind NOT NULL,
+                                                total_quantity BIGINT CHECK (total_quantity > 0),
+                                                created_by UUID NOT NULL REFERENCES employee(id),
+                                                created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                finalized_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tender_award_share (
+                                                  tender_id UUID NOT NULL REFERENCES tender_award_set(tender_id) ON DELETE CASCADE,
+                                                  lot_id UUID REFERENCES tender_lot(id) ON DELETE CASCADE,
+                                                  bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                                  share NUMERIC(14, 2) NOT NULL CHECK (share > 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tender_award_share_bid_idx
+    ON tender_award_share (tender_id, COALESCE(lot_id, '00000000-0000-0000-0000-000000000000'), bid_id);
+
+ALTER TABLE tender_award
+    ADD COLUMN IF NOT EXISTS share_kind award_share_kind,
+    ADD COLUMN IF NOT EXISTS share NUMERIC(14, 2);
+
+DROP INDEX IF EXISTS tender_award_tender_lot_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS tender_award_tender_lot_bid_idx
+    ON tender_award (tender_id, COALESCE(lot_id, '00000000-0000-0000-0000-000000000000'), bid_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS tender_award_tender_lot_bid_idx;
+ALTER TABLE tender_award
+    DROP COLUMN IF EXISTS share,
+    DROP COLUMN IF EXISTS share_kind;
+DROP TABLE IF EXISTS tender_award_share;
+DROP TABLE IF EXISTS tender_award_set;
+DROP TYPE IF EXISTS award_share_kind;
+-- +goose StatementEnd
@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS bid_review (
+                                          id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                          bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+                                          tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                          reviewer_id UUID NOT NULL REFERENCES employee(id),
+                                          description TEXT NOT NULL,
+                                          rating INT CHECK (rating BETWEEN 1 AND 5),
+                                          created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS bid_review_bid_idx ON bid_review (bid_id);
+
+ALTER TABLE tender_award
+    ADD COLUMN IF NOT EXISTS canceled_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS cancel_reason TEXT,
+    ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS delivered_on_time BOOLEAN;
+
+-- Счетчики репутации поставщика (сотрудника-автора предложений или организации, за которую он отвечает)
+-- обновляются приращениями при каждом событии, поэтому чтение не требует пересчета.
+CREATE TABLE IF NOT EXISTS supplier_reputation (
+                                                   subject_type author_type NOT NULL,
+                                                   subject_id UUID NOT NULL,
+                                                   review_count INT NOT NULL DEFAULT 0,
+                                                   review_score_sum INT NOT NULL DEFAULT 0,
+                                                   bids_decided INT NOT NULL DEFAULT 0,
+                                                   bids_won INT NOT NULL DEFAULT 0,
+                                                   awards INT NOT NULL DEFAULT 0,
+                                                   awards_canceled INT NOT NULL DEFAULT 0,
+                                                   deliveries INT NOT NULL DEFAULT 0,
+                                                   deliveries_on_time INT NOT NULL DEFAULT 0,
+                                                   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                   PRIMARY KEY (subject_type, subject_id)
+);
+
+-- Начальные значения считаются один раз по уже принятым решениям и присуждениям. Автор предложения -
+-- всегда сотрудник, поэтому счетчики получают он сам и организации, за которые он отвечает, независимо
+-- от author_type.
+INSERT INTO supplier_reputation (subject_type, subject_id, bids_decided, bids_won, awards)
+SELECT s.subject_type, s.subject_id,
+       count(DISTINCT b.id) FILTER (WHERE b.decision IS NOT NULL),
+       count(DISTINCT b.id) FILTER (WHERE b.decision = 'Accepted'),
+       count(DISTINCT a.id)
+FROM bid b
+CROSS JOIN LATERAL (
+    SELECT 'User'::author_type AS subject_type, b.author_id AS subject_id
+    UNION
+    SELECT 'Organization'::author_type, r.organization_id
+    FROM organization_responsible r
+    WHERE r.user_id = b.author_id
+) s
+LEFT JOIN tender_award a ON a.bid_id = b.id
+GROUP BY s.subject_type, s.subject_id
+ON CONFLICT (subject_type, subject_id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS supplier_reputation;
+ALTER TABLE tender_award
+    DROP COLUMN IF EXISTS delivered_on_time,
+    DROP COLUMN IF EXISTS delivered_at,
+    DROP COLUMN IF EXISTS cancel_reason,
+    DROP COLUMN IF EXISTS canceled_at;
+DROP TABLE IF EXISTS bid_review;
+-- +goose StatementEnd
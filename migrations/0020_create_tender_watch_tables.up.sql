@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tender_watch (
+                                            user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+                                            tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                            PRIMARY KEY (user_id, tender_id)
+);
+
+CREATE INDEX IF NOT EXISTS tender_watch_tender_idx ON tender_watch (tender_id);
+
+-- Сохраненный поиск хранит те же параметры, что и GET /api/tenders.
+CREATE TABLE IF NOT EXISTS saved_search (
+                                            id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                            user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+                                            name VARCHAR(100) NOT NULL,
+                                            service_types TEXT[] NOT NULL DEFAULT '{}',
+                                            search_query TEXT,
+                                            organization_id UUID,
+                                            created_from TIMESTAMPTZ,
+                                            created_to TIMESTAMPTZ,
+                                            budget_min BIGINT,
+                                            budget_max BIGINT,
+                                            currency VARCHAR(3),
+                                            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS saved_search_user_idx ON saved_search (user_id, created_at);
+
+-- Совпадение фиксируется один раз, чтобы правки тендера не порождали повторных оповещений.
+CREATE TABLE IF NOT EXISTS saved_search_match (
+                                                  search_id UUID NOT NULL REFERENCES saved_search(id) ON DELETE CASCADE,
+                                                  tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+                                                  matched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                  PRIMARY KEY (search_id, tender_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS saved_search_match;
+DROP TABLE IF EXISTS saved_search;
+DROP TABLE IF EXISTS tender_watch;
+-- +goose StatementEnd
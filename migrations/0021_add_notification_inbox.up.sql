@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification ADD COLUMN IF NOT EXISTS read_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS notification_unread_idx ON notification (recipient_id) WHERE read_at IS NULL;
+
+-- Отсутствие строки означает, что уведомления этого вида доставляются.
+CREATE TABLE IF NOT EXISTS notification_preference (
+                                                       user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+                                                       kind VARCHAR(50) NOT NULL,
+                                                       enabled BOOLEAN NOT NULL,
+                                                       updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                                       PRIMARY KEY (user_id, kind)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_preference;
+DROP INDEX IF EXISTS notification_unread_idx;
+ALTER TABLE notification DROP COLUMN IF EXISTS read_at;
+-- +goose StatementEnd
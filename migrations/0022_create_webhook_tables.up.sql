@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhook_endpoint (
+                                                id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                                organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+                                                url TEXT NOT NULL,
+                                                secret TEXT NOT NULL,
+                                                event_types TEXT[] NOT NULL DEFAULT '{}',
+                                                created_by UUID NOT NULL REFERENCES employee(id),
+                                                created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_endpoint_organization_idx ON webhook_endpoint (organization_id);
+
+CREATE TYPE webhook_delivery_status AS ENUM (
+    'Pending',
+    'Delivered',
+    'Dead'
+    );
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+                                                id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                                endpoint_id UUID NOT NULL REFERENCES webhook_endpoint(id) ON DELETE CASCADE,
+                                                event_id UUID NOT NULL,
+                                                event_type VARCHAR(50) NOT NULL,
+                                                payload JSONB NOT NULL,
+                                                status webhook_delivery_status NOT NULL DEFAULT 'Pending',
+                                                attempts INT NOT NULL DEFAULT 0,
+                                                next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                                last_status_code INT,
+                                                last_error TEXT,
+                                                delivered_at TIMESTAMPTZ,
+                                                created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_due_idx ON webhook_delivery (next_attempt_at) WHERE status = 'Pending';
+CREATE INDEX IF NOT EXISTS webhook_delivery_endpoint_idx ON webhook_delivery (endpoint_id, created_at);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempt (
+                                                        delivery_id UUID NOT NULL REFERENCES webhook_delivery(id) ON DELETE CASCADE,
+                                                        attempt INT NOT NULL,
+                                                        status_code INT,
+                                                        error TEXT,
+                                                        duration_ms BIGINT NOT NULL,
+                                                        attempted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                                        PRIMARY KEY (delivery_id, attempt)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_delivery_attempt;
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TYPE IF EXISTS webhook_delivery_status;
+DROP TABLE IF EXISTS webhook_endpoint;
+-- +goose StatementEnd
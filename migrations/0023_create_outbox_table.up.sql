@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Номер публикации выдается под блокировкой непосредственно перед фиксацией транзакции реле, поэтому номера
+-- растут в порядке фиксации и служат курсором потока событий: BIGSERIAL id назначается при записи события,
+-- и событие, опубликованное позже события с большим id, курсор по id пропустил бы.
+CREATE SEQUENCE IF NOT EXISTS outbox_publish_seq;
+
+CREATE TABLE IF NOT EXISTS outbox_event (
+                                            id BIGSERIAL PRIMARY KEY,
+                                            event_id UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+                                            event_type VARCHAR(50) NOT NULL,
+                                            tender_id UUID NOT NULL,
+                                            bid_id UUID,
+                                            payload JSONB NOT NULL,
+                                            created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                            published_at TIMESTAMPTZ,
+                                            publish_seq BIGINT,
+                                            published_sinks TEXT[] NOT NULL DEFAULT '{}',
+                                            attempts INT NOT NULL DEFAULT 0,
+                                            last_error TEXT,
+                                            failed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_event_unpublished_idx ON outbox_event (id)
+    WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS outbox_event_publish_seq_idx ON outbox_event (publish_seq);
+CREATE INDEX IF NOT EXISTS outbox_event_tender_publish_seq_idx ON outbox_event (tender_id, publish_seq)
+    WHERE publish_seq IS NOT NULL;
+
+-- Повторная передача события из outbox не должна порождать вторую доставку вебхука.
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_delivery_event_idx ON webhook_delivery (endpoint_id, event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS webhook_delivery_event_idx;
+DROP TABLE IF EXISTS outbox_event;
+DROP SEQUENCE IF EXISTS outbox_publish_seq;
+-- +goose StatementEnd
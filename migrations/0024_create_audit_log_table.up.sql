@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS audit_log (
+                                         id BIGSERIAL PRIMARY KEY,
+                                         request_id VARCHAR(100) NOT NULL DEFAULT '',
+                                         actor_id UUID,
+                                         actor_username VARCHAR(50) NOT NULL DEFAULT '',
+                                         actor_organization_ids UUID[] NOT NULL DEFAULT '{}',
+                                         action VARCHAR(50) NOT NULL,
+                                         entity_type VARCHAR(50) NOT NULL,
+                                         entity_id VARCHAR(100) NOT NULL,
+                                         changes JSONB NOT NULL DEFAULT '{}',
+                                         created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_organizations_idx ON audit_log USING GIN (actor_organization_ids);
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity_type, entity_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_created_idx ON audit_log (created_at);
+
+-- Журнал только пополняется: изменить или удалить запись нельзя.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+-- +goose StatementEnd